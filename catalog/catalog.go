@@ -0,0 +1,224 @@
+// Package catalog is the built-in catalog of common developer tools checked by pre-req: their
+// version command, the regular expression reading its output, the installation instructions of
+// each operating system and the packages setup installs them with.
+//
+// The catalog is embedded from catalog.yaml and versioned with it. The configuration references
+// entries by id, either in the apps list or as the catalog of an application whose other fields
+// override the entry:
+//
+//	apps: [kubectl, helm]
+//	applications:
+//	  - catalog: terraform
+//	    recommendedVersion: "~1.9"
+package catalog
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fabianoflorentino/whiterose/output"
+	"github.com/fabianoflorentino/whiterose/utils"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed catalog.yaml
+var data []byte
+
+// file is the content of catalog.yaml.
+type file struct {
+	Version  int      `yaml:"version"`
+	Defaults []string `yaml:"defaults"`
+	Tools    []Entry  `yaml:"tools"`
+}
+
+// Entry is a tool of the catalog.
+type Entry struct {
+	ID            string `json:"id" yaml:"id"`
+	utils.AppInfo `yaml:",inline"`
+}
+
+// builtin is the parsed catalog; catalog_test.go checks that catalog.yaml parses.
+var builtin = mustParse(data)
+
+func mustParse(data []byte) file {
+	var f file
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		panic(fmt.Sprintf("invalid embedded catalog: %v", err))
+	}
+	return f
+}
+
+// Version returns the version of the built-in catalog.
+func Version() int {
+	return builtin.Version
+}
+
+// Lookup returns the application of the entry whose id, or else command, is id, with its
+// Catalog set to the id of the entry.
+func Lookup(id string) (utils.AppInfo, bool) {
+	for _, e := range builtin.Tools {
+		if strings.EqualFold(e.ID, id) {
+			return e.app(), true
+		}
+	}
+	for _, e := range builtin.Tools {
+		if strings.EqualFold(e.Command, id) {
+			return e.app(), true
+		}
+	}
+	return utils.AppInfo{}, false
+}
+
+// app returns a copy of the application of e, so that callers cannot change the catalog.
+func (e Entry) app() utils.AppInfo {
+	app := clone(e.AppInfo)
+	app.Catalog = e.ID
+	return app
+}
+
+// Defaults returns the applications checked when the configuration has none: Git, Go and Docker.
+func Defaults() []utils.AppInfo {
+	apps := make([]utils.AppInfo, 0, len(builtin.Defaults))
+	for _, id := range builtin.Defaults {
+		app, _ := Lookup(id)
+		apps = append(apps, app)
+	}
+	return apps
+}
+
+// Resolve returns the applications of the configuration: the catalog entries of ids, then apps.
+// An application with a catalog id is its entry with the fields the application sets replacing
+// those of the entry, see Merge; it takes the place of the same entry listed in ids.
+func Resolve(ids []string, apps []utils.AppInfo) ([]utils.AppInfo, error) {
+	resolved := make([]utils.AppInfo, 0, len(ids)+len(apps))
+	for _, id := range ids {
+		app, ok := Lookup(id)
+		if !ok {
+			return nil, unknownError(id)
+		}
+		resolved = append(resolved, app)
+	}
+
+	for _, app := range apps {
+		if app.Catalog == "" {
+			resolved = append(resolved, app)
+			continue
+		}
+
+		base, ok := Lookup(app.Catalog)
+		if !ok {
+			return nil, unknownError(app.Catalog)
+		}
+		merged := Merge(base, app)
+
+		replaced := false
+		for i, r := range resolved {
+			if r.Catalog == base.Catalog {
+				resolved[i], replaced = merged, true
+				break
+			}
+		}
+		if !replaced {
+			resolved = append(resolved, merged)
+		}
+	}
+
+	return resolved, nil
+}
+
+// Merge returns base with the fields set in override. Installation instructions are merged by
+// operating system; the other fields, including install and healthCheck, are replaced as a whole.
+func Merge(base, override utils.AppInfo) utils.AppInfo {
+	merged := clone(base)
+	if override.Name != "" {
+		merged.Name = override.Name
+	}
+	if override.Command != "" {
+		merged.Command = override.Command
+	}
+	if override.VersionFlag != "" {
+		merged.VersionFlag = override.VersionFlag
+	}
+	if override.RecommendedVersion != "" {
+		merged.RecommendedVersion = override.RecommendedVersion
+	}
+	if override.VersionRegex != "" {
+		merged.VersionRegex = override.VersionRegex
+	}
+	if override.Timeout != "" {
+		merged.Timeout = override.Timeout
+	}
+	if override.DependsOn != nil {
+		merged.DependsOn = override.DependsOn
+	}
+	if override.HealthCheck != nil {
+		merged.HealthCheck = override.HealthCheck
+	}
+	if override.Install != nil {
+		merged.Install = override.Install
+	}
+	for os, instruction := range override.InstallInstructions {
+		if merged.InstallInstructions == nil {
+			merged.InstallInstructions = map[string]string{}
+		}
+		merged.InstallInstructions[os] = instruction
+	}
+	return merged
+}
+
+// clone copies app, so that callers cannot change the catalog through its maps.
+func clone(app utils.AppInfo) utils.AppInfo {
+	if app.InstallInstructions != nil {
+		instructions := make(map[string]string, len(app.InstallInstructions))
+		for os, instruction := range app.InstallInstructions {
+			instructions[os] = instruction
+		}
+		app.InstallInstructions = instructions
+	}
+	return app
+}
+
+func unknownError(id string) error {
+	return fmt.Errorf("unknown catalog application %q, available: %s", id, strings.Join(IDs(), ", "))
+}
+
+// IDs returns the ids of the catalog, in catalog order.
+func IDs() []string {
+	ids := make([]string, 0, len(builtin.Tools))
+	for _, e := range builtin.Tools {
+		ids = append(ids, e.ID)
+	}
+	return ids
+}
+
+// List is the content of the catalog, for the pre-req --catalog command.
+type List struct {
+	Version int     `json:"version"`
+	Tools   []Entry `json:"tools"`
+}
+
+// Entries returns the content of the catalog.
+func Entries() List {
+	return List{Version: builtin.Version, Tools: append([]Entry{}, builtin.Tools...)}
+}
+
+// Text writes one tool per line.
+func (l List) Text(w io.Writer) {
+	fmt.Fprintf(w, "📚 Application catalog (version %d):\n", l.Version)
+	fmt.Fprintf(w, "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	for _, e := range l.Tools {
+		fmt.Fprintf(w, "%-14s %s (command: %s)\n", e.ID, e.Name, e.Command)
+	}
+	fmt.Fprintf(w, "\n")
+}
+
+// Table lays the tools out one per row.
+func (l List) Table() output.Table {
+	t := output.Table{Header: []string{"ID", "APP", "COMMAND", "VERSION FLAG"}}
+	for _, e := range l.Tools {
+		t.Rows = append(t.Rows, []string{e.ID, e.Name, e.Command, e.VersionFlag})
+	}
+	return t
+}
@@ -0,0 +1,179 @@
+package catalog
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/fabianoflorentino/whiterose/semver"
+	"github.com/fabianoflorentino/whiterose/utils"
+)
+
+func TestCatalog(t *testing.T) {
+	if Version() < 1 {
+		t.Errorf("Version() = %d", Version())
+	}
+
+	seen := map[string]bool{}
+	for _, e := range Entries().Tools {
+		if e.ID == "" || e.Name == "" || e.Command == "" || e.VersionFlag == "" {
+			t.Errorf("incomplete entry %+v", e)
+		}
+		if seen[e.ID] {
+			t.Errorf("duplicate id %q", e.ID)
+		}
+		seen[e.ID] = true
+
+		if e.VersionRegex != "" {
+			if _, err := regexp.Compile(e.VersionRegex); err != nil {
+				t.Errorf("%s: %v", e.ID, err)
+			}
+		}
+		if e.RecommendedVersion != "" {
+			if _, err := semver.ParseConstraint(e.RecommendedVersion); err != nil {
+				t.Errorf("%s: %v", e.ID, err)
+			}
+		}
+		for _, os := range []string{"linux", "darwin", "windows"} {
+			if e.InstallInstructions[os] == "" {
+				t.Errorf("%s has no installation instructions for %s", e.ID, os)
+			}
+		}
+	}
+
+	for _, want := range []string{"kubectl", "helm", "terraform", "node", "python", "make", "gh", "jq"} {
+		if !seen[want] {
+			t.Errorf("catalog has no %s", want)
+		}
+	}
+	if got := Defaults(); len(got) != 3 || got[0].Name != "Git" || got[1].Name != "Go" || got[2].Name != "Docker" {
+		t.Errorf("Defaults() = %+v", got)
+	}
+}
+
+func TestCatalog_VersionRegex(t *testing.T) {
+	// Output of the version commands of the entries with a versionRegex.
+	outputs := map[string]string{
+		"kubectl":       "Client Version: v1.30.2\nKustomize Version: v5.0.4-0.20230601165947-6ce0bf390ce3",
+		"helm":          "v3.15.2+g1a500d5",
+		"terraform":     "Terraform v1.9.2\non linux_amd64",
+		"gh":            "gh version 2.55.0 (2024-08-20)\nhttps://github.com/cli/cli/releases/tag/v2.55.0",
+		"yq":            "yq (https://github.com/mikefarah/yq/) version v4.44.3",
+		"golangci-lint": "golangci-lint has version 1.60.1 built with go1.23.0 from 1a0d8d7 on 2024-08-19",
+		"aws":           "aws-cli/2.17.20 Python/3.11.9 Linux/6.8.0 exe/x86_64.ubuntu.24",
+	}
+	want := map[string]string{
+		"kubectl": "1.30.2", "helm": "3.15.2", "terraform": "1.9.2", "gh": "2.55.0",
+		"yq": "4.44.3", "golangci-lint": "1.60.1", "aws": "2.17.20",
+	}
+
+	for _, e := range Entries().Tools {
+		if e.VersionRegex == "" {
+			continue
+		}
+		out, ok := outputs[e.ID]
+		if !ok {
+			t.Errorf("no sample output for %s", e.ID)
+			continue
+		}
+		v, err := semver.Extract(out, e.VersionRegex)
+		if err != nil || v.String() != want[e.ID] {
+			t.Errorf("%s: Extract() = %v, %v, want %s", e.ID, v, err, want[e.ID])
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	app, ok := Lookup("Python3")
+	if !ok || app.Catalog != "python" || app.Name != "Python" {
+		t.Errorf("Lookup(python3) = %+v, %v", app, ok)
+	}
+
+	app.InstallInstructions["linux"] = "changed"
+	if again, _ := Lookup("python"); again.InstallInstructions["linux"] == "changed" {
+		t.Error("Lookup() returned the map of the catalog")
+	}
+
+	if _, ok := Lookup("unknown"); ok {
+		t.Error("Lookup(unknown) found an entry")
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name    string
+		ids     []string
+		apps    []utils.AppInfo
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "ids then applications",
+			ids:  []string{"kubectl", "helm"},
+			apps: []utils.AppInfo{{Name: "Custom", Command: "custom"}},
+			want: []string{"kubectl/kubectl/version --client", "Helm/helm/version --short", "Custom/custom/"},
+		},
+		{
+			name: "override replaces the listed entry",
+			ids:  []string{"kubectl", "helm"},
+			apps: []utils.AppInfo{{Catalog: "kubectl", VersionFlag: "version --client --output=yaml"}},
+			want: []string{"kubectl/kubectl/version --client --output=yaml", "Helm/helm/version --short"},
+		},
+		{
+			name: "override without id",
+			apps: []utils.AppInfo{{Catalog: "python", Name: "Python 3.12", Command: "python3.12"}},
+			want: []string{"Python 3.12/python3.12/--version"},
+		},
+		{
+			name:    "unknown id",
+			ids:     []string{"kubectl", "kubeclt"},
+			wantErr: `unknown catalog application "kubeclt"`,
+		},
+		{
+			name:    "unknown catalog of an application",
+			apps:    []utils.AppInfo{{Catalog: "nope"}},
+			wantErr: `unknown catalog application "nope"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apps, err := Resolve(tt.ids, tt.apps)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Resolve() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+
+			var got []string
+			for _, app := range apps {
+				got = append(got, app.Name+"/"+app.Command+"/"+app.VersionFlag)
+			}
+			if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+				t.Errorf("Resolve() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	base, _ := Lookup("go")
+	merged := Merge(base, utils.AppInfo{
+		RecommendedVersion:  "~1.24",
+		InstallInstructions: map[string]string{"linux": "sudo snap install go --classic"},
+	})
+
+	if merged.RecommendedVersion != "~1.24" || merged.Command != "go" || merged.Install == nil || merged.Install.Mise != "go" {
+		t.Errorf("Merge() = %+v", merged)
+	}
+	if merged.InstallInstructions["linux"] != "sudo snap install go --classic" || merged.InstallInstructions["darwin"] != "brew install go" {
+		t.Errorf("Merge().InstallInstructions = %v", merged.InstallInstructions)
+	}
+	if base.InstallInstructions["linux"] == "sudo snap install go --classic" {
+		t.Error("Merge() changed base")
+	}
+}
@@ -0,0 +1,129 @@
+/*
+Copyright © 2025 Fabiano Santos Florentino <fabianoflorentino@outlook.com>
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/fabianoflorentino/whiterose/config"
+	"github.com/fabianoflorentino/whiterose/exitcode"
+	"github.com/fabianoflorentino/whiterose/git"
+	"github.com/spf13/cobra"
+)
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Create, inspect and validate the whiterose configuration.",
+	Long: `The config command works with the configuration files merged by whiterose:
+the legacy repositories file, the global file and the project-local file.
+
+Example usage:
+  whiterose config init
+  whiterose config validate
+  whiterose config validate whiterose.yaml
+  whiterose config schema > whiterose.schema.json`,
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate [file...]",
+	Short: "Check configuration files for unknown fields and invalid values.",
+	Long: `Validate checks each file against the configuration schema and reports every
+problem with its file and line: unknown fields, values of the wrong type, missing
+required fields, invalid update strategies, invalid repository URLs and
+repositories sharing a directory.
+
+Without arguments the files that whiterose would load are validated.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		files := args
+		if len(files) == 0 {
+			var err error
+			if files, err = config.NewLoader().Files(); err != nil {
+				return exitcode.Wrap(exitcode.Config, err)
+			}
+			if len(files) == 0 {
+				fmt.Fprintln(progressOutput(), "No configuration file found.")
+				return render(config.ValidationReport{})
+			}
+		}
+
+		report := config.ValidateFiles(files)
+		if err := render(report); err != nil {
+			return err
+		}
+
+		if !report.Valid() {
+			return exitcode.Errorf(exitcode.Config, "configuration is invalid")
+		}
+		return nil
+	},
+}
+
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create a configuration file by answering a few questions.",
+	Long: `Init asks for the Git host, the organization, the authentication method, the
+repositories to clone and the applications to check, then writes the
+repositories file read by setup (~/.config.yaml by default). Repositories can also
+be imported from a directory of existing clones by reading their remotes.
+
+The file is written as JSON when its name ends in .json, and as YAML otherwise.`,
+	Args: usageArgs(cobra.NoArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("file")
+		force, _ := cmd.Flags().GetBool("force")
+		if path == "" {
+			path = config.DefaultInitPath()
+		}
+
+		wizard := config.NewWizard(os.Stdin, os.Stdout).WithDiscovery(git.DiscoverRepositories)
+
+		if _, err := os.Stat(path); err == nil && !force {
+			overwrite, err := wizard.Confirm(fmt.Sprintf("%s already exists, overwrite it?", path), false)
+			if err != nil {
+				return err
+			}
+			if !overwrite {
+				return nil
+			}
+		}
+
+		cfg, err := wizard.Run()
+		if err != nil {
+			return err
+		}
+		if err := config.WriteConfigFile(path, cfg); err != nil {
+			return err
+		}
+
+		fmt.Printf("Wrote %d repositories and %d applications to %s\n", len(cfg.Repositories), len(cfg.Applications), path)
+		return nil
+	},
+}
+
+var configSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema of the configuration.",
+	Args:  usageArgs(cobra.NoArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := json.MarshalIndent(config.GenerateSchema(), "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to generate schema: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configInitCmd)
+	configCmd.AddCommand(configValidateCmd)
+	configCmd.AddCommand(configSchemaCmd)
+
+	configInitCmd.Flags().StringP("file", "f", "", "File to write (default: the existing repositories file or ~/.config.yaml)")
+	configInitCmd.Flags().Bool("force", false, "Overwrite the file without asking")
+}
@@ -7,8 +7,9 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/fabianoflorentino/whiterose/config"
 	"github.com/fabianoflorentino/whiterose/docker"
-	"github.com/fabianoflorentino/whiterose/utils"
+	"github.com/fabianoflorentino/whiterose/exitcode"
 	"github.com/spf13/cobra"
 )
 
@@ -19,20 +20,18 @@ var dockerCmd = &cobra.Command{
 	Long: `The docker command in Whiterose automates common Docker tasks, such as 
 checking for the existence of a Dockerfile in the current directory and building 
 Docker images using environment variables and custom build arguments.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		switch {
 		case cmd.Flags().Changed("file"):
-			isDockerFile()
+			return isDockerFile()
 		case cmd.Flags().Changed("build"):
-			buildDockerImage()
+			return buildDockerImage()
 		case cmd.Flags().Changed("delete"):
-			deleteDockerImage()
+			return deleteDockerImage()
 		case cmd.Flags().Changed("list"):
-			listDockerImages()
+			return listDockerImages()
 		default:
-			if err := cmd.Help(); err != nil {
-				fmt.Println(err)
-			}
+			return cmd.Help()
 		}
 	},
 }
@@ -56,64 +55,85 @@ func init() {
 	// dockerCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 }
 
+// dockerSettings returns the directory searched for the Dockerfile, the image name and its version
+// from the configuration (image.path, image.name and image.version, or DOCKERFILE_PATH, IMAGE_NAME
+// and IMAGE_VERSION). The name and version default to my_app:latest and latest in the loader.
+func dockerSettings() (workDir, imageName, imageVersion string, err error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return "", "", "", exitcode.Wrap(exitcode.Config, err)
+	}
+
+	workDir, imageName, imageVersion = cfg.Image.Path, cfg.Image.Name, cfg.Image.Version
+	if workDir == "" {
+		workDir = os.Getenv("PWD")
+	}
+
+	return workDir, imageName, imageVersion, nil
+}
+
 // isDockerFile checks if a Dockerfile exists in the current directory
-func isDockerFile() {
-	workDir := utils.GetEnvOrDefault("DOCKERFILE_PATH", os.Getenv("PWD"))
+func isDockerFile() error {
+	workDir, _, _, err := dockerSettings()
+	if err != nil {
+		return err
+	}
+
 	d := docker.NewDockerManager(workDir)
 
 	dockerfilePath, err := d.DetectDockerFile()
 	if err != nil {
-		fmt.Println(err)
-		return
+		return err
 	}
 
 	fmt.Printf("Dockerfile found at: %s\n", dockerfilePath[0])
+	return nil
 }
 
 // buildDockerImage builds a Docker image from the Dockerfile
-func buildDockerImage() {
-	workDir := utils.GetEnvOrDefault("DOCKERFILE_PATH", os.Getenv("PWD"))
-	imageName := utils.GetEnvOrDefault("IMAGE_NAME", "my_app:latest")
+func buildDockerImage() error {
+	workDir, imageName, imageVersion, err := dockerSettings()
+	if err != nil {
+		return err
+	}
+
 	buildArgs := map[string]string{
-		"IMAGE_VERSION": utils.GetEnvOrDefault("IMAGE_VERSION", "latest"),
+		"IMAGE_VERSION": imageVersion,
 	}
 
 	d := docker.NewDockerManager(workDir)
 
 	dockerfilePath, err := d.DetectDockerFile()
 	if err != nil {
-		fmt.Println(err)
-		return
+		return err
 	}
 
-	if err := d.BuildDockerImage(dockerfilePath[0], imageName, buildArgs); err != nil {
-		fmt.Println(err)
-		return
-	}
+	return d.BuildDockerImage(dockerfilePath[0], imageName, buildArgs)
 }
 
-func deleteDockerImage() {
-	workDir := utils.GetEnvOrDefault("DOCKERFILE_PATH", os.Getenv("PWD"))
-	imageName := utils.GetEnvOrDefault("IMAGE_NAME", "my_app:latest")
+func deleteDockerImage() error {
+	workDir, imageName, _, err := dockerSettings()
+	if err != nil {
+		return err
+	}
 
 	d := docker.NewDockerManager(workDir)
 
-	if err := d.DeleteDockerImage(imageName); err != nil {
-		fmt.Println(err)
-		return
-	}
+	return d.DeleteDockerImage(imageName)
 }
 
-func listDockerImages() {
-	workDir := utils.GetEnvOrDefault("DOCKERFILE_PATH", os.Getenv("PWD"))
-	imageName := utils.GetEnvOrDefault("IMAGE_NAME", "my_app:latest")
-	d := docker.NewDockerManager(workDir)
+func listDockerImages() error {
+	workDir, imageName, _, err := dockerSettings()
+	if err != nil {
+		return err
+	}
 
-	fmt.Printf("📋 Available Docker images:\n")
-	fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	d := docker.NewDockerManager(workDir)
 
-	if err := d.ListDockerImages(imageName); err != nil {
-		fmt.Println(err)
-		return
+	images, err := d.Images(imageName)
+	if err != nil {
+		return err
 	}
+
+	return render(images)
 }
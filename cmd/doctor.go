@@ -0,0 +1,50 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"github.com/fabianoflorentino/whiterose/doctor"
+	"github.com/spf13/cobra"
+)
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose the environment whiterose depends on.",
+	Long: `The doctor command checks everything whiterose depends on and prints a pass, warn or
+fail line with a hint for each check, instead of the error the same problem causes in
+the middle of a run:
+
+  config files   the configuration files found (CONFIG_FILE, ~/.config.{yml,yaml,json},
+                 ~/.config/whiterose.yaml, ./whiterose.yaml)
+  config         the configuration loads and lists repositories
+  .env           ~/.env exists and parses
+  ssh key        the key, or ssh-agent, of the repositories cloned over SSH is readable,
+                 parses and is not accessible by other users
+  git host       the host of every repository accepts connections on its port
+  docker         the Docker socket of DOCKER_HOST accepts connections
+  gh auth        the GitHub CLI is logged in
+  disk space     the directory the repositories are cloned into has free space
+
+The command exits with status 4 when a check fails; warnings do not fail.
+
+Example usage:
+  whiterose doctor
+  whiterose doctor --timeout 2s --output table`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+
+		report := doctor.New().WithTimeout(timeout).Run()
+		if err := render(report); err != nil {
+			return err
+		}
+		return report.Err()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+
+	doctorCmd.Flags().Duration("timeout", doctor.DefaultTimeout, "How long a connection or command of a check may take")
+}
@@ -0,0 +1,80 @@
+/*
+Copyright © 2025 Fabiano Santos Florentino <fabianoflorentino@outlook.com>
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/fabianoflorentino/whiterose/exitcode"
+	"github.com/fabianoflorentino/whiterose/git"
+	"github.com/fabianoflorentino/whiterose/runner"
+	"github.com/spf13/cobra"
+)
+
+// execCmd represents the exec command
+var execCmd = &cobra.Command{
+	Use:   "exec [flags] -- <command>",
+	Short: "Run a shell command in every configured repository.",
+	Long: `The exec command runs an arbitrary shell command inside the directory of every
+repository listed in the configuration file. Each output line is prefixed with the
+repository name and a summary of exit codes is printed at the end.
+
+Repositories can be narrowed down by name, group, tag or directory glob, and the command
+can run in several repositories at once with --parallel.
+
+Several arguments after -- are passed to the command unchanged; a single argument
+is run as a shell command line, so it may use pipes and redirections.
+
+Example usage:
+  whiterose exec -- git status --short
+  whiterose exec --group backend --parallel 4 -- make test
+  whiterose exec --only api,worker -- make test
+  whiterose exec --glob 'services/*' -- go mod tidy
+  whiterose exec -- 'git log -1 --oneline | cut -c1-60'`,
+	Args: usageArgs(cobra.MinimumNArgs(1)),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		globs, _ := cmd.Flags().GetStringSlice("glob")
+		parallel, _ := cmd.Flags().GetInt("parallel")
+
+		repos, err := git.LoadConfiguredRepositories()
+		if err != nil {
+			return fmt.Errorf("failed to load repositories: %w", err)
+		}
+
+		filter := repoFilterFromFlags(cmd.Flags())
+		filter.Globs = globs
+
+		repos = git.FilterRepositories(repos, filter)
+		if len(repos) == 0 {
+			fmt.Fprintln(progressOutput(), "No repositories match the given filters.")
+			return render(runner.Summary{})
+		}
+
+		targets := make([]runner.Target, 0, len(repos))
+		for _, r := range repos {
+			targets = append(targets, runner.Target{Name: r.Name(), Directory: r.Directory})
+		}
+
+		// The prefixed command output goes to stderr when the summary is printed as JSON or YAML.
+		results := runner.New().WithParallel(parallel).WithOutput(progressOutput()).Run(targets, runner.CommandLine(args))
+
+		fmt.Fprintln(progressOutput())
+		if err := render(runner.Summary(results)); err != nil {
+			return err
+		}
+
+		if _, failed, _ := runner.Counts(results); failed > 0 {
+			return exitcode.Errorf(exitcode.Partial, "command failed in %d of %d repositories", failed, len(results))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(execCmd)
+
+	execCmd.Flags().IntP("parallel", "p", 1, "Number of repositories to run the command in concurrently")
+	addRepoSelectorFlags(execCmd.Flags())
+	execCmd.Flags().StringSliceP("glob", "g", []string{}, "Only run in repositories whose directory matches these patterns (comma-separated)")
+}
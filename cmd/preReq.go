@@ -5,7 +5,12 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/fabianoflorentino/whiterose/catalog"
+	"github.com/fabianoflorentino/whiterose/exitcode"
+	"github.com/fabianoflorentino/whiterose/git"
+	"github.com/fabianoflorentino/whiterose/pins"
 	"github.com/fabianoflorentino/whiterose/prereq"
 	"github.com/spf13/cobra"
 )
@@ -15,35 +20,112 @@ var preReqCmd = &cobra.Command{
 	Use:   "pre-req",
 	Short: "Validate and list required applications for the environment.",
 	Long: `The pre-req command helps you manage environment prerequisites by listing
-all required applications or validating the presence of specific ones.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		app := prereq.NewAppValidator()
+all required applications or validating the presence of specific ones.
+
+The version of each installed application is read from the output of its version
+command and compared with its recommendedVersion constraint, such as ">=1.25" or
+"~2.40". Missing applications always make the command fail; with --strict so do
+applications that do not satisfy their recommended version.
+
+Applications can be taken from the built-in catalog of common tools, listed with
+--catalog, by id in the apps key of the configuration. Without any configured
+application, Git, Go and Docker are checked.
+
+Applications are checked concurrently. A version command that does not finish within
+the timeout of its application, or --timeout, is killed and the application is
+reported as timed out, which also makes the command fail.
+
+With --from-repos the required versions are read instead from the pinning files of
+the cloned repositories: .tool-versions, .go-version, .nvmrc, the go and toolchain
+directives of go.mod and the *_VERSION ARGs of Dockerfile. The requirements of all
+repositories, narrowed with --only, --group and --tag, are merged per tool and shown
+next to the installed version; tools whose requirements conflict make the command
+fail.
+
+Example usage:
+  whiterose pre-req --check
+  whiterose pre-req --check --strict
+  whiterose pre-req --apps go,git --strict
+  whiterose pre-req --check --timeout 3s
+  whiterose pre-req --catalog
+  whiterose pre-req --from-repos --group backend`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		app, err := prereq.NewAppValidator()
+		if err != nil {
+			return err
+		}
 
 		// validApps receives the list of applications to validate
 		validApps, _ := cmd.Flags().GetStringSlice("apps")
+		strict, _ := cmd.Flags().GetBool("strict")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		app.WithTimeout(timeout)
 
 		switch {
 		case cmd.Flags().Changed("check"):
-			app.ValidateApps()
+			return renderChecks(app.Check(), strict)
 		case cmd.Flags().Changed("list"):
-			app.ListAvailableApps()
+			return render(app.Apps())
+		case cmd.Flags().Changed("catalog"):
+			return render(catalog.Entries())
+		case cmd.Flags().Changed("from-repos"):
+			return checkFromRepos(cmd, app, strict)
 		case cmd.Flags().Changed("apps"):
-			app.ValidateSpecificApps(validApps)
-		case len(args) == 0:
-			if err := cmd.Help(); err != nil {
-				fmt.Println(err)
+			results := app.CheckSpecific(validApps)
+			if len(results) == 0 {
+				return exitcode.Errorf(exitcode.Usage, "no configured application matches %s", strings.Join(validApps, ", "))
 			}
+			return renderChecks(results, strict)
 		default:
+			return cmd.Help()
 		}
 	},
 }
 
+// renderChecks prints the validations and returns the exitcode.Prerequisite error when an
+// application is missing or timed out or, when strict, outdated.
+func renderChecks(results prereq.Results, strict bool) error {
+	if err := render(results); err != nil {
+		return err
+	}
+	return results.Err(strict)
+}
+
+// checkFromRepos prints the versions required by the pinning files of the selected repositories
+// and returns the exitcode.Prerequisite error when they conflict or a tool is missing or, when
+// strict, outdated.
+func checkFromRepos(cmd *cobra.Command, app *prereq.AppValidator, strict bool) error {
+	configured, err := git.LoadConfiguredRepositories()
+	if err != nil {
+		return fmt.Errorf("failed to load repositories: %w", err)
+	}
+
+	var repos []pins.Repository
+	for _, r := range git.FilterRepositories(configured, repoFilterFromFlags(cmd.Flags())) {
+		repos = append(repos, pins.Repository{Name: r.Name(), Directory: r.Directory})
+	}
+
+	report, err := pins.Check(repos, app)
+	if err != nil {
+		return err
+	}
+	if err := render(report); err != nil {
+		return err
+	}
+	return report.Err(strict)
+}
+
 func init() {
 	rootCmd.AddCommand(preReqCmd)
 
 	preReqCmd.Flags().BoolP("check", "c", false, "Check if all required applications are installed")
 	preReqCmd.Flags().BoolP("list", "l", false, "List all available applications")
+	preReqCmd.Flags().Bool("catalog", false, "List the applications of the built-in catalog")
 	preReqCmd.Flags().StringSliceP("apps", "a", []string{}, "Validate specific applications (comma-separated)")
+	preReqCmd.Flags().Bool("strict", false, "Also fail when an application does not satisfy its recommended version")
+	preReqCmd.Flags().Duration("timeout", prereq.DefaultTimeout, "How long the version command of an application without a timeout may run")
+	preReqCmd.Flags().Bool("from-repos", false, "Check the tool versions pinned by the cloned repositories")
+	addRepoSelectorFlags(preReqCmd.Flags())
 
 	// Here you will define your flags and configuration settings.
 
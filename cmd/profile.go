@@ -0,0 +1,71 @@
+/*
+Copyright © 2025 Fabiano Santos Florentino <fabianoflorentino@outlook.com>
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fabianoflorentino/whiterose/config"
+	"github.com/spf13/cobra"
+)
+
+// profileCmd represents the profile command
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "List and switch between configuration profiles.",
+	Long: `Profiles are named sets of settings, such as the repositories, credentials,
+Docker image and base branch of a client, declared under the profiles key of the
+configuration. The active profile is applied on top of the other settings and is
+chosen, in order, by the --profile flag, the WHITEROSE_PROFILE environment variable
+or the profile saved with "whiterose profile use".
+
+Example usage:
+  whiterose profile list
+  whiterose profile use client-x
+  whiterose --profile personal setup`,
+}
+
+var profileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the configured profiles, marking the active one.",
+	Args:  usageArgs(cobra.NoArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		return render(cfg.ProfileList())
+	},
+}
+
+var profileUseCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Save the profile used by default in the global config file.",
+	Args:  usageArgs(cobra.ExactArgs(1)),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.NewLoader().WithProfile(args[0]).Load()
+		if err != nil {
+			return err
+		}
+
+		path := config.GlobalFilePath()
+		if err := config.SaveProfile(path, cfg.Profile); err != nil {
+			return err
+		}
+
+		fmt.Printf("Using profile %s (saved in %s)\n", cfg.Profile, path)
+		if env := os.Getenv("WHITEROSE_PROFILE"); env != "" && env != cfg.Profile {
+			fmt.Printf("Note: WHITEROSE_PROFILE=%s still takes precedence in this shell\n", env)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(profileCmd)
+	profileCmd.AddCommand(profileListCmd)
+	profileCmd.AddCommand(profileUseCmd)
+}
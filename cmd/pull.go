@@ -0,0 +1,47 @@
+/*
+Copyright © 2025 Fabiano Santos Florentino <fabianoflorentino@outlook.com>
+*/
+package cmd
+
+import (
+	"github.com/fabianoflorentino/whiterose/git"
+	"github.com/spf13/cobra"
+)
+
+// pullCmd represents the pull command
+var pullCmd = &cobra.Command{
+	Use:   "pull",
+	Short: "Fetch and fast-forward every configured repository.",
+	Long: `The pull command fetches every repository listed in the configuration file and
+fast-forwards its current branch to its upstream, processing repositories concurrently.
+
+Repositories with local changes, a diverged history or that are not cloned yet are
+skipped and the reason is printed in the summary.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := git.SetupOptions{Filter: repoFilterFromFlags(cmd.Flags()), Format: outputFormat}
+		return git.NewGitRepository().Pull(opts)
+	},
+}
+
+// fetchCmd represents the fetch command
+var fetchCmd = &cobra.Command{
+	Use:   "fetch",
+	Short: "Fetch every configured repository without changing the worktrees.",
+	Long: `The fetch command fetches the origin remote of every repository listed in the
+configuration file, processing repositories concurrently. Local branches and
+worktrees are left untouched.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := git.SetupOptions{Filter: repoFilterFromFlags(cmd.Flags()), Format: outputFormat}
+		return git.NewGitRepository().Fetch(opts)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pullCmd)
+	rootCmd.AddCommand(fetchCmd)
+
+	addRepoSelectorFlags(pullCmd.Flags())
+	addRepoSelectorFlags(fetchCmd.Flags())
+	pullCmd.Flags().IntP("workers", "w", 0, "Number of repositories to process concurrently (default from config \"workers\" key, or 4)")
+	fetchCmd.Flags().IntP("workers", "w", 0, "Number of repositories to process concurrently (default from config \"workers\" key, or 4)")
+}
@@ -4,9 +4,19 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"errors"
+	"io"
+	"os"
+
+	"github.com/fabianoflorentino/whiterose/config"
+	"github.com/fabianoflorentino/whiterose/exitcode"
+	"github.com/fabianoflorentino/whiterose/output"
 	"github.com/spf13/cobra"
 )
 
+// outputFormat is the format selected with --output.
+var outputFormat = output.FormatText
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "whiterose",
@@ -23,7 +33,36 @@ Features:
 
 Example usage:
   whiterose setup
+  whiterose --profile client-x setup
+  whiterose --output json pre-req --check
+
+Exit codes:
+  0  success
+  1  unexpected failure
+  2  invalid flags or arguments
+  3  configuration error
+  4  prerequisite missing
+  5  partial failure: some repositories or projects failed
+  6  an external tool or service failed
 `,
+	// Errors are printed once by main, with the exit code they carry.
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if profile, _ := cmd.Flags().GetString("profile"); profile != "" {
+			config.SelectProfile(profile)
+		}
+		config.SelectFlags(cmd.Flags())
+
+		name, _ := cmd.Flags().GetString("output")
+		format, err := output.ParseFormat(name)
+		if err != nil {
+			return exitcode.Wrap(exitcode.Usage, err)
+		}
+		outputFormat = format
+
+		return nil
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -31,3 +70,37 @@ Example usage:
 func Execute() error {
 	return rootCmd.Execute()
 }
+
+func init() {
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return exitcode.Wrap(exitcode.Usage, err)
+	})
+	rootCmd.PersistentFlags().String("output", string(output.FormatText), "Output format: text, table, json or yaml")
+	rootCmd.PersistentFlags().String("profile", "", "Configuration profile to use (default: WHITEROSE_PROFILE or the saved profile)")
+}
+
+// render writes a command result to stdout in the format selected with --output. A format the
+// result does not support is a usage error; failing to encode or write it is a failure.
+func render(v any) error {
+	err := output.Render(os.Stdout, outputFormat, v)
+	if errors.Is(err, output.ErrUnsupported) {
+		return exitcode.Wrap(exitcode.Usage, err)
+	}
+	return exitcode.Wrap(exitcode.Failure, err)
+}
+
+// usageArgs makes the errors of an argument validator exit with the usage status.
+func usageArgs(validate cobra.PositionalArgs) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		return exitcode.Wrap(exitcode.Usage, validate(cmd, args))
+	}
+}
+
+// progressOutput returns where commands write their progress messages: stderr when the result
+// is printed as JSON or YAML, so that stdout can be parsed, and stdout otherwise.
+func progressOutput() io.Writer {
+	if outputFormat.Structured() {
+		return os.Stderr
+	}
+	return os.Stdout
+}
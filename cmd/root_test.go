@@ -1,7 +1,13 @@
 package cmd
 
 import (
+	"os"
 	"testing"
+
+	"github.com/fabianoflorentino/whiterose/exitcode"
+	"github.com/fabianoflorentino/whiterose/output"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 func TestRootCmd(t *testing.T) {
@@ -17,6 +23,80 @@ func TestRootCmd(t *testing.T) {
 	}
 }
 
+func TestRootCmd_OutputFlag(t *testing.T) {
+	t.Cleanup(func() { outputFormat = output.FormatText })
+
+	flag := rootCmd.PersistentFlags().Lookup("output")
+	if flag == nil || flag.DefValue != "text" || flag.Shorthand != "" {
+		t.Fatalf("output flag = %+v, want --output defaulting to text without shorthand", flag)
+	}
+
+	cmd := &cobra.Command{Use: "test"}
+	cmd.Flags().AddFlagSet(rootCmd.PersistentFlags())
+
+	if err := cmd.Flags().Set("output", "JSON"); err != nil {
+		t.Fatal(err)
+	}
+	if err := rootCmd.PersistentPreRunE(cmd, nil); err != nil || outputFormat != output.FormatJSON {
+		t.Errorf("PersistentPreRunE() = %v, outputFormat = %q", err, outputFormat)
+	}
+
+	if err := cmd.Flags().Set("output", "xml"); err != nil {
+		t.Fatal(err)
+	}
+	if err := rootCmd.PersistentPreRunE(cmd, nil); exitcode.Of(err) != exitcode.Usage {
+		t.Errorf("PersistentPreRunE() = %v, want a usage error for an unknown output format", err)
+	}
+}
+
+func TestRender_ExitCodes(t *testing.T) {
+	stdout := os.Stdout
+	t.Cleanup(func() {
+		os.Stdout = stdout
+		outputFormat = output.FormatText
+	})
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Close()
+	defer w.Close()
+	os.Stdout = w
+
+	outputFormat = output.FormatTable
+	if err := render("plain"); exitcode.Of(err) != exitcode.Usage {
+		t.Errorf("render() of an unsupported format = %v, want exit code %d", err, exitcode.Usage)
+	}
+
+	outputFormat = output.FormatJSON
+	if err := render([]string{"api"}); err == nil || exitcode.Of(err) != exitcode.Failure {
+		t.Errorf("render() to a closed pipe = %v, want exit code %d", err, exitcode.Failure)
+	}
+}
+
+func TestExecute_UsageErrors(t *testing.T) {
+	t.Cleanup(func() { rootCmd.SetArgs(nil) })
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"unknown flag", []string{"status", "--bogus"}},
+		{"missing arguments", []string{"exec"}},
+		{"extra arguments", []string{"profile", "list", "extra"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd.SetArgs(tt.args)
+			if err := Execute(); exitcode.Of(err) != exitcode.Usage {
+				t.Errorf("Execute(%v) = %v, want exit code %d", tt.args, err, exitcode.Usage)
+			}
+		})
+	}
+}
+
 func TestSetupCmd(t *testing.T) {
 	if setupCmd.Use != "setup" {
 		t.Errorf("Use = %v, want setup", setupCmd.Use)
@@ -34,6 +114,9 @@ func TestSetupCmd(t *testing.T) {
 	if flags.Lookup("repos") == nil {
 		t.Error("repos flag should exist")
 	}
+	if flags.Lookup("workers") == nil {
+		t.Error("workers flag should exist")
+	}
 }
 
 func TestPreReqCmd(t *testing.T) {
@@ -108,4 +191,107 @@ func TestAllCommands_HaveShortDescription(t *testing.T) {
 			t.Errorf("Command %s has empty Short description", c.Use)
 		}
 	}
-}
\ No newline at end of file
+}
+func TestStatusCmd(t *testing.T) {
+	if statusCmd.Use != "status" {
+		t.Errorf("Use = %v, want status", statusCmd.Use)
+	}
+	if statusCmd.Flags().Lookup("json") == nil {
+		t.Error("json flag should exist")
+	}
+}
+
+func TestPullAndFetchCmd(t *testing.T) {
+	for _, c := range []*cobra.Command{pullCmd, fetchCmd} {
+		if c.Short == "" {
+			t.Errorf("%s: Short should not be empty", c.Use)
+		}
+		if c.Flags().Lookup("workers") == nil {
+			t.Errorf("%s: workers flag should exist", c.Use)
+		}
+	}
+}
+
+func TestExecCmd(t *testing.T) {
+	flags := execCmd.Flags()
+	for _, name := range []string{"parallel", "only", "group", "tag", "glob"} {
+		if flags.Lookup(name) == nil {
+			t.Errorf("%s flag should exist", name)
+		}
+	}
+	if err := execCmd.Args(execCmd, []string{}); err == nil {
+		t.Error("exec should require a command")
+	}
+}
+
+func TestSecretCmd(t *testing.T) {
+	if secretSetCmd.Flags().Lookup("backend") == nil {
+		t.Error("backend flag should exist")
+	}
+	if err := secretSetCmd.Args(secretSetCmd, []string{}); err == nil {
+		t.Error("secret set should require a name")
+	}
+	if len(secretCmd.Commands()) != 2 {
+		t.Errorf("secret has %d subcommands, want set and list", len(secretCmd.Commands()))
+	}
+}
+
+func TestConfigCmd(t *testing.T) {
+	if configCmd.Use != "config" {
+		t.Errorf("Use = %v, want config", configCmd.Use)
+	}
+	if len(configCmd.Commands()) != 3 {
+		t.Errorf("config has %d subcommands, want init, validate and schema", len(configCmd.Commands()))
+	}
+	if configInitCmd.Flags().Lookup("file") == nil || configInitCmd.Flags().Lookup("force") == nil {
+		t.Error("config init should have file and force flags")
+	}
+	if err := configSchemaCmd.Args(configSchemaCmd, []string{"extra"}); err == nil {
+		t.Error("config schema should not accept arguments")
+	}
+}
+
+func TestProfileCmd(t *testing.T) {
+	if rootCmd.PersistentFlags().Lookup("profile") == nil {
+		t.Error("profile flag should exist on the root command")
+	}
+	if len(profileCmd.Commands()) != 2 {
+		t.Errorf("profile has %d subcommands, want list and use", len(profileCmd.Commands()))
+	}
+	if err := profileUseCmd.Args(profileUseCmd, []string{}); err == nil {
+		t.Error("profile use should require a name")
+	}
+}
+
+func TestRepoSelectorFlags(t *testing.T) {
+	commands := map[string]*pflag.FlagSet{
+		"setup":  setupCmd.PersistentFlags(),
+		"status": statusCmd.Flags(),
+		"pull":   pullCmd.Flags(),
+		"fetch":  fetchCmd.Flags(),
+		"exec":   execCmd.Flags(),
+	}
+	for name, flags := range commands {
+		for _, flag := range []string{"only", "group", "tag"} {
+			if flags.Lookup(flag) == nil {
+				t.Errorf("%s: %s flag should exist", name, flag)
+			}
+		}
+	}
+}
+
+func TestRepoFilterFromFlags(t *testing.T) {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	addRepoSelectorFlags(flags)
+	if err := flags.Parse([]string{"--group", "backend", "--only", "api,worker"}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	f := repoFilterFromFlags(flags)
+	if len(f.Groups) != 1 || f.Groups[0] != "backend" {
+		t.Errorf("Groups = %v, want [backend]", f.Groups)
+	}
+	if len(f.Names) != 2 || f.Names[0] != "api" || f.Names[1] != "worker" {
+		t.Errorf("Names = %v, want [api worker]", f.Names)
+	}
+}
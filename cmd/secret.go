@@ -0,0 +1,113 @@
+/*
+Copyright © 2025 Fabiano Santos Florentino <fabianoflorentino@outlook.com>
+*/
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fabianoflorentino/whiterose/exitcode"
+	"github.com/fabianoflorentino/whiterose/output"
+	"github.com/fabianoflorentino/whiterose/secrets"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// secretCmd represents the secret command
+var secretCmd = &cobra.Command{
+	Use:   "secret",
+	Short: "Store tokens in the OS keyring or an encrypted file.",
+	Long: `The secret command stores tokens outside of the configuration and .env files.
+Any configuration value or environment variable written as secret://<name> is
+resolved when whiterose runs, looking the name up in order in:
+
+  1. the WHITEROSE_SECRET_<NAME> environment variable
+  2. the OS keyring (macOS Keychain or libsecret's secret-tool)
+  3. the encrypted file ~/.config/whiterose/secrets.enc (WHITEROSE_SECRETS_FILE),
+     unlocked with WHITEROSE_SECRETS_PASSPHRASE or an interactive prompt
+
+Example usage:
+  whiterose secret set gitlab-token
+  echo "$TOKEN" | whiterose secret set github-token --backend keyring
+  GIT_TOKEN=secret://github-token whiterose setup`,
+}
+
+var secretSetCmd = &cobra.Command{
+	Use:   "set <name>",
+	Short: "Store a secret, reading its value from stdin or a prompt.",
+	Args:  usageArgs(cobra.ExactArgs(1)),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		backend, _ := cmd.Flags().GetString("backend")
+
+		value, err := readSecretValue(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to read secret: %w", err)
+		}
+
+		switch backend {
+		case "file":
+			err = secrets.NewFileProvider(secrets.DefaultFilePath()).Set(args[0], value)
+		case "keyring":
+			err = secrets.NewKeyringProvider(secrets.NewSystemKeyring()).Set(args[0], value)
+		default:
+			return exitcode.Errorf(exitcode.Usage, "unknown backend %q, expected file or keyring", backend)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to store secret: %w", err)
+		}
+
+		fmt.Printf("Stored %s in the %s backend, reference it as %s%s\n", args[0], backend, secrets.ReferencePrefix, args[0])
+		return nil
+	},
+}
+
+var secretListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the secrets stored in the encrypted file.",
+	Args:  usageArgs(cobra.NoArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		names, err := secrets.NewFileProvider(secrets.DefaultFilePath()).Names()
+		if err != nil {
+			return fmt.Errorf("failed to read secrets: %w", err)
+		}
+
+		return render(output.List{Header: "NAME", Values: names})
+	},
+}
+
+// readSecretValue prompts for the secret without echo on a terminal, or reads the first line of stdin.
+func readSecretValue(name string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		fmt.Fprintf(os.Stderr, "Value for %s: ", name)
+		value, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", err
+		}
+		return string(value), nil
+	}
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	value := strings.TrimRight(line, "\r\n")
+	if value == "" {
+		return "", fmt.Errorf("empty value")
+	}
+
+	return value, nil
+}
+
+func init() {
+	rootCmd.AddCommand(secretCmd)
+	secretCmd.AddCommand(secretSetCmd)
+	secretCmd.AddCommand(secretListCmd)
+
+	secretSetCmd.Flags().StringP("backend", "b", "file", "Where to store the secret: file or keyring")
+}
@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"github.com/fabianoflorentino/whiterose/git"
+	"github.com/spf13/pflag"
+)
+
+// addRepoSelectorFlags registers the flags shared by every multi-repository command
+// to narrow the configured repositories down.
+func addRepoSelectorFlags(flags *pflag.FlagSet) {
+	flags.StringSliceP("only", "o", []string{}, "Only these repositories, by name or directory (comma-separated)")
+	flags.StringSlice("group", []string{}, "Only repositories in any of these groups (comma-separated)")
+	flags.StringSliceP("tag", "t", []string{}, "Only repositories with any of these tags (comma-separated)")
+}
+
+// repoFilterFromFlags builds a git.RepoFilter from the flags registered by addRepoSelectorFlags.
+func repoFilterFromFlags(flags *pflag.FlagSet) git.RepoFilter {
+	only, _ := flags.GetStringSlice("only")
+	groups, _ := flags.GetStringSlice("group")
+	tags, _ := flags.GetStringSlice("tag")
+
+	return git.RepoFilter{Names: only, Groups: groups, Tags: tags}
+}
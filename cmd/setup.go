@@ -5,7 +5,11 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 
+	"github.com/fabianoflorentino/whiterose/config"
+	"github.com/fabianoflorentino/whiterose/git"
+	"github.com/fabianoflorentino/whiterose/install"
 	"github.com/fabianoflorentino/whiterose/setup"
 	"github.com/spf13/cobra"
 )
@@ -18,24 +22,62 @@ var setupCmd = &cobra.Command{
 
 It can be used to:
 - Check and install required prerequisites (such as system dependencies and mandatory tools);
-- Clone the necessary git repositories for the project to work.`,
-	Run: func(cmd *cobra.Command, args []string) {
+- Clone the necessary git repositories for the project to work.
+
+Missing prerequisites are installed with the first available installer configured in the
+install key of the application: mise, asdf, brew, apt, dnf, pacman or a release archive
+verified against its SHA-256 checksum. The plan is printed and confirmed before anything is
+installed.
+
+Example usage:
+  whiterose setup --pre-req --dry-run
+  whiterose setup --pre-req --yes
+  whiterose setup --all`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cloneOpts := git.SetupOptions{Filter: repoFilterFromFlags(cmd.Flags()), Format: outputFormat}
+
 		switch {
 		case cmd.Flags().Changed("all"):
-			setup.PreReq()
-			setup.GitCloneRepository()
+			// The clone summary is the result of --all; the pre-req check is printed as progress.
+			results, err := setup.InstallPreReq(installOptionsFromFlags(cmd))
+			if err != nil {
+				return err
+			}
+			results.Text(progressOutput())
+			if err := setup.GitCloneRepository(cloneOpts); err != nil {
+				return err
+			}
+			return results.Err(false)
 		case cmd.Flags().Changed("pre-req"):
-			setup.PreReq()
+			results, err := setup.InstallPreReq(installOptionsFromFlags(cmd))
+			if err != nil {
+				return err
+			}
+			return renderChecks(results, false)
 		case cmd.Flags().Changed("repos"):
-			setup.GitCloneRepository()
+			return setup.GitCloneRepository(cloneOpts)
 		default:
-			if err := cmd.Help(); err != nil {
-				fmt.Println(err)
-			}
+			return cmd.Help()
 		}
 	},
 }
 
+// installOptionsFromFlags reads --dry-run and --yes. Without --yes the plan is confirmed on the
+// terminal, and declined when stdin is not one.
+func installOptionsFromFlags(cmd *cobra.Command) setup.InstallOptions {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	yes, _ := cmd.Flags().GetBool("yes")
+
+	opts := setup.InstallOptions{DryRun: dryRun, Out: progressOutput()}
+	if !yes {
+		opts.Confirm = func(plan install.Plan) (bool, error) {
+			question := fmt.Sprintf("Install %d applications?", len(plan.Steps))
+			return config.NewWizard(os.Stdin, progressOutput()).Confirm(question, false)
+		}
+	}
+	return opts
+}
+
 func init() {
 	rootCmd.AddCommand(setupCmd)
 
@@ -47,6 +89,10 @@ func init() {
 	setupCmd.PersistentFlags().BoolP("all", "a", false, "Check and install pre-requisites and clone repositories")
 	setupCmd.PersistentFlags().BoolP("pre-req", "p", false, "Check and install pre-requisites")
 	setupCmd.PersistentFlags().BoolP("repos", "r", false, "Clone git repositories")
+	setupCmd.PersistentFlags().Bool("dry-run", false, "Print how the missing pre-requisites would be installed without installing them")
+	setupCmd.PersistentFlags().BoolP("yes", "y", false, "Install the missing pre-requisites without asking for confirmation")
+	addRepoSelectorFlags(setupCmd.PersistentFlags())
+	setupCmd.PersistentFlags().IntP("workers", "w", 0, "Number of repositories to clone concurrently (default from config \"workers\" key, or 4)")
 
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
@@ -0,0 +1,45 @@
+/*
+Copyright © 2025 Fabiano Santos Florentino <fabianoflorentino@outlook.com>
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/fabianoflorentino/whiterose/git"
+	"github.com/fabianoflorentino/whiterose/output"
+	"github.com/spf13/cobra"
+)
+
+// statusCmd represents the status command
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show branch, dirty state and ahead/behind of every configured repository.",
+	Long: `The status command opens every repository listed in the configuration file and
+reports its current branch, the number of modified and untracked files, how many
+commits it is ahead of or behind its upstream and its last commit.
+
+Run 'whiterose setup --repos' first to clone the repositories.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repos, err := git.LoadConfiguredRepositories()
+		if err != nil {
+			return fmt.Errorf("failed to load repositories: %w", err)
+		}
+
+		statuses := git.CollectStatus(git.FilterRepositories(repos, repoFilterFromFlags(cmd.Flags())))
+
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			outputFormat = output.FormatJSON
+		}
+
+		return render(statuses)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+
+	statusCmd.Flags().BoolP("json", "j", false, "Print the status as JSON")
+	_ = statusCmd.Flags().MarkDeprecated("json", "use --output json instead")
+	addRepoSelectorFlags(statusCmd.Flags())
+}
@@ -10,6 +10,8 @@ import (
 	"os/exec"
 	"strings"
 
+	"github.com/fabianoflorentino/whiterose/config"
+	"github.com/fabianoflorentino/whiterose/exitcode"
 	"github.com/fabianoflorentino/whiterose/internal/domain/entities"
 	"github.com/fabianoflorentino/whiterose/update"
 	"github.com/spf13/cobra"
@@ -51,24 +53,36 @@ The command will:
 4. Commit changes
 5. Push to origin for PR creation
 `,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// --base is bound to git.base by the configuration loader.
+		cfg, err := config.Load()
+		if err != nil {
+			return exitcode.Wrap(exitcode.Config, err)
+		}
+		updateBase = cfg.Git.Base
+
 		if updateList || updateReport {
-			runListVersions()
+			// JSON and YAML hold a single document, so the report replaces the list there.
+			var listErr error
+			if !updateReport || !outputFormat.Structured() {
+				listErr = runListVersions()
+			}
 			if updateReport {
-				runCreateReportPR()
+				if err := runCreateReportPR(); err != nil {
+					return err
+				}
 			}
-			return
+			return listErr
 		}
 
 		if !updateGoMod && !updateGoVersion && !updateDockerImage && !updatePackages {
-			fmt.Println("Error: specify at least one of --go-mod, --go-version, --docker-image, or --packages")
-			os.Exit(1)
+			return exitcode.Errorf(exitcode.Usage, "specify at least one of --go-mod, --go-version, --docker-image, or --packages")
 		}
 
 		if updateMajor {
 			if !confirmMajorUpdate() {
 				fmt.Println("Update cancelled.")
-				os.Exit(0)
+				return nil
 			}
 		}
 
@@ -76,15 +90,15 @@ The command will:
 
 		projects, err := service.LoadUpdateConfig(updateConfigPath)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("failed to load config: %w", err)
 		}
 
 		if len(projects) == 0 {
 			fmt.Println("No projects found in config.")
-			os.Exit(0)
+			return nil
 		}
 
+		failed := 0
 		for _, project := range projects {
 			fmt.Printf("\n--- Updating %s ---\n", project.Name)
 
@@ -96,6 +110,7 @@ The command will:
 				err = service.UpdateGoMod(project, parsedStrategy, updateMajor)
 				if err != nil {
 					fmt.Fprintf(os.Stderr, "Error updating go.mod: %v\n", err)
+					failed++
 					continue
 				}
 				changes = append(changes, "Updated go.mod dependencies")
@@ -106,6 +121,7 @@ The command will:
 				err = service.UpdateGoVersion(project, parsedStrategy, updateMajor)
 				if err != nil {
 					fmt.Fprintf(os.Stderr, "Error updating Go version: %v\n", err)
+					failed++
 					continue
 				}
 				changes = append(changes, "Updated Go version")
@@ -116,6 +132,7 @@ The command will:
 				err = service.UpdateDockerImage(project, parsedStrategy, updateMajor)
 				if err != nil {
 					fmt.Fprintf(os.Stderr, "Error updating Docker image: %v\n", err)
+					failed++
 					continue
 				}
 				changes = append(changes, "Updated Docker base image")
@@ -126,6 +143,7 @@ The command will:
 				err = checker.UpdatePackages(project.Path, "patch", updateDryRun)
 				if err != nil {
 					fmt.Fprintf(os.Stderr, "Error updating packages: %v\n", err)
+					failed++
 					continue
 				}
 				changes = append(changes, "Updated Go packages")
@@ -140,12 +158,14 @@ The command will:
 				branchName, err := service.CreateBranchAndCommit(project, changes)
 				if err != nil {
 					fmt.Fprintf(os.Stderr, "Error creating branch/commit: %v\n", err)
+					failed++
 					continue
 				}
 
 				if updatePR {
 					if err := service.CreatePRWithBase(project, branchName, changes, updateBase); err != nil {
 						fmt.Fprintf(os.Stderr, "Error creating PR: %v\n", err)
+						failed++
 						continue
 					}
 				}
@@ -153,58 +173,84 @@ The command will:
 				fmt.Printf("Update completed for %s\n", project.Name)
 			}
 		}
+
+		if failed > 0 {
+			return exitcode.Errorf(exitcode.Partial, "%d of %d projects failed to update", failed, len(projects))
+		}
+		return nil
 	},
 }
 
-func runListVersions() {
+// runListVersions prints the available updates. The checks that fail are reported in the list
+// and make it return an exitcode.Partial error.
+func runListVersions() error {
 	checker := update.NewVersionChecker()
+	progress := progressOutput()
+	var list update.UpdateList
+	failed := 0
 
 	if updateGoMod {
 		projects, err := update.New().LoadUpdateConfig(updateConfigPath)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("failed to load config: %w", err)
 		}
 
 		for _, project := range projects {
 			if project.GoMod != nil {
-				fmt.Printf("\n=== %s: Library updates ===\n", project.Name)
-				if err := checker.ListGoLibUpdates(project.Path); err != nil {
+				fmt.Fprintf(progress, "Checking %s for library updates...\n", project.Name)
+				result := update.ProjectPackages{Project: project.Name}
+				updates, err := checker.FetchGoLibUpdates(project.Path)
+				if err != nil {
 					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					result.Error = err.Error()
+					failed++
 				}
+				result.Updates = updates
+				list.Libraries = append(list.Libraries, result)
 			}
 		}
 	}
 
-	if updateGoVersion {
-		if err := checker.ListGoVersions(); err != nil {
+	if updateGoVersion || (!updateGoMod && !updateDockerImage) {
+		fmt.Fprintln(progress, "Fetching Go versions...")
+		versions, err := checker.FetchGoVersions()
+		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			failed++
+		} else {
+			list.GoVersions = &versions
 		}
 	}
 
 	if updateDockerImage {
 		projects, err := update.New().LoadUpdateConfig(updateConfigPath)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("failed to load config: %w", err)
 		}
 
 		for _, project := range projects {
 			if project.DockerImage != nil {
-				fmt.Printf("\n=== %s: Docker image updates ===\n", project.Name)
-				if err := checker.ListDockerUpdates(project.DockerImage.Base); err != nil {
+				fmt.Fprintf(progress, "Fetching Docker image versions for %s...\n", project.DockerImage.Base)
+				result := update.ProjectImage{Project: project.Name}
+				tags, err := checker.FetchDockerTags(project.DockerImage.Base)
+				if err != nil {
 					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					result.Error = err.Error()
+					failed++
 				}
+				result.ImageTags = tags
+				list.DockerImages = append(list.DockerImages, result)
 			}
 		}
 	}
 
-	if !updateGoMod && !updateGoVersion && !updateDockerImage {
-		fmt.Println("Listing all available updates...")
-		if err := checker.ListGoVersions(); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		}
+	if err := render(list); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return exitcode.Errorf(exitcode.Partial, "%d update checks failed", failed)
 	}
+	return nil
 }
 
 func confirmMajorUpdate() bool {
@@ -218,66 +264,22 @@ func confirmMajorUpdate() bool {
 	return input == "yes"
 }
 
-func runCreateReportPR() {
-	fmt.Println("\n--- Generating Updates Report ---")
+// runCreateReportPR prints the updates report and opens a pull request with it when a GitHub
+// token is set. A missing token only skips the pull request.
+func runCreateReportPR() error {
+	progress := progressOutput()
+	fmt.Fprintln(progress, "\n--- Generating Updates Report ---")
 
 	projects, err := update.New().LoadUpdateConfig(updateConfigPath)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
-		return
+		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	checker := update.NewVersionChecker()
-	var report strings.Builder
-
-	report.WriteString("# 📦 Dependency Updates Report\n\n")
-	fmt.Fprintf(&report, "Generated: %s\n\n", entities.GetTimestampedBranchName())
-	report.WriteString("---\n\n")
-
-	for _, project := range projects {
-		fmt.Fprintf(&report, "## %s\n\n", project.Name)
-		fmt.Fprintf(&report, "Path: `%s`\n\n", project.Path)
-
-		if project.GoMod != nil {
-			report.WriteString("### Go Packages\n\n")
-			cmd := exec.Command("go", "list", "-m", "-u", "all")
-			cmd.Dir = project.Path
-			out, err := cmd.CombinedOutput()
-			if err != nil {
-				report.WriteString("```\nError checking updates\n```\n\n")
-			} else {
-				hasUpdates := false
-				lines := strings.Split(string(out), "\n")
-				for _, line := range lines {
-					if strings.Contains(line, "->") && !strings.HasPrefix(line, "go:") {
-						fmt.Fprintf(&report, "- `%s`\n", line)
-						hasUpdates = true
-					}
-				}
-				if !hasUpdates {
-					report.WriteString("All packages up to date.\n\n")
-				}
-			}
-		}
-
-		if project.GoVersion != nil {
-			report.WriteString("### Go Version\n\n")
-			fmt.Fprintf(&report, "Current: %s\n\n", checker.GetCurrentGoVersion(project.Path))
-		}
-
-		if project.DockerImage != nil {
-			report.WriteString("### Docker Image\n\n")
-			fmt.Fprintf(&report, "Current: `%s`\n\n", project.DockerImage.Base)
-		}
-
-		report.WriteString("---\n\n")
+	report := update.NewVersionChecker().BuildReport(projects)
+	if err := render(report); err != nil {
+		return err
 	}
 
-	report.WriteString("## 🤖 Generated by Whiterose\n\n")
-	report.WriteString("Use `whiterose update --packages --config update-config.yaml` to apply updates.\n")
-
-	fmt.Println(report.String())
-
 	if updatePR || updateReport {
 		ghToken := os.Getenv("GH_TOKEN")
 		if ghToken == "" {
@@ -285,21 +287,19 @@ func runCreateReportPR() {
 		}
 
 		if ghToken == "" {
-			fmt.Println("\n⚠️  GH_TOKEN or GITHUB_TOKEN not set. Skipping PR creation.")
-			fmt.Println("   Run 'export GH_TOKEN=your_token' to enable PR creation.")
-			return
+			fmt.Fprintln(progress, "\n⚠️  GH_TOKEN or GITHUB_TOKEN not set. Skipping PR creation.")
+			fmt.Fprintln(progress, "   Run 'export GH_TOKEN=your_token' to enable PR creation.")
+			return nil
 		}
 
 		tmpFile, err := os.CreateTemp("", "whiterose-report-*.md")
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error creating temp file: %v\n", err)
-			return
+			return fmt.Errorf("failed to create temp file: %w", err)
 		}
 		defer func() { _ = os.Remove(tmpFile.Name()) }()
 
-		if _, err := tmpFile.WriteString(report.String()); err != nil {
-			fmt.Fprintf(os.Stderr, "Error writing report: %v\n", err)
-			return
+		if _, err := tmpFile.WriteString(report.Markdown()); err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
 		}
 		_ = tmpFile.Close()
 
@@ -316,14 +316,15 @@ func runCreateReportPR() {
 		out, err := createPR.CombinedOutput()
 
 		if err != nil {
-			fmt.Printf("\n⚠️  Could not create PR automatically: %v\n", err)
-			fmt.Println("Report saved to:", tmpFile.Name())
-			fmt.Println("\nYou can create the PR manually with:")
-			fmt.Printf("   gh pr create --title '📦 Dependency Updates' --body-file %s\n", tmpFile.Name())
-		} else {
-			fmt.Printf("\n✅ PR created successfully:\n%s\n", string(out))
+			fmt.Fprintf(progress, "\n⚠️  Could not create PR automatically: %v\n", err)
+			fmt.Fprintln(progress, "Report saved to:", tmpFile.Name())
+			fmt.Fprintln(progress, "\nYou can create the PR manually with:")
+			fmt.Fprintf(progress, "   gh pr create --title '📦 Dependency Updates' --body-file %s\n", tmpFile.Name())
+			return exitcode.Errorf(exitcode.External, "failed to create the report pull request: %w", err)
 		}
+		fmt.Fprintf(progress, "\n✅ PR created successfully:\n%s\n", string(out))
 	}
+	return nil
 }
 
 func init() {
@@ -338,6 +339,6 @@ func init() {
 	updateCmd.Flags().BoolVarP(&updatePR, "pr", "r", false, "Create pull request after pushing (requires gh cli)")
 	updateCmd.Flags().BoolVarP(&updateReport, "report", "e", false, "Generate a report with available updates and optionally create PR")
 	updateCmd.Flags().BoolVarP(&updateDryRun, "dry-run", "n", false, "Show what would be updated without making changes")
-	updateCmd.Flags().StringVarP(&updateConfigPath, "config", "c", "", "Path to update config file")
-	updateCmd.Flags().StringVarP(&updateBase, "base", "b", "main", "Base branch for PR")
+	updateCmd.Flags().StringVarP(&updateConfigPath, "config", "c", "", "Path to a standalone update config file (default: the projects key of the whiterose config)")
+	updateCmd.Flags().StringVarP(&updateBase, "base", "b", "", "Base branch for PR (default: git.base of the config, main)")
 }
@@ -1,22 +1,49 @@
 package config
 
 import (
-	"fmt"
 	"os"
-	"strings"
 
+	"github.com/fabianoflorentino/whiterose/internal/domain/entities"
+	"github.com/fabianoflorentino/whiterose/utils"
 	"github.com/spf13/viper"
 )
 
+// Config is the single configuration schema of whiterose. It is assembled by Loader from
+// defaults, the global and project-local files, environment variables and flags.
 type Config struct {
 	Git   GitConfig
 	SSH   SSHConfig
 	Image ImageConfig
 	Repo  RepoConfig
+
+	// Workers is the number of repositories processed concurrently; 0 means the default.
+	Workers int
+	// Credentials holds HTTPS credentials per Git host.
+	Credentials map[string]utils.HostCredential
+	// Repositories are the repositories cloned by setup.
+	Repositories []utils.RepoInfo
+	// Apps are the ids of the catalog applications checked by pre-req, before Applications.
+	Apps []string `jsonschema:"format=catalog-id"`
+	// Applications are the tools checked by pre-req. The loader adds those of Apps and completes
+	// the ones based on a catalog entry, see catalog.Resolve.
+	Applications []utils.AppInfo
+	// Projects are the projects updated by the update command.
+	Projects []entities.UpdateProject
+
+	// Profile is the active profile, applied on top of the other settings; see Loader.
+	Profile string
+	// Profiles are the named profiles that can be activated.
+	Profiles map[string]Profile
+
+	// Include lists the files merged before the file that declares it; see Loader.
+	Include []string
+
+	// Files lists the configuration files that were merged, lowest precedence first.
+	Files []string `mapstructure:"-"`
 }
 
 type GitConfig struct {
-	User string
+	User  string
 	Token string
 	Base  string
 }
@@ -29,64 +56,17 @@ type SSHConfig struct {
 type ImageConfig struct {
 	Name    string
 	Version string
+	// Path is the directory searched for the Dockerfile; empty means the working directory.
+	Path string
 }
 
 type RepoConfig struct {
 	Path string
 }
 
+// Load reads the configuration from the default locations; see Loader.
 func Load() (*Config, error) {
-	v := viper.New()
-
-	v.SetEnvPrefix("WHITEROSE")
-	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
-	v.AutomaticEnv()
-
-	v.SetDefault("git.base", "main")
-	v.SetDefault("ssh.keyName", "id_rsa")
-	v.SetDefault("image.name", "my_app")
-	v.SetDefault("image.version", "latest")
-	v.SetDefault("repo.path", ".config.json")
-
-	_ = v.BindEnv("git.user", "GIT_USER")
-	_ = v.BindEnv("git.token", "GIT_TOKEN")
-	_ = v.BindEnv("ssh.keyPath", "SSH_KEY_PATH")
-	_ = v.BindEnv("ssh.keyName", "SSH_KEY_NAME")
-	_ = v.BindEnv("image.name", "IMAGE_NAME")
-	_ = v.BindEnv("image.version", "IMAGE_VERSION")
-	_ = v.BindEnv("repo.path", "CONFIG_FILE", "WHITEROSE_REPO_PATH")
-
-	v.SetConfigName("whiterose")
-	v.SetConfigType("yaml")
-	v.AddConfigPath("$HOME/.config")
-	v.AddConfigPath(".")
-
-	if err := v.ReadInConfig(); err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-			return nil, fmt.Errorf("error reading config: %w", err)
-		}
-	}
-
-	cfg := &Config{
-		Git: GitConfig{
-			User: v.GetString("git.user"),
-			Token: v.GetString("git.token"),
-			Base: v.GetString("git.base"),
-		},
-		SSH: SSHConfig{
-			KeyPath: v.GetString("ssh.keyPath"),
-			KeyName: v.GetString("ssh.keyName"),
-		},
-		Image: ImageConfig{
-			Name:    v.GetString("image.name"),
-			Version: v.GetString("image.version"),
-		},
-		Repo: RepoConfig{
-			Path: v.GetString("repo.path"),
-		},
-	}
-
-	return cfg, nil
+	return NewLoader().Load()
 }
 
 func LoadOrDefault() *Config {
@@ -107,4 +87,4 @@ func GetConfigPath() string {
 		return path
 	}
 	return ".config.json"
-}
\ No newline at end of file
+}
@@ -91,4 +91,16 @@ func TestGetConfigPath_Default(t *testing.T) {
 
 func TestGetConfigPath_WithEnv(t *testing.T) {
 	t.Skip("Viper singleton state makes this test unreliable")
-}
\ No newline at end of file
+}
+func TestLoad_SecretReference(t *testing.T) {
+	t.Setenv("GIT_TOKEN", "secret://git-token")
+	t.Setenv("WHITEROSE_SECRET_GIT_TOKEN", "resolved-token")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Git.Token != "resolved-token" {
+		t.Errorf("Git.Token = %v, want resolved-token", cfg.Git.Token)
+	}
+}
@@ -0,0 +1,393 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"gopkg.in/yaml.v3"
+)
+
+// gitIncludePrefix marks an include read from a Git repository:
+// git::<repository URL>//<path in the repository>[?ref=<branch, tag or commit>].
+const gitIncludePrefix = "git::"
+
+const (
+	includeFile = "file"
+	includeHTTP = "http"
+	includeGit  = "git"
+)
+
+var (
+	// fetched holds the remote includes read by this process, so that loading the configuration
+	// again does not download or clone them again.
+	fetched   = map[string][]byte{}
+	fetchedMu sync.Mutex
+)
+
+// IncludeAuth returns the authentication of the Git repository of an include; without it the
+// repository is cloned anonymously. The git package sets it so that includes are authenticated
+// like repositories, with the HTTPS credentials of their own host and verified SSH host keys.
+var IncludeAuth func(rawURL string) (transport.AuthMethod, error)
+
+// listKeys names the field identifying an entry of the lists that includes merge entry by entry
+// instead of replacing them.
+var listKeys = map[string]string{
+	"repositories": "directory",
+	"applications": "name",
+	"projects":     "name",
+}
+
+// includeSource is a parsed entry of the include list.
+type includeSource struct {
+	kind     string
+	location string // file path, URL or repository URL
+	path     string // file inside the repository, for git includes
+	ref      string
+}
+
+func (s includeSource) String() string {
+	if s.kind != includeGit {
+		return s.location
+	}
+	str := gitIncludePrefix + s.location + "//" + s.path
+	if s.ref != "" {
+		str += "?ref=" + s.ref
+	}
+	return str
+}
+
+// parseInclude parses raw, resolving relative paths against the file that includes it: the
+// directory of a local file, the URL of a remote file, or the same repository and ref.
+func parseInclude(raw string, parent *includeSource) (includeSource, error) {
+	raw = strings.TrimSpace(raw)
+
+	switch {
+	case raw == "":
+		return includeSource{}, fmt.Errorf("empty include")
+	case strings.HasPrefix(raw, gitIncludePrefix):
+		rest, ref, _ := strings.Cut(strings.TrimPrefix(raw, gitIncludePrefix), "?ref=")
+		start := 0
+		if i := strings.Index(rest, "://"); i >= 0 {
+			start = i + len("://")
+		}
+		i := strings.Index(rest[start:], "//")
+		if i < 0 || start+i+2 == len(rest) {
+			return includeSource{}, fmt.Errorf("include %q: expected git::<repository>//<path>", raw)
+		}
+		return includeSource{kind: includeGit, location: rest[:start+i], path: rest[start+i+2:], ref: ref}, nil
+	case strings.HasPrefix(raw, "https://"), strings.HasPrefix(raw, "http://"):
+		return includeSource{kind: includeHTTP, location: raw}, nil
+	}
+
+	if raw == "~" || strings.HasPrefix(raw, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return includeSource{}, fmt.Errorf("include %q: %w", raw, err)
+		}
+		return includeSource{kind: includeFile, location: filepath.Join(home, raw[1:])}, nil
+	}
+	if parent == nil || filepath.IsAbs(raw) {
+		return includeSource{kind: includeFile, location: raw}, nil
+	}
+
+	switch parent.kind {
+	case includeHTTP:
+		base, err := url.Parse(parent.location)
+		if err != nil {
+			return includeSource{}, fmt.Errorf("include %q: %w", raw, err)
+		}
+		ref, err := url.Parse(filepath.ToSlash(raw))
+		if err != nil {
+			return includeSource{}, fmt.Errorf("include %q: %w", raw, err)
+		}
+		return includeSource{kind: includeHTTP, location: base.ResolveReference(ref).String()}, nil
+	case includeGit:
+		p := path.Join(path.Dir(parent.path), filepath.ToSlash(raw))
+		return includeSource{kind: includeGit, location: parent.location, path: p, ref: parent.ref}, nil
+	default:
+		return includeSource{kind: includeFile, location: filepath.Join(filepath.Dir(parent.location), raw)}, nil
+	}
+}
+
+// readTree reads src and the files it includes, merging each include in order and then the
+// content of src on top. stack holds the includes being read, to detect cycles.
+func (l *Loader) readTree(src includeSource, stack []string) (map[string]any, error) {
+	data, err := l.fetch(src)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := map[string]any{}
+	if err := yaml.Unmarshal(data, &tree); err != nil {
+		return nil, fmt.Errorf("error reading config %s: %w", src, err)
+	}
+	if _, err := interpolateTree(tree, l.templateData); err != nil {
+		return nil, fmt.Errorf("error reading config %s: %w", src, err)
+	}
+
+	includes, err := includeList(tree["include"])
+	if err != nil {
+		return nil, fmt.Errorf("error reading config %s: %w", src, err)
+	}
+
+	merged := map[string]any{}
+	for _, raw := range includes {
+		child, err := parseInclude(raw, &src)
+		if err != nil {
+			return nil, fmt.Errorf("error reading config %s: %w", src, err)
+		}
+		if slices.Contains(stack, child.String()) {
+			return nil, fmt.Errorf("include cycle: %s -> %s", strings.Join(stack, " -> "), child)
+		}
+
+		sub, err := l.readTree(child, append(stack, child.String()))
+		if err != nil {
+			return nil, err
+		}
+		delete(sub, "include")
+		mergeTrees(merged, sub)
+	}
+	mergeTrees(merged, tree)
+
+	return merged, nil
+}
+
+// includeList accepts a single include or a list of them.
+func includeList(value any) ([]string, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{v}, nil
+	case []any:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("include entries must be strings, got %v", item)
+			}
+			list = append(list, s)
+		}
+		return list, nil
+	default:
+		return nil, fmt.Errorf("include must be a string or a list, got %v", value)
+	}
+}
+
+// mergeTrees merges src into dst: maps are merged key by key, the lists named in listKeys entry
+// by entry, and any other value of src replaces the one in dst.
+func mergeTrees(dst, src map[string]any) {
+	for key, value := range src {
+		switch sv := value.(type) {
+		case map[string]any:
+			if dv, ok := dst[key].(map[string]any); ok {
+				mergeTrees(dv, sv)
+				continue
+			}
+		case []any:
+			if id, ok := listKeys[strings.ToLower(key)]; ok {
+				if dv, ok := dst[key].([]any); ok {
+					dst[key] = mergeList(dv, sv, id)
+					continue
+				}
+			}
+		}
+		dst[key] = value
+	}
+}
+
+// mergeList merges the entries of src into dst: an entry whose id field matches an entry of dst
+// overrides its fields, any other entry is appended.
+func mergeList(dst, src []any, id string) []any {
+	merged := slices.Clone(dst)
+	for _, item := range src {
+		entry, ok := item.(map[string]any)
+		if !ok {
+			merged = append(merged, item)
+			continue
+		}
+
+		i := slices.IndexFunc(merged, func(existing any) bool {
+			m, ok := existing.(map[string]any)
+			return ok && entry[id] != nil && fmt.Sprint(m[id]) == fmt.Sprint(entry[id])
+		})
+		if i < 0 {
+			merged = append(merged, entry)
+			continue
+		}
+
+		// Copy the entry so merging into it leaves the included tree untouched.
+		override := maps.Clone(merged[i].(map[string]any))
+		mergeTrees(override, entry)
+		merged[i] = override
+	}
+	return merged
+}
+
+// fetch returns the content of src. Remote includes are cached, and the cached copy is used when
+// the fetch fails or WHITEROSE_OFFLINE is set.
+func (l *Loader) fetch(src includeSource) ([]byte, error) {
+	if src.kind == includeFile {
+		data, err := os.ReadFile(src.location)
+		if err != nil {
+			return nil, fmt.Errorf("error reading config %s: %w", src, err)
+		}
+		return data, nil
+	}
+
+	key := src.String()
+	if !l.refresh {
+		fetchedMu.Lock()
+		data, ok := fetched[key]
+		fetchedMu.Unlock()
+		if ok {
+			return data, nil
+		}
+	}
+
+	data, err := l.fetchRemote(src)
+	if err != nil {
+		return nil, err
+	}
+	fetchedMu.Lock()
+	fetched[key] = data
+	fetchedMu.Unlock()
+	return data, nil
+}
+
+// fetchRemote downloads a remote include and caches it in cacheDir, falling back to the cached
+// copy when the download fails. Offline, it only reads the cached copy.
+func (l *Loader) fetchRemote(src includeSource) ([]byte, error) {
+	var cached string
+	if l.cacheDir != "" {
+		sum := sha256.Sum256([]byte(src.String()))
+		cached = filepath.Join(l.cacheDir, hex.EncodeToString(sum[:]))
+	}
+
+	if !l.offline {
+		data, err := downloadInclude(src)
+		if err == nil {
+			if cached != "" {
+				if err := writeCache(cached, data); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: failed to cache include %s: %v\n", src, err)
+				}
+			}
+			return data, nil
+		}
+		if cached == "" {
+			return nil, fmt.Errorf("failed to fetch include %s: %w", src, err)
+		}
+
+		data, cacheErr := os.ReadFile(cached)
+		if cacheErr != nil {
+			return nil, fmt.Errorf("failed to fetch include %s: %w", src, err)
+		}
+		fmt.Fprintf(os.Stderr, "Warning: failed to fetch include %s, using the cached copy: %v\n", src, err)
+		return data, nil
+	}
+
+	if cached == "" {
+		return nil, fmt.Errorf("include %s is not cached", src)
+	}
+	data, err := os.ReadFile(cached)
+	if err != nil {
+		return nil, fmt.Errorf("include %s is not cached, run once without WHITEROSE_OFFLINE: %w", src, err)
+	}
+	return data, nil
+}
+
+func writeCache(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+func downloadInclude(src includeSource) ([]byte, error) {
+	if src.kind == includeGit {
+		return fetchGitFile(src)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(src.location)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// fetchGitFile clones the repository of src in memory and returns the file at its ref: a branch,
+// a tag, or any revision such as a commit hash. Without a ref the default branch is used.
+func fetchGitFile(src includeSource) ([]byte, error) {
+	var auth transport.AuthMethod
+	var err error
+	if IncludeAuth != nil {
+		if auth, err = IncludeAuth(src.location); err != nil {
+			return nil, fmt.Errorf("failed to authenticate to %s: %w", src.location, err)
+		}
+	}
+
+	// A branch or tag clone leaves HEAD at the ref; a commit is resolved in the full history.
+	revision := plumbing.Revision("HEAD")
+	var repo *git.Repository
+	if src.ref == "" {
+		repo, err = git.Clone(memory.NewStorage(), nil, &git.CloneOptions{URL: src.location, Auth: auth, Depth: 1})
+	} else {
+		for _, name := range []plumbing.ReferenceName{plumbing.NewBranchReferenceName(src.ref), plumbing.NewTagReferenceName(src.ref)} {
+			repo, err = git.Clone(memory.NewStorage(), nil, &git.CloneOptions{
+				URL: src.location, Auth: auth, ReferenceName: name, SingleBranch: true, Depth: 1,
+			})
+			if err == nil {
+				break
+			}
+		}
+		if err != nil {
+			repo, err = git.Clone(memory.NewStorage(), nil, &git.CloneOptions{URL: src.location, Auth: auth})
+			revision = plumbing.Revision(src.ref)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to clone %s: %w", src.location, err)
+	}
+
+	hash, err := repo.ResolveRevision(revision)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s in %s: %w", revision, src.location, err)
+	}
+
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commit %s of %s: %w", hash, src.location, err)
+	}
+	file, err := commit.File(src.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s at %s: %w", src.path, hash, err)
+	}
+	contents, err := file.Contents()
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(contents), nil
+}
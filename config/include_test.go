@@ -0,0 +1,210 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestParseInclude(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	local := &includeSource{kind: includeFile, location: "/etc/team/whiterose.yaml"}
+	remote := &includeSource{kind: includeHTTP, location: "https://example.com/config/team.yaml"}
+	repo := &includeSource{kind: includeGit, location: "git@github.com:org/config.git", path: "teams/backend.yaml", ref: "v1"}
+
+	tests := []struct {
+		raw    string
+		parent *includeSource
+		want   string
+		kind   string
+	}{
+		{"base.yaml", local, "/etc/team/base.yaml", includeFile},
+		{"/abs/base.yaml", local, "/abs/base.yaml", includeFile},
+		{"~/team.yaml", local, filepath.Join(home, "team.yaml"), includeFile},
+		{"base.yaml", nil, "base.yaml", includeFile},
+		{"../base.yaml", remote, "https://example.com/base.yaml", includeHTTP},
+		{"common.yaml", repo, "git::git@github.com:org/config.git//teams/common.yaml?ref=v1", includeGit},
+		{"https://example.com/a.yaml", local, "https://example.com/a.yaml", includeHTTP},
+		{"git::https://github.com/org/config.git//team.yaml?ref=main", nil, "git::https://github.com/org/config.git//team.yaml?ref=main", includeGit},
+		{"git::git@github.com:org/config.git//dir/team.yaml", nil, "git::git@github.com:org/config.git//dir/team.yaml", includeGit},
+	}
+
+	for _, tt := range tests {
+		got, err := parseInclude(tt.raw, tt.parent)
+		if err != nil {
+			t.Errorf("parseInclude(%q) error = %v", tt.raw, err)
+			continue
+		}
+		if got.String() != tt.want || got.kind != tt.kind {
+			t.Errorf("parseInclude(%q) = %s (%s), want %s (%s)", tt.raw, got, got.kind, tt.want, tt.kind)
+		}
+	}
+
+	for _, raw := range []string{"", "git::https://github.com/org/config.git", "git::git@github.com:org/config.git//"} {
+		if _, err := parseInclude(raw, nil); err == nil {
+			t.Errorf("parseInclude(%q) succeeded", raw)
+		}
+	}
+}
+
+func TestLoader_Includes(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("CONFIG_FILE", "")
+
+	writeConfig(t, dir, "common.yaml", `workers: 2
+applications:
+  - name: Go
+    command: go
+`)
+	if err := os.Mkdir(filepath.Join(dir, "team"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeConfig(t, filepath.Join(dir, "team"), "team.yaml", `include: ../common.yaml
+git:
+  base: develop
+repositories:
+  - url: https://github.com/org/api.git
+    directory: api
+  - url: https://github.com/org/web.git
+    directory: web
+`)
+	local := writeConfig(t, dir, "whiterose.yaml", `include:
+  - team/team.yaml
+workers: 6
+repositories:
+  - directory: web
+    branch: feature
+  - url: https://github.com/me/tools.git
+    directory: tools
+`)
+
+	cfg, err := NewLoader().WithLegacyFile("").WithGlobalFile("").WithLocalFile(local).WithCacheDir("").Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.Workers != 6 || cfg.Git.Base != "develop" || len(cfg.Applications) != 1 {
+		t.Errorf("Workers = %d, Git.Base = %q, Applications = %+v", cfg.Workers, cfg.Git.Base, cfg.Applications)
+	}
+	if len(cfg.Repositories) != 3 {
+		t.Fatalf("Repositories = %+v, want api, web and tools", cfg.Repositories)
+	}
+	web := cfg.Repositories[1]
+	if web.Directory != "web" || web.URL != "https://github.com/org/web.git" || web.Branch != "feature" {
+		t.Errorf("web = %+v, want the team entry with the local branch", web)
+	}
+	if cfg.Repositories[2].Directory != "tools" {
+		t.Errorf("Repositories[2] = %+v, want tools appended", cfg.Repositories[2])
+	}
+
+	writeConfig(t, dir, "common.yaml", "include: team/team.yaml\n")
+	_, err = NewLoader().WithLegacyFile("").WithGlobalFile("").WithLocalFile(local).WithCacheDir("").Load()
+	if err == nil || !strings.Contains(err.Error(), "include cycle") {
+		t.Errorf("Load() error = %v, want an include cycle", err)
+	}
+}
+
+func TestLoader_RemoteIncludes(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	// A Git repository whose team.yaml changes after the v1 tag.
+	repoDir := t.TempDir()
+	repo, err := git.PlainInit(repoDir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commit := func(content string) string {
+		t.Helper()
+		writeConfig(t, repoDir, "team.yaml", content)
+		wt, err := repo.Worktree()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := wt.Add("team.yaml"); err != nil {
+			t.Fatal(err)
+		}
+		sig := &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()}
+		hash, err := wt.Commit("update team.yaml", &git.CommitOptions{Author: sig})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return hash.String()
+	}
+	first := commit("workers: 3\n")
+	if _, err := repo.CreateTag("v1", plumbing.NewHash(first), nil); err != nil {
+		t.Fatal(err)
+	}
+	commit("workers: 9\n")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/config/team.yaml":
+			_, _ = w.Write([]byte("include: image.yaml\ngit:\n  base: develop\n"))
+		case "/config/image.yaml":
+			_, _ = w.Write([]byte("image:\n  name: team-image\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	cacheDir := filepath.Join(dir, "cache")
+	local := writeConfig(t, dir, "whiterose.yaml", "include:\n  - git::"+repoDir+"//team.yaml?ref=v1\n  - "+server.URL+"/config/team.yaml\n")
+	loader := func() *Loader {
+		return NewLoader().WithLegacyFile("").WithGlobalFile("").WithLocalFile(local).WithCacheDir(cacheDir).WithOffline(false)
+	}
+
+	cfg, err := loader().Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Workers != 3 || cfg.Git.Base != "develop" || cfg.Image.Name != "team-image" {
+		t.Errorf("Workers = %d, Git.Base = %q, Image.Name = %q, want 3 from v1 and the HTTP includes", cfg.Workers, cfg.Git.Base, cfg.Image.Name)
+	}
+
+	pinned := writeConfig(t, dir, "pinned.yaml", "include: git::"+repoDir+"//team.yaml?ref="+first+"\n")
+	cfg, err = loader().WithLocalFile(pinned).Load()
+	if err != nil || cfg.Workers != 3 {
+		t.Errorf("Load() pinned to a commit = %+v, %v, want workers 3", cfg, err)
+	}
+	head := writeConfig(t, dir, "head.yaml", "include: git::"+repoDir+"//team.yaml\n")
+	cfg, err = loader().WithLocalFile(head).Load()
+	if err != nil || cfg.Workers != 9 {
+		t.Errorf("Load() of the default branch = %+v, %v, want workers 9", cfg, err)
+	}
+
+	// Both remotes are gone: this process still has the includes it read, and refreshing them
+	// falls back to the cached copies.
+	server.Close()
+	if err := os.RemoveAll(repoDir); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = loader().WithCacheDir("").Load()
+	if err != nil || cfg.Workers != 3 || cfg.Image.Name != "team-image" {
+		t.Errorf("Load() again in the same process = %+v, %v, want the includes read before", cfg, err)
+	}
+	for _, l := range []*Loader{loader().WithRefresh(true), loader().WithRefresh(true).WithOffline(true)} {
+		cfg, err = l.Load()
+		if err != nil {
+			t.Fatalf("Load() from the cache error = %v", err)
+		}
+		if cfg.Workers != 3 || cfg.Image.Name != "team-image" {
+			t.Errorf("cached Workers = %d, Image.Name = %q", cfg.Workers, cfg.Image.Name)
+		}
+	}
+
+	if _, err := loader().WithRefresh(true).WithCacheDir(t.TempDir()).Load(); err == nil {
+		t.Error("Load() without remotes nor cache succeeded")
+	}
+}
@@ -0,0 +1,121 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// envPattern matches ${VAR} and ${VAR:-default}; a leading "$$" escapes the expansion.
+var envPattern = regexp.MustCompile(`\$?\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}`)
+
+// TemplateData holds the values available to {{ }} templates in configuration values.
+type TemplateData struct {
+	User string
+	Home string
+	Env  map[string]string
+}
+
+// NewTemplateData returns the template values of the current user and environment.
+func NewTemplateData() TemplateData {
+	data := TemplateData{User: os.Getenv("USER"), Env: map[string]string{}}
+	if data.User == "" {
+		if u, err := user.Current(); err == nil {
+			data.User = u.Username
+		}
+	}
+	data.Home, _ = os.UserHomeDir()
+
+	for _, kv := range os.Environ() {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			data.Env[k] = v
+		}
+	}
+
+	return data
+}
+
+// Interpolate expands a configuration value: Go templates such as {{ .User }}, {{ .Home }} and
+// {{ .Env.GOPATH }}, then ${VAR} and ${VAR:-default} (the default applies when VAR is unset or
+// empty), then a leading "~" to the home directory.
+func Interpolate(value string, data TemplateData) (string, error) {
+	if strings.Contains(value, "{{") {
+		t, err := template.New("value").Option("missingkey=zero").Parse(value)
+		if err != nil {
+			return "", fmt.Errorf("invalid template %q: %w", value, err)
+		}
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, data); err != nil {
+			return "", fmt.Errorf("invalid template %q: %w", value, err)
+		}
+		value = buf.String()
+	}
+
+	value = envPattern.ReplaceAllStringFunc(value, func(match string) string {
+		if strings.HasPrefix(match, "$$") {
+			return match[1:]
+		}
+		groups := envPattern.FindStringSubmatch(match)
+		if v := os.Getenv(groups[1]); v != "" {
+			return v
+		}
+		return groups[2]
+	})
+
+	if (value == "~" || strings.HasPrefix(value, "~/")) && data.Home != "" {
+		value = filepath.Join(data.Home, value[1:])
+	}
+
+	return value, nil
+}
+
+// interpolateTree expands every string of a decoded configuration file in place.
+func interpolateTree(node any, data TemplateData) (any, error) {
+	switch v := node.(type) {
+	case string:
+		return Interpolate(v, data)
+	case map[string]any:
+		for key, value := range v {
+			expanded, err := interpolateTree(value, data)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			v[key] = expanded
+		}
+	case []any:
+		for i, value := range v {
+			expanded, err := interpolateTree(value, data)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			v[i] = expanded
+		}
+	}
+	return node, nil
+}
+
+// InterpolateYAML expands every string of a YAML or JSON document, for files read outside of
+// Loader such as a standalone update config.
+func InterpolateYAML(data []byte) ([]byte, error) {
+	var tree any
+	if err := yaml.Unmarshal(data, &tree); err != nil {
+		return nil, err
+	}
+	if tree == nil {
+		return data, nil
+	}
+
+	tree, err := interpolateTree(tree, NewTemplateData())
+	if err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(tree)
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInterpolate(t *testing.T) {
+	t.Setenv("WR_ORG", "acme")
+	t.Setenv("WR_EMPTY", "")
+
+	data := TemplateData{User: "jdoe", Home: "/home/jdoe", Env: map[string]string{"GOPATH": "/go"}}
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"plain", "plain"},
+		{"git@github.com:${WR_ORG}/api.git", "git@github.com:acme/api.git"},
+		{"${WR_MISSING:-fallback}/api", "fallback/api"},
+		{"${WR_EMPTY:-fallback}", "fallback"},
+		{"${WR_MISSING}", ""},
+		{"$${WR_ORG} and $WR_ORG", "${WR_ORG} and $WR_ORG"},
+		{"feature/{{ .User }}", "feature/jdoe"},
+		{"{{ .Home }}/src", "/home/jdoe/src"},
+		{"{{ .Env.GOPATH }}/src/{{ .Env.UNSET }}", "/go/src/"},
+		{"~/projects/api", "/home/jdoe/projects/api"},
+		{"~", "/home/jdoe"},
+		{"a/~/b", "a/~/b"},
+		{"~/${WR_ORG}", "/home/jdoe/acme"},
+	}
+
+	for _, tt := range tests {
+		got, err := Interpolate(tt.value, data)
+		if err != nil {
+			t.Errorf("Interpolate(%q) error = %v", tt.value, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Interpolate(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+
+	if _, err := Interpolate("{{ .User", data); err == nil {
+		t.Error("Interpolate() of an invalid template succeeded")
+	}
+}
+
+func TestLoader_Interpolation(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("WR_ORG", "acme")
+
+	local := writeConfig(t, dir, "whiterose.yaml", `repositories:
+  - url: git@github.com:${WR_ORG}/api.git
+    directory: ~/src/${WR_DIR:-api}
+    createBranchTemplate: dev/{{ .User }}
+projects:
+  - name: api
+    path: "{{ .Home }}/src/api"
+`)
+
+	loader := NewLoader().WithLegacyFile("").WithGlobalFile("").WithLocalFile(local)
+	loader.templateData = TemplateData{User: "jdoe", Home: "/home/jdoe"}
+
+	cfg, err := loader.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	repo := cfg.Repositories[0]
+	if repo.URL != "git@github.com:acme/api.git" || repo.Directory != "/home/jdoe/src/api" || repo.CreateBranchTemplate != "dev/jdoe" {
+		t.Errorf("repository = %+v", repo)
+	}
+	if cfg.Projects[0].Path != "/home/jdoe/src/api" {
+		t.Errorf("project path = %q", cfg.Projects[0].Path)
+	}
+
+	bad := writeConfig(t, dir, "bad.yaml", "image:\n  name: \"{{ .Nope \"\n")
+	if _, err := loader.WithLocalFile(bad).Load(); err == nil || !strings.Contains(err.Error(), "bad.yaml") {
+		t.Errorf("Load() error = %v, want the invalid template reported with its file", err)
+	}
+}
+
+func TestInterpolateYAML(t *testing.T) {
+	t.Setenv("WR_ORG", "acme")
+
+	out, err := InterpolateYAML([]byte(`{"projects": [{"name": "api", "path": "./${WR_ORG}"}]}`))
+	if err != nil {
+		t.Fatalf("InterpolateYAML() error = %v", err)
+	}
+	if !strings.Contains(string(out), "path: ./acme") {
+		t.Errorf("InterpolateYAML() = %s", out)
+	}
+}
@@ -0,0 +1,327 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fabianoflorentino/whiterose/catalog"
+	"github.com/fabianoflorentino/whiterose/exitcode"
+	"github.com/fabianoflorentino/whiterose/secrets"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// keyDelimiter separates nested configuration keys, e.g. "git::token".
+const keyDelimiter = "::"
+
+// Loader assembles a Config from several layers, each overriding the previous one:
+//
+//  1. defaults
+//  2. the legacy repositories file (CONFIG_FILE or ~/.config.{yml,yaml,json})
+//  3. the global file (WHITEROSE_CONFIG or ~/.config/whiterose.yaml)
+//  4. the project-local file (whiterose.yaml or .whiterose.yaml in the working directory)
+//  5. the active profile (WithProfile or SelectProfile, WHITEROSE_PROFILE, or the profile key)
+//  6. environment variables (WHITEROSE_<KEY> and the historical GIT_USER, SSH_KEY_NAME, ...)
+//  7. flags such as --workers, bound with WithFlags or SelectFlags
+//
+// Maps and scalars are merged key by key; a list such as repositories is replaced as a whole
+// by the layer that defines it.
+//
+// A file may include others with the include key: local paths, http(s) URLs or files in a Git
+// repository (git::<repository>//<path>?ref=<ref>). Includes are merged first and the including
+// file on top of them, with repositories, applications and projects merged entry by entry so a
+// file can add to or override a shared list. Remote includes are fetched once per process
+// and cached on disk for offline use.
+//
+// Every string of a file, includes too, is expanded with Interpolate before it is merged.
+type Loader struct {
+	legacyFile string
+	globalFile string
+	localFile  string
+	flags      *pflag.FlagSet
+	cacheDir   string
+	offline    bool
+	refresh    bool
+	profile    string
+
+	templateData TemplateData
+}
+
+// NewLoader creates a Loader reading the files from their default locations.
+func NewLoader() *Loader {
+	return &Loader{
+		legacyFile: LegacyFilePath(),
+		globalFile: GlobalFilePath(),
+		localFile:  LocalFilePath(),
+		cacheDir:   IncludeCacheDir(),
+		offline:    os.Getenv("WHITEROSE_OFFLINE") != "",
+		refresh:    os.Getenv("WHITEROSE_REFRESH") != "",
+		profile:    selectedProfile,
+		flags:      selectedFlags,
+
+		templateData: NewTemplateData(),
+	}
+}
+
+// WithLegacyFile replaces the legacy repositories file; an empty path disables it.
+func (l *Loader) WithLegacyFile(path string) *Loader {
+	l.legacyFile = path
+	return l
+}
+
+// WithGlobalFile replaces the global configuration file; an empty path disables it.
+func (l *Loader) WithGlobalFile(path string) *Loader {
+	l.globalFile = path
+	return l
+}
+
+// WithLocalFile replaces the project-local configuration file; an empty path disables it.
+func (l *Loader) WithLocalFile(path string) *Loader {
+	l.localFile = path
+	return l
+}
+
+// WithCacheDir replaces the directory caching remote includes; an empty path disables the cache.
+func (l *Loader) WithCacheDir(path string) *Loader {
+	l.cacheDir = path
+	return l
+}
+
+// WithOffline makes remote includes read from the cache only.
+func (l *Loader) WithOffline(offline bool) *Loader {
+	l.offline = offline
+	return l
+}
+
+// WithRefresh makes remote includes fetched again even when this process already read them.
+func (l *Loader) WithRefresh(refresh bool) *Loader {
+	l.refresh = refresh
+	return l
+}
+
+// WithProfile activates the named profile; an empty name falls back to WHITEROSE_PROFILE and the
+// profile key of the configuration files.
+func (l *Loader) WithProfile(name string) *Loader {
+	l.profile = name
+	return l
+}
+
+// WithFlags binds the changed flags of flagKeys, e.g. --workers, on top of the other layers.
+func (l *Loader) WithFlags(flags *pflag.FlagSet) *Loader {
+	l.flags = flags
+	return l
+}
+
+// flagKeys maps the command-line flags that override a configuration key to that key. Flags
+// sharing the name of another key, such as pre-req --apps, select rather than configure and are
+// not bound.
+var flagKeys = map[string]string{
+	"workers": "workers",
+	"base":    "git::base",
+}
+
+// selectedFlags are the flags of the running command, see SelectFlags.
+var selectedFlags *pflag.FlagSet
+
+// SelectFlags makes the Loaders created afterwards bind the changed flags of flagKeys, so that
+// the flags of the running command take precedence over every other layer.
+func SelectFlags(flags *pflag.FlagSet) {
+	selectedFlags = flags
+}
+
+// Load merges every layer and returns the resulting configuration. Errors carry the
+// exitcode.Config exit status.
+func (l *Loader) Load() (*Config, error) {
+	cfg, err := l.load()
+	if err != nil {
+		return nil, exitcode.Wrap(exitcode.Config, err)
+	}
+	return cfg, nil
+}
+
+// load merges every layer into a Config.
+func (l *Loader) load() (*Config, error) {
+	// Hosts such as "gitlab.example.com" are map keys, so "." cannot separate nested keys.
+	v := viper.NewWithOptions(viper.KeyDelimiter(keyDelimiter))
+
+	v.SetDefault("git::base", "main")
+	v.SetDefault("ssh::keyName", "id_rsa")
+	v.SetDefault("image::name", "my_app:latest")
+	v.SetDefault("image::version", "latest")
+	v.SetDefault("repo::path", ".config.json")
+	v.SetDefault("workers", 0)
+
+	files, err := l.Files()
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		src := includeSource{kind: includeFile, location: f}
+		tree, err := l.readTree(src, []string{src.String()})
+		if err != nil {
+			return nil, err
+		}
+		if err := v.MergeConfigMap(tree); err != nil {
+			return nil, fmt.Errorf("error reading config %s: %w", f, err)
+		}
+	}
+
+	v.SetEnvPrefix("WHITEROSE")
+	v.SetEnvKeyReplacer(strings.NewReplacer(keyDelimiter, "_"))
+	v.AutomaticEnv()
+
+	_ = v.BindEnv("git::user", "GIT_USER")
+	_ = v.BindEnv("git::token", "GIT_TOKEN")
+	_ = v.BindEnv("ssh::keyPath", "SSH_KEY_PATH")
+	_ = v.BindEnv("ssh::keyName", "SSH_KEY_NAME")
+	_ = v.BindEnv("image::name", "IMAGE_NAME")
+	_ = v.BindEnv("image::version", "IMAGE_VERSION")
+	_ = v.BindEnv("image::path", "DOCKERFILE_PATH")
+	_ = v.BindEnv("repo::path", "CONFIG_FILE", "WHITEROSE_REPO_PATH")
+	_ = v.BindEnv("workers", "WHITEROSE_WORKERS")
+
+	if err := l.applyProfile(v); err != nil {
+		return nil, err
+	}
+
+	if l.flags != nil {
+		l.flags.VisitAll(func(f *pflag.Flag) {
+			if key, ok := flagKeys[f.Name]; ok && f.Changed {
+				_ = v.BindPFlag(key, f)
+			}
+		})
+	}
+
+	cfg := &Config{}
+	if err := v.Unmarshal(cfg); err != nil {
+		return nil, fmt.Errorf("error decoding config: %w", err)
+	}
+	cfg.Files = files
+
+	token, err := secrets.Resolve(cfg.Git.Token)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving git.token: %w", err)
+	}
+	cfg.Git.Token = token
+
+	apps, err := catalog.Resolve(cfg.Apps, cfg.Applications)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving applications: %w", err)
+	}
+	cfg.Applications = apps
+
+	return cfg, nil
+}
+
+// applyProfile merges the active profile over the configuration files. Environment variables and
+// flags still take precedence over it.
+func (l *Loader) applyProfile(v *viper.Viper) error {
+	name := l.profile
+	if name == "" {
+		name = v.GetString("profile")
+	}
+	if name == "" {
+		return nil
+	}
+
+	// Viper lowercases keys, so profile names are matched regardless of case.
+	profiles := v.GetStringMap("profiles")
+	profile, ok := profiles[strings.ToLower(name)].(map[string]any)
+	if !ok {
+		names := make([]string, 0, len(profiles))
+		for n := range profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("unknown profile %q, available: %s", name, strings.Join(names, ", "))
+	}
+
+	v.Set("profile", strings.ToLower(name))
+	if err := v.MergeConfigMap(profile); err != nil {
+		return fmt.Errorf("error applying profile %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// Files returns the configuration files that exist, lowest precedence first. A CONFIG_FILE
+// pointing to a missing file is an error.
+func (l *Loader) Files() ([]string, error) {
+	var files []string
+	for _, f := range []string{l.legacyFile, l.globalFile, l.localFile} {
+		if f == "" {
+			continue
+		}
+		if _, err := os.Stat(f); errors.Is(err, os.ErrNotExist) {
+			if f == l.legacyFile && f == os.Getenv("CONFIG_FILE") {
+				return nil, fmt.Errorf("config file %s set in CONFIG_FILE does not exist", f)
+			}
+			continue
+		}
+		files = append(files, f)
+	}
+
+	return files, nil
+}
+
+// LegacyFilePath returns the repositories file used before the unified configuration:
+// CONFIG_FILE when set, otherwise the first of ~/.config.yml, ~/.config.yaml and ~/.config.json
+// that exists, or an empty string.
+func LegacyFilePath() string {
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	for _, name := range []string{".config.yml", ".config.yaml", ".config.json"} {
+		path := filepath.Join(home, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+
+	return ""
+}
+
+// GlobalFilePath returns WHITEROSE_CONFIG, falling back to ~/.config/whiterose.yaml.
+func GlobalFilePath() string {
+	if path := os.Getenv("WHITEROSE_CONFIG"); path != "" {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(home, ".config", "whiterose.yaml")
+}
+
+// IncludeCacheDir returns the directory caching remote includes, under the user cache directory.
+func IncludeCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "whiterose", "includes")
+}
+
+// LocalFilePath returns whiterose.yaml or .whiterose.yaml in the working directory,
+// whichever exists, defaulting to whiterose.yaml.
+func LocalFilePath() string {
+	for _, name := range []string{"whiterose.yaml", ".whiterose.yaml"} {
+		if _, err := os.Stat(name); err == nil {
+			return name
+		}
+	}
+
+	return "whiterose.yaml"
+}
@@ -0,0 +1,208 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fabianoflorentino/whiterose/exitcode"
+	"github.com/spf13/pflag"
+)
+
+func writeConfig(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestLoader_Defaults(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("IMAGE_NAME", "")
+	t.Setenv("IMAGE_VERSION", "")
+
+	cfg, err := NewLoader().WithLegacyFile("").WithGlobalFile("").WithLocalFile("").Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Git.Base != "main" || cfg.Image.Name != "my_app:latest" || cfg.Image.Version != "latest" {
+		t.Errorf("Git.Base = %q, Image = %+v, want main and my_app:latest", cfg.Git.Base, cfg.Image)
+	}
+}
+
+func TestLoader_Layers(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("IMAGE_NAME", "")
+
+	legacy := writeConfig(t, dir, ".config.json", `{
+  "workers": 2,
+  "repositories": [{"url": "https://github.com/org/legacy", "directory": "legacy"}],
+  "applications": [{"name": "Go", "command": "go", "versionFlag": "version"}]
+}`)
+	global := writeConfig(t, dir, "whiterose.yaml", `git:
+  base: develop
+image:
+  name: global-image
+workers: 6
+credentials:
+  gitlab.example.com:
+    token: glpat
+`)
+	local := writeConfig(t, dir, "local.yaml", `image:
+  version: "2.0"
+repositories:
+  - url: https://gitlab.example.com/team/api.git
+    directory: api
+    fallbackBranch: main
+    tags: [go]
+projects:
+  - name: api
+    path: ./api
+    goMod:
+      updateStrategy: minor
+`)
+
+	cfg, err := NewLoader().WithLegacyFile(legacy).WithGlobalFile(global).WithLocalFile(local).Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(cfg.Files, []string{legacy, global, local}) {
+		t.Errorf("Files = %v", cfg.Files)
+	}
+	if cfg.Git.Base != "develop" || cfg.SSH.KeyName != "id_rsa" {
+		t.Errorf("Git.Base = %q, SSH.KeyName = %q, want develop and the id_rsa default", cfg.Git.Base, cfg.SSH.KeyName)
+	}
+	if cfg.Image.Name != "global-image" || cfg.Image.Version != "2.0" {
+		t.Errorf("Image = %+v, want global-image:2.0 merged from both files", cfg.Image)
+	}
+	if cfg.Workers != 6 {
+		t.Errorf("Workers = %d, want 6 from the global file", cfg.Workers)
+	}
+	if len(cfg.Repositories) != 1 || cfg.Repositories[0].Directory != "api" || cfg.Repositories[0].FallbackBranch != "main" ||
+		!reflect.DeepEqual(cfg.Repositories[0].Tags, []string{"go"}) {
+		t.Errorf("Repositories = %+v, want the local list", cfg.Repositories)
+	}
+	if len(cfg.Applications) != 1 || cfg.Applications[0].Command != "go" {
+		t.Errorf("Applications = %+v, want the legacy list", cfg.Applications)
+	}
+	if cfg.Credentials["gitlab.example.com"].Token != "glpat" {
+		t.Errorf("Credentials = %+v", cfg.Credentials)
+	}
+	if len(cfg.Projects) != 1 || cfg.Projects[0].GoMod == nil || cfg.Projects[0].GoMod.UpdateStrategy != "minor" {
+		t.Errorf("Projects = %+v", cfg.Projects)
+	}
+}
+
+func TestLoader_EnvAndFlagsOverrideFiles(t *testing.T) {
+	dir := t.TempDir()
+	global := writeConfig(t, dir, "whiterose.yaml", "workers: 6\nimage:\n  name: from-file\n")
+
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("IMAGE_NAME", "from-env")
+	t.Setenv("WHITEROSE_WORKERS", "8")
+
+	loader := NewLoader().WithLegacyFile("").WithGlobalFile(global).WithLocalFile("")
+
+	cfg, err := loader.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Image.Name != "from-env" || cfg.Workers != 8 {
+		t.Errorf("Image.Name = %q, Workers = %d, want env values", cfg.Image.Name, cfg.Workers)
+	}
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.Int("workers", 0, "")
+	flags.Bool("unrelated", false, "")
+	flags.String("base", "", "")
+	flags.StringSlice("apps", nil, "")
+	if err := flags.Parse([]string{"--workers", "12", "--unrelated", "--base", "develop", "--apps", "kubectl"}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	cfg, err = loader.WithFlags(flags).Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Workers != 12 || cfg.Git.Base != "develop" {
+		t.Errorf("Workers = %d, Git.Base = %q, want 12 and develop from the flags", cfg.Workers, cfg.Git.Base)
+	}
+	if len(cfg.Apps) != 0 {
+		t.Errorf("Apps = %v, --apps selects applications and must not be bound", cfg.Apps)
+	}
+
+	SelectFlags(flags)
+	defer SelectFlags(nil)
+	cfg, err = NewLoader().WithLegacyFile("").WithGlobalFile(global).WithLocalFile("").Load()
+	if err != nil || cfg.Workers != 12 {
+		t.Errorf("Load() after SelectFlags = %v, %v, want 12 workers", cfg, err)
+	}
+}
+
+func TestLoader_CatalogApplications(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+	dir := t.TempDir()
+
+	global := writeConfig(t, dir, "whiterose.yaml", "apps: [kubectl, helm]\n")
+	local := writeConfig(t, dir, "local.yaml", `applications:
+  - catalog: helm
+    recommendedVersion: ">=3.14"
+  - name: Custom
+    command: custom
+`)
+
+	cfg, err := NewLoader().WithLegacyFile("").WithGlobalFile(global).WithLocalFile(local).Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	apps := cfg.Applications
+	if len(apps) != 3 || apps[0].Command != "kubectl" || apps[1].Command != "helm" || apps[2].Name != "Custom" {
+		t.Fatalf("Applications = %+v, want kubectl, helm and Custom", apps)
+	}
+	if apps[1].RecommendedVersion != ">=3.14" || apps[1].VersionFlag != "version --short" {
+		t.Errorf("Applications[1] = %+v, want the catalog entry of helm with the overridden version", apps[1])
+	}
+
+	unknown := writeConfig(t, dir, "unknown.yaml", "apps: [kubeclt]\n")
+	_, err = NewLoader().WithLegacyFile("").WithGlobalFile(unknown).WithLocalFile("").Load()
+	if err == nil || !strings.Contains(err.Error(), `unknown catalog application "kubeclt"`) || exitcode.Of(err) != exitcode.Config {
+		t.Errorf("Load() error = %v, want an unknown catalog application configuration error", err)
+	}
+}
+
+func TestLoader_MissingConfigFileEnv(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.json")
+	t.Setenv("CONFIG_FILE", missing)
+
+	if _, err := NewLoader().WithGlobalFile("").WithLocalFile("").Load(); err == nil {
+		t.Error("Load() with a missing CONFIG_FILE succeeded")
+	}
+}
+
+func TestLegacyFilePath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("CONFIG_FILE", "")
+
+	if got := LegacyFilePath(); got != "" {
+		t.Errorf("LegacyFilePath() = %q, want empty without a config file", got)
+	}
+
+	writeConfig(t, home, ".config.json", "{}")
+	yml := writeConfig(t, home, ".config.yml", "")
+	if got := LegacyFilePath(); got != yml {
+		t.Errorf("LegacyFilePath() = %q, want %q", got, yml)
+	}
+
+	t.Setenv("CONFIG_FILE", "/some/path.yaml")
+	if got := LegacyFilePath(); got != "/some/path.yaml" {
+		t.Errorf("LegacyFilePath() = %q, want CONFIG_FILE", got)
+	}
+}
@@ -0,0 +1,149 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fabianoflorentino/whiterose/internal/domain/entities"
+	"github.com/fabianoflorentino/whiterose/output"
+	"github.com/fabianoflorentino/whiterose/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// Profile is a named set of settings, such as the repositories and credentials of a client,
+// applied on top of the configuration when it is active. Maps are merged key by key and the
+// lists of the profile replace those of the configuration.
+type Profile struct {
+	Git          GitConfig
+	SSH          SSHConfig
+	Image        ImageConfig
+	Workers      int
+	Credentials  map[string]utils.HostCredential
+	Repositories []utils.RepoInfo
+	Apps         []string `jsonschema:"format=catalog-id"`
+	Applications []utils.AppInfo
+	Projects     []entities.UpdateProject
+}
+
+// selectedProfile is the profile chosen with --profile.
+var selectedProfile string
+
+// SelectProfile makes the Loaders created afterwards use the named profile, taking precedence
+// over WHITEROSE_PROFILE and the profile key of the configuration files.
+func SelectProfile(name string) {
+	selectedProfile = name
+}
+
+// ProfileNames returns the names of the configured profiles, sorted.
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ProfileList is the list of configured profiles and the name of the active one.
+type ProfileList struct {
+	Active   string   `json:"active"`
+	Profiles []string `json:"profiles"`
+}
+
+// ProfileList returns the configured profiles and the active one.
+func (c *Config) ProfileList() ProfileList {
+	return ProfileList{Active: c.Profile, Profiles: c.ProfileNames()}
+}
+
+// Text writes one profile per line, the active one marked with an asterisk.
+func (l ProfileList) Text(w io.Writer) {
+	if len(l.Profiles) == 0 {
+		fmt.Fprintln(w, "No profiles configured.")
+		return
+	}
+	for _, name := range l.Profiles {
+		marker := " "
+		if name == l.Active {
+			marker = "*"
+		}
+		fmt.Fprintf(w, "%s %s\n", marker, name)
+	}
+}
+
+// Table lays the profiles out one per row.
+func (l ProfileList) Table() output.Table {
+	t := output.Table{Header: []string{"PROFILE", "ACTIVE"}}
+	for _, name := range l.Profiles {
+		t.Rows = append(t.Rows, []string{name, strconv.FormatBool(name == l.Active)})
+	}
+	return t
+}
+
+// SaveProfile sets the profile key of the configuration file at path, creating the file when it
+// does not exist. Other keys and, in YAML files, comments are kept.
+func SaveProfile(path, name string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		tree := map[string]any{}
+		if len(data) > 0 {
+			if err := json.Unmarshal(data, &tree); err != nil {
+				return fmt.Errorf("failed to parse %s: %w", path, err)
+			}
+		}
+		tree["profile"] = name
+		if data, err = json.MarshalIndent(tree, "", "  "); err != nil {
+			return fmt.Errorf("failed to encode %s: %w", path, err)
+		}
+		data = append(data, '\n')
+	} else {
+		if data, err = setYAMLKey(data, "profile", name); err != nil {
+			return fmt.Errorf("failed to update %s: %w", path, err)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// setYAMLKey sets a top-level scalar key of a YAML document, appending it when missing.
+func setYAMLKey(data []byte, key, value string) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("the document is not a mapping")
+	}
+
+	if existing := mappingValue(root, key); existing != nil {
+		*existing = yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+	} else {
+		root.Content = append(root.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value},
+		)
+	}
+
+	return marshalYAML(&doc)
+}
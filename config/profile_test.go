@@ -0,0 +1,147 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const profilesConfig = `profile: work
+git:
+  base: main
+image:
+  name: my_app
+repositories:
+  - url: git@github.com:me/dotfiles.git
+    directory: dotfiles
+profiles:
+  work:
+    git:
+      base: develop
+    repositories:
+      - url: git@gitlab.work.com:team/api.git
+        directory: api
+  Client-X:
+    git:
+      base: trunk
+      token: ${CLIENT_X_TOKEN}
+    image:
+      name: registry.client-x.com/app
+    credentials:
+      git.client-x.com:
+        token: glpat
+`
+
+func TestLoader_Profiles(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("IMAGE_NAME", "")
+	t.Setenv("WHITEROSE_PROFILE", "")
+	t.Setenv("CLIENT_X_TOKEN", "cx-token")
+	global := writeConfig(t, dir, "whiterose.yaml", profilesConfig)
+
+	loader := func() *Loader {
+		return NewLoader().WithLegacyFile("").WithGlobalFile(global).WithLocalFile("")
+	}
+
+	// The saved profile key selects work.
+	cfg, err := loader().Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Profile != "work" || cfg.Git.Base != "develop" || len(cfg.Repositories) != 1 || cfg.Repositories[0].Directory != "api" {
+		t.Errorf("work profile: Profile = %q, Git.Base = %q, Repositories = %+v", cfg.Profile, cfg.Git.Base, cfg.Repositories)
+	}
+	if !reflect.DeepEqual(cfg.ProfileNames(), []string{"client-x", "work"}) {
+		t.Errorf("ProfileNames() = %v", cfg.ProfileNames())
+	}
+
+	// WHITEROSE_PROFILE overrides the saved profile, and WithProfile overrides both.
+	t.Setenv("WHITEROSE_PROFILE", "client-x")
+	for _, l := range []*Loader{loader(), loader().WithProfile("CLIENT-X")} {
+		cfg, err = l.Load()
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if cfg.Profile != "client-x" || cfg.Git.Base != "trunk" || cfg.Git.Token != "cx-token" || cfg.Image.Name != "registry.client-x.com/app" {
+			t.Errorf("client-x profile: %+v", cfg)
+		}
+		if cfg.Repositories[0].Directory != "dotfiles" || cfg.Credentials["git.client-x.com"].Token != "glpat" {
+			t.Errorf("client-x profile: Repositories = %+v, Credentials = %+v", cfg.Repositories, cfg.Credentials)
+		}
+	}
+
+	// Environment variables still override the profile.
+	t.Setenv("IMAGE_NAME", "from-env")
+	if cfg, err = loader().Load(); err != nil || cfg.Image.Name != "from-env" {
+		t.Errorf("Load() = %+v, %v, want IMAGE_NAME over the profile", cfg, err)
+	}
+
+	if _, err := loader().WithProfile("missing").Load(); err == nil || !strings.Contains(err.Error(), "available: client-x, work") {
+		t.Errorf("Load() error = %v, want the available profiles", err)
+	}
+}
+
+func TestSelectProfile(t *testing.T) {
+	t.Cleanup(func() { SelectProfile("") })
+
+	SelectProfile("personal")
+	if got := NewLoader().profile; got != "personal" {
+		t.Errorf("NewLoader().profile = %q, want personal", got)
+	}
+}
+
+func TestSaveProfile(t *testing.T) {
+	dir := t.TempDir()
+
+	yamlPath := writeConfig(t, dir, "whiterose.yaml", "# team settings\nprofile: work\nworkers: 4 # tuned\n")
+	if err := SaveProfile(yamlPath, "client-x"); err != nil {
+		t.Fatalf("SaveProfile() error = %v", err)
+	}
+	data, _ := os.ReadFile(yamlPath)
+	if got := string(data); !strings.Contains(got, "profile: client-x") || !strings.Contains(got, "# team settings") || !strings.Contains(got, "workers: 4 # tuned") {
+		t.Errorf("SaveProfile() wrote:\n%s", got)
+	}
+
+	created := filepath.Join(dir, "nested", "new.yaml")
+	if err := SaveProfile(created, "work"); err != nil {
+		t.Fatalf("SaveProfile() error = %v", err)
+	}
+	if data, _ := os.ReadFile(created); string(data) != "profile: work\n" {
+		t.Errorf("SaveProfile() created %q", data)
+	}
+
+	jsonPath := writeConfig(t, dir, "config.json", `{"workers": 2}`)
+	if err := SaveProfile(jsonPath, "work"); err != nil {
+		t.Fatalf("SaveProfile() error = %v", err)
+	}
+	var tree map[string]any
+	data, _ = os.ReadFile(jsonPath)
+	if err := json.Unmarshal(data, &tree); err != nil || tree["profile"] != "work" || tree["workers"] != float64(2) {
+		t.Errorf("SaveProfile() wrote %s (%v)", data, err)
+	}
+}
+
+func TestConfig_ProfileList(t *testing.T) {
+	cfg := &Config{Profile: "work", Profiles: map[string]Profile{"work": {}, "client-x": {}}}
+
+	list := cfg.ProfileList()
+	if list.Active != "work" || !reflect.DeepEqual(list.Profiles, []string{"client-x", "work"}) {
+		t.Errorf("ProfileList() = %+v", list)
+	}
+
+	var out strings.Builder
+	list.Text(&out)
+	if got := out.String(); got != "  client-x\n* work\n" {
+		t.Errorf("Text() = %q", got)
+	}
+
+	out.Reset()
+	(&Config{}).ProfileList().Text(&out)
+	if got := out.String(); got != "No profiles configured.\n" {
+		t.Errorf("Text() without profiles = %q", got)
+	}
+}
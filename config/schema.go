@@ -0,0 +1,119 @@
+package config
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// SchemaURI is the JSON Schema dialect of the generated schema.
+const SchemaURI = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is the subset of JSON Schema used to describe the configuration.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties any                `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Format               string             `json:"format,omitempty"`
+}
+
+// GenerateSchema builds the JSON Schema of Config from its Go types. Property names follow the
+// yaml tags, or the lowerCamelCase field name when there is none. A `jsonschema` tag adds
+// constraints: "required", "enum=a|b" and "format=name".
+func GenerateSchema() *Schema {
+	s := schemaFor(reflect.TypeOf(Config{}))
+	s.Schema = SchemaURI
+	s.Title = "whiterose configuration"
+	return s
+}
+
+func schemaFor(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		s := &Schema{Type: "object", Properties: map[string]*Schema{}, AdditionalProperties: false}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name := fieldName(f)
+			if name == "" {
+				continue
+			}
+
+			prop := schemaFor(f.Type)
+			for _, opt := range strings.Split(f.Tag.Get("jsonschema"), ",") {
+				key, value, _ := strings.Cut(opt, "=")
+				switch key {
+				case "required":
+					s.Required = append(s.Required, name)
+				case "enum":
+					prop.Enum = strings.Split(value, "|")
+				case "format":
+					// The format of a list applies to its items, e.g. the catalog ids of apps.
+					if prop.Type == "array" {
+						prop.Items.Format = value
+					} else {
+						prop.Format = value
+					}
+				}
+			}
+			s.Properties[name] = prop
+		}
+		sort.Strings(s.Required)
+		return s
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: schemaFor(t.Elem())}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: schemaFor(t.Elem())}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	default:
+		return &Schema{Type: "string"}
+	}
+}
+
+// fieldName returns the configuration key of a struct field, or "" when the field is not configurable.
+func fieldName(f reflect.StructField) string {
+	if !f.IsExported() || f.Tag.Get("mapstructure") == "-" {
+		return ""
+	}
+
+	if tag, _, _ := strings.Cut(f.Tag.Get("yaml"), ","); tag != "" {
+		if tag == "-" {
+			return ""
+		}
+		return tag
+	}
+
+	return lowerCamel(f.Name)
+}
+
+// lowerCamel lowercases the leading capitals of name, so "Git" becomes "git", "SSH" becomes "ssh"
+// and "KeyPath" becomes "keyPath".
+func lowerCamel(name string) string {
+	runes := []rune(name)
+	for i := range runes {
+		if !unicode.IsUpper(runes[i]) {
+			break
+		}
+		// Keep the capital that starts the next word, e.g. the "P" of "SSHPath".
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLowerCamel(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Git", "git"},
+		{"SSH", "ssh"},
+		{"KeyPath", "keyPath"},
+		{"SSHPath", "sshPath"},
+		{"URL", "url"},
+	}
+
+	for _, tt := range tests {
+		if got := lowerCamel(tt.name); got != tt.want {
+			t.Errorf("lowerCamel(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateSchema(t *testing.T) {
+	s := GenerateSchema()
+
+	if s.Schema != SchemaURI || s.Type != "object" || s.AdditionalProperties != false {
+		t.Errorf("root = %+v, want a closed object", s)
+	}
+	for _, name := range []string{"git", "ssh", "workers", "credentials", "repositories", "applications", "projects"} {
+		if s.Properties[name] == nil {
+			t.Errorf("missing property %q", name)
+		}
+	}
+	if _, ok := s.Properties["files"]; ok {
+		t.Error("files is computed and should not be in the schema")
+	}
+
+	repo := s.Properties["repositories"].Items
+	if !reflect.DeepEqual(repo.Required, []string{"directory", "url"}) {
+		t.Errorf("repository required = %v", repo.Required)
+	}
+	if repo.Properties["url"].Format != "git-url" {
+		t.Errorf("url format = %q, want git-url", repo.Properties["url"].Format)
+	}
+
+	strategy := s.Properties["projects"].Items.Properties["goMod"].Properties["updateStrategy"]
+	if !reflect.DeepEqual(strategy.Enum, []string{"patch", "minor", "major"}) {
+		t.Errorf("updateStrategy enum = %v", strategy.Enum)
+	}
+
+	if _, ok := s.Properties["credentials"].AdditionalProperties.(*Schema); !ok {
+		t.Error("credentials should accept any host as a key")
+	}
+}
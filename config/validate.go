@@ -0,0 +1,390 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fabianoflorentino/whiterose/catalog"
+	"github.com/fabianoflorentino/whiterose/internal/domain/entities"
+	"github.com/fabianoflorentino/whiterose/output"
+	"github.com/fabianoflorentino/whiterose/semver"
+	"gopkg.in/yaml.v3"
+)
+
+// Issue is a problem found in a configuration file.
+type Issue struct {
+	File    string `json:"file"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+func (i Issue) String() string {
+	if i.Line == 0 {
+		return fmt.Sprintf("%s: %s", i.File, i.Message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", i.File, i.Line, i.Column, i.Message)
+}
+
+// ValidateFile checks a JSON or YAML configuration file against the schema of Config and
+// returns every issue found, in file order.
+func ValidateFile(path string) ([]Issue, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	return ValidateData(path, data, GenerateSchema()), nil
+}
+
+// FileValidation is the outcome of validating one configuration file.
+type FileValidation struct {
+	File   string  `json:"file"`
+	Valid  bool    `json:"valid"`
+	Issues []Issue `json:"issues"`
+	// Error is set when the file could not be read.
+	Error string `json:"error,omitempty"`
+}
+
+// ValidationReport is the outcome of validating a set of configuration files.
+type ValidationReport []FileValidation
+
+// ValidateFiles validates every file, recording the files that cannot be read instead of stopping.
+func ValidateFiles(paths []string) ValidationReport {
+	report := make(ValidationReport, 0, len(paths))
+	for _, path := range paths {
+		result := FileValidation{File: path, Issues: []Issue{}}
+		issues, err := ValidateFile(path)
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Issues = append(result.Issues, issues...)
+			result.Valid = len(issues) == 0
+		}
+		report = append(report, result)
+	}
+	return report
+}
+
+// Valid reports whether every file was read and has no issues.
+func (r ValidationReport) Valid() bool {
+	for _, f := range r {
+		if !f.Valid {
+			return false
+		}
+	}
+	return true
+}
+
+// Text writes one line per issue, or "file: ok" for the valid files.
+func (r ValidationReport) Text(w io.Writer) {
+	for _, f := range r {
+		switch {
+		case f.Error != "":
+			fmt.Fprintf(w, "Error: %s\n", f.Error)
+		case f.Valid:
+			fmt.Fprintf(w, "%s: ok\n", f.File)
+		default:
+			for _, issue := range f.Issues {
+				fmt.Fprintln(w, issue)
+			}
+		}
+	}
+}
+
+// Table lays the issues out one per row, with a single row for each valid or unreadable file.
+func (r ValidationReport) Table() output.Table {
+	t := output.Table{Header: []string{"FILE", "LINE", "COLUMN", "MESSAGE"}}
+	for _, f := range r {
+		switch {
+		case f.Error != "":
+			t.Rows = append(t.Rows, []string{f.File, "-", "-", f.Error})
+		case f.Valid:
+			t.Rows = append(t.Rows, []string{f.File, "-", "-", "ok"})
+		}
+		for _, issue := range f.Issues {
+			t.Rows = append(t.Rows, []string{f.File, strconv.Itoa(issue.Line), strconv.Itoa(issue.Column), issue.Message})
+		}
+	}
+	return t
+}
+
+// ValidateData checks the content of a configuration file against schema. Besides the schema
+// (unknown fields, types, required fields, enums, URLs, version constraints and regular
+// expressions) it reports repositories sharing a directory and applications without a name or
+// command that are not based on a catalog entry. Required fields are not checked in a
+// file with includes, whose entries may complete included ones. Strings are expanded with
+// Interpolate first, so that values are checked as the loader reads them.
+func ValidateData(file string, data []byte, schema *Schema) []Issue {
+	v := &validator{file: file}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		v.add(nil, "invalid syntax: %v", err)
+		return v.issues
+	}
+	if len(doc.Content) == 0 {
+		return nil
+	}
+
+	root := doc.Content[0]
+	v.interpolate(root, NewTemplateData())
+	v.partial = mappingValue(root, "include") != nil
+	v.check(root, schema, "")
+	v.checkDuplicateDirectories(root)
+	v.checkApplications(root, "applications")
+	if profiles := mappingValue(root, "profiles"); profiles != nil && profiles.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(profiles.Content); i += 2 {
+			path := joinPath(joinPath("profiles", profiles.Content[i].Value), "applications")
+			v.checkApplications(profiles.Content[i+1], path)
+		}
+	}
+
+	slices.SortStableFunc(v.issues, func(a, b Issue) int {
+		if a.Line != b.Line {
+			return a.Line - b.Line
+		}
+		return a.Column - b.Column
+	})
+
+	return v.issues
+}
+
+type validator struct {
+	file    string
+	partial bool
+	issues  []Issue
+}
+
+func (v *validator) add(n *yaml.Node, format string, args ...any) {
+	issue := Issue{File: v.file, Message: fmt.Sprintf(format, args...)}
+	if n != nil {
+		issue.Line, issue.Column = n.Line, n.Column
+	}
+	v.issues = append(v.issues, issue)
+}
+
+// interpolate expands the string values of n and its children in place, like the loader does
+// before decoding a file; mapping keys are left as written.
+func (v *validator) interpolate(n *yaml.Node, data TemplateData) {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		if n.Tag != "!!str" {
+			return
+		}
+		expanded, err := Interpolate(n.Value, data)
+		if err != nil {
+			v.add(n, "%v", err)
+			return
+		}
+		n.Value = expanded
+	case yaml.MappingNode:
+		for i := 1; i < len(n.Content); i += 2 {
+			v.interpolate(n.Content[i], data)
+		}
+	case yaml.SequenceNode:
+		for _, item := range n.Content {
+			v.interpolate(item, data)
+		}
+	}
+}
+
+// check validates n and its children against s; path names n in messages.
+func (v *validator) check(n *yaml.Node, s *Schema, path string) {
+	if n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	if n.Kind == yaml.ScalarNode && n.Tag == "!!null" {
+		return
+	}
+
+	switch s.Type {
+	case "object":
+		v.checkObject(n, s, path)
+	case "array":
+		// The loader decodes a single string as a list of one, e.g. "include: team.yaml".
+		if n.Kind == yaml.ScalarNode && s.Items.Type == "string" {
+			v.checkScalar(n, s.Items, path)
+			return
+		}
+		if n.Kind != yaml.SequenceNode {
+			v.add(n, "%s: expected a list", displayPath(path))
+			return
+		}
+		for i, item := range n.Content {
+			v.check(item, s.Items, fmt.Sprintf("%s[%d]", path, i))
+		}
+	default:
+		v.checkScalar(n, s, path)
+	}
+}
+
+func (v *validator) checkObject(n *yaml.Node, s *Schema, path string) {
+	if n.Kind != yaml.MappingNode {
+		v.add(n, "%s: expected an object", displayPath(path))
+		return
+	}
+
+	seen := map[string]bool{}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, value := n.Content[i], n.Content[i+1]
+		childPath := joinPath(path, key.Value)
+
+		if s.Properties != nil {
+			name, prop := lookupProperty(s, key.Value)
+			if prop != nil {
+				seen[name] = true
+				v.check(value, prop, childPath)
+				continue
+			}
+		}
+
+		if values, ok := s.AdditionalProperties.(*Schema); ok {
+			v.check(value, values, childPath)
+			continue
+		}
+		v.add(key, "unknown field %q in %s", key.Value, displayPath(path))
+	}
+
+	for _, name := range s.Required {
+		if !seen[name] && !v.partial {
+			v.add(n, "%s: missing required field %q", displayPath(path), name)
+		}
+	}
+}
+
+func (v *validator) checkScalar(n *yaml.Node, s *Schema, path string) {
+	if n.Kind != yaml.ScalarNode {
+		v.add(n, "%s: expected a %s", displayPath(path), s.Type)
+		return
+	}
+
+	switch s.Type {
+	case "integer":
+		if n.Tag != "!!int" {
+			v.add(n, "%s: expected an integer, got %q", displayPath(path), n.Value)
+		}
+	case "number":
+		if n.Tag != "!!int" && n.Tag != "!!float" {
+			v.add(n, "%s: expected a number, got %q", displayPath(path), n.Value)
+		}
+	case "boolean":
+		if n.Tag != "!!bool" {
+			v.add(n, "%s: expected true or false, got %q", displayPath(path), n.Value)
+		}
+	}
+
+	if len(s.Enum) > 0 && !slices.ContainsFunc(s.Enum, func(e string) bool { return strings.EqualFold(e, n.Value) }) {
+		v.add(n, "%s: invalid value %q, expected one of %s", displayPath(path), n.Value, strings.Join(s.Enum, ", "))
+	}
+
+	switch s.Format {
+	case "git-url":
+		if err := entities.ValidateURL(n.Value); err != nil {
+			v.add(n, "%s: invalid repository URL %q: %v", displayPath(path), n.Value, err)
+		}
+	case "version-constraint":
+		if _, err := semver.ParseConstraint(n.Value); err != nil {
+			v.add(n, "%s: %v", displayPath(path), err)
+		}
+	case "regex":
+		if _, err := regexp.Compile(n.Value); err != nil {
+			v.add(n, "%s: invalid regular expression %q: %v", displayPath(path), n.Value, err)
+		}
+	case "catalog-id":
+		if _, ok := catalog.Lookup(n.Value); !ok {
+			v.add(n, "%s: unknown catalog application %q", displayPath(path), n.Value)
+		}
+	case "duration":
+		if d, err := time.ParseDuration(n.Value); err != nil || d <= 0 {
+			v.add(n, "%s: invalid duration %q, expected a positive value such as 10s", displayPath(path), n.Value)
+		}
+	}
+}
+
+// checkDuplicateDirectories reports repositories cloned into a directory already used by another one.
+func (v *validator) checkDuplicateDirectories(root *yaml.Node) {
+	repos := mappingValue(root, "repositories")
+	if repos == nil || repos.Kind != yaml.SequenceNode {
+		return
+	}
+
+	lines := map[string]int{}
+	for _, repo := range repos.Content {
+		dir := mappingValue(repo, "directory")
+		if dir == nil || dir.Kind != yaml.ScalarNode || dir.Value == "" {
+			continue
+		}
+		if line, ok := lines[dir.Value]; ok {
+			v.add(dir, "duplicate directory %q, already used on line %d", dir.Value, line)
+			continue
+		}
+		lines[dir.Value] = dir.Line
+	}
+}
+
+// checkApplications reports the applications of n that have no name or command and are not
+// based on a catalog entry, which would provide them.
+func (v *validator) checkApplications(n *yaml.Node, path string) {
+	apps := mappingValue(n, "applications")
+	if apps == nil || apps.Kind != yaml.SequenceNode || v.partial {
+		return
+	}
+
+	for i, app := range apps.Content {
+		if app.Kind != yaml.MappingNode || mappingValue(app, "catalog") != nil {
+			continue
+		}
+		for _, field := range []string{"name", "command"} {
+			if mappingValue(app, field) == nil {
+				v.add(app, "%s[%d]: missing required field %q", path, i, field)
+			}
+		}
+	}
+}
+
+// lookupProperty finds a property by name, ignoring case like the loader does.
+func lookupProperty(s *Schema, key string) (string, *Schema) {
+	if prop, ok := s.Properties[key]; ok {
+		return key, prop
+	}
+	for name, prop := range s.Properties {
+		if strings.EqualFold(name, key) {
+			return name, prop
+		}
+	}
+	return "", nil
+}
+
+// mappingValue returns the value of key in a mapping node, ignoring case.
+func mappingValue(n *yaml.Node, key string) *yaml.Node {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if strings.EqualFold(n.Content[i].Value, key) {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func displayPath(path string) string {
+	if path == "" {
+		return "top level"
+	}
+	return path
+}
@@ -0,0 +1,201 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateData(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name: "valid",
+			content: `git:
+  base: main
+workers: 4
+credentials:
+  gitlab.example.com:
+    token: secret://gitlab
+repositories:
+  - url: https://github.com/org/api.git
+    directory: api
+  - url: ssh://git@github.com/org/web.git
+    directory: web
+projects:
+  - name: api
+    path: ./api
+    goMod:
+      updateStrategy: Minor
+`,
+		},
+		{
+			name:    "unknown field",
+			content: "git:\n  base: main\n  branch: dev\n",
+			want:    []string{`f.yaml:3:3: unknown field "branch" in git`},
+		},
+		{
+			name: "invalid strategy",
+			content: `projects:
+  - name: api
+    path: ./api
+    goVersion:
+      updateStrategy: latest
+`,
+			want: []string{`f.yaml:5:23: projects[0].goVersion.updateStrategy: invalid value "latest", expected one of patch, minor, major`},
+		},
+		{
+			name: "bad url and missing directory",
+			content: `repositories:
+  - url: ftp://example.com/repo
+`,
+			want: []string{
+				`f.yaml:2:5: repositories[0]: missing required field "directory"`,
+				`f.yaml:2:10: repositories[0].url: invalid repository URL "ftp://example.com/repo"`,
+			},
+		},
+		{
+			name: "duplicate directory",
+			content: `repositories:
+  - url: https://github.com/org/a.git
+    directory: app
+  - url: https://github.com/org/b.git
+    directory: app
+`,
+			want: []string{`f.yaml:5:16: duplicate directory "app", already used on line 3`},
+		},
+		{
+			name: "entry completing an include",
+			content: `include: team.yaml
+repositories:
+  - directory: api
+    branch: feature
+`,
+		},
+		{
+			name: "invalid version constraint and regex",
+			content: `applications:
+  - name: Go
+    command: go
+    recommendedVersion: latest
+    versionRegex: "go(\\d+"
+`,
+			want: []string{
+				`f.yaml:4:25: applications[0].recommendedVersion: invalid version constraint "latest"`,
+				`f.yaml:5:19: applications[0].versionRegex: invalid regular expression "go(\\d+"`,
+			},
+		},
+		{
+			name: "catalog applications",
+			content: `apps: [kubectl, kubeclt]
+applications:
+  - catalog: helm
+    recommendedVersion: ">=3.14"
+  - name: Custom
+  - catalog: nope
+`,
+			want: []string{
+				`f.yaml:1:17: apps[1]: unknown catalog application "kubeclt"`,
+				`f.yaml:5:5: applications[1]: missing required field "command"`,
+				`f.yaml:6:14: applications[2].catalog: unknown catalog application "nope"`,
+			},
+		},
+		{
+			name: "invalid timeout",
+			content: `applications:
+  - name: Docker
+    command: docker
+    timeout: 10
+`,
+			want: []string{`f.yaml:4:14: applications[0].timeout: invalid duration "10", expected a positive value such as 10s`},
+		},
+		{
+			name: "interpolated values",
+			content: `repositories:
+  - url: ${WHITEROSE_TEST_GIT_HOST:-https://github.com}/org/api.git
+    directory: api
+applications:
+  - name: Go
+    command: go
+    recommendedVersion: "${WHITEROSE_TEST_GO_VERSION:->=1.25}"
+`,
+		},
+		{
+			name:    "invalid template",
+			content: "git:\n  base: \"{{ .User \"\n",
+			want:    []string{`f.yaml:2:9: invalid template`},
+		},
+		{
+			name:    "wrong type",
+			content: "workers: many\n",
+			want:    []string{`f.yaml:1:10: workers: expected an integer, got "many"`},
+		},
+		{
+			name:    "syntax error",
+			content: "git: [\n",
+			want:    []string{"f.yaml: invalid syntax"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := ValidateData("f.yaml", []byte(tt.content), GenerateSchema())
+			if len(issues) != len(tt.want) {
+				t.Fatalf("ValidateData() = %v, want %d issues", issues, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if got := issues[i].String(); !strings.HasPrefix(got, want) {
+					t.Errorf("issue %d = %q, want prefix %q", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateFile_JSON(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), ".config.json", `{
+  "repositories": [
+    {"url": "https://github.com/org/api.git", "directory": "api", "fallback": "main"}
+  ]
+}`)
+
+	issues, err := ValidateFile(path)
+	if err != nil {
+		t.Fatalf("ValidateFile() error = %v", err)
+	}
+	if len(issues) != 1 || issues[0].Line != 3 || !strings.Contains(issues[0].Message, `"fallback"`) {
+		t.Errorf("ValidateFile() = %v, want the unknown fallback field on line 3", issues)
+	}
+}
+
+func TestValidateFiles(t *testing.T) {
+	dir := t.TempDir()
+	good := writeConfig(t, dir, "good.yaml", "workers: 2\n")
+	bad := writeConfig(t, dir, "bad.yaml", "workers: many\n")
+	missing := dir + "/missing.yaml"
+
+	report := ValidateFiles([]string{good, bad, missing})
+	if len(report) != 3 || report.Valid() {
+		t.Fatalf("ValidateFiles() = %+v, want 3 files and an invalid report", report)
+	}
+	if !report[0].Valid || len(report[0].Issues) != 0 {
+		t.Errorf("good file = %+v", report[0])
+	}
+	if report[1].Valid || len(report[1].Issues) != 1 || report[1].Issues[0].Line != 1 {
+		t.Errorf("bad file = %+v", report[1])
+	}
+	if report[2].Valid || !strings.Contains(report[2].Error, "missing.yaml") {
+		t.Errorf("missing file = %+v", report[2])
+	}
+
+	var out strings.Builder
+	report.Text(&out)
+	if got := out.String(); !strings.HasPrefix(got, good+": ok\n"+bad+":1:") || !strings.Contains(got, "Error: ") {
+		t.Errorf("Text() =\n%s", got)
+	}
+	if rows := report.Table().Rows; len(rows) != 3 || rows[1][1] != "1" {
+		t.Errorf("Table().Rows = %v", rows)
+	}
+}
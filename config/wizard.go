@@ -0,0 +1,272 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/fabianoflorentino/whiterose/catalog"
+	"github.com/fabianoflorentino/whiterose/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// knownApp returns the application of the catalog entry whose id or command is name. Only the
+// fields identifying it are written, so the loader completes it with the current catalog.
+func knownApp(name string) (utils.AppInfo, bool) {
+	app, ok := catalog.Lookup(name)
+	if !ok {
+		return utils.AppInfo{}, false
+	}
+	return utils.AppInfo{Catalog: app.Catalog, Name: app.Name, Command: app.Command, VersionFlag: app.VersionFlag}, true
+}
+
+// DiscoverFunc returns the repositories already cloned under a directory.
+type DiscoverFunc func(dir string) ([]utils.RepoInfo, error)
+
+// Wizard asks the questions needed to write a first configuration file: the Git host and
+// organization, the authentication method, the repositories and the applications to check.
+type Wizard struct {
+	in       *bufio.Reader
+	out      io.Writer
+	discover DiscoverFunc
+}
+
+// NewWizard creates a Wizard reading answers from in and writing questions to out.
+func NewWizard(in io.Reader, out io.Writer) *Wizard {
+	return &Wizard{in: bufio.NewReader(in), out: out}
+}
+
+// WithDiscovery lets the wizard import repositories from a directory of existing clones.
+func (w *Wizard) WithDiscovery(discover DiscoverFunc) *Wizard {
+	w.discover = discover
+	return w
+}
+
+// Run asks every question and returns the resulting configuration.
+func (w *Wizard) Run() (*utils.ConfigFile, error) {
+	cfg := &utils.ConfigFile{}
+
+	host, err := w.ask("Git host", "github.com")
+	if err != nil {
+		return nil, err
+	}
+	org, err := w.ask("Organization or user", "")
+	if err != nil {
+		return nil, err
+	}
+	method, err := w.choose("Authentication method", []string{"ssh", "https"})
+	if err != nil {
+		return nil, err
+	}
+
+	if method == "https" {
+		user, err := w.ask("HTTPS username", "git")
+		if err != nil {
+			return nil, err
+		}
+		token, err := w.ask("HTTPS token or secret:// reference", "secret://"+strings.ReplaceAll(host, ".", "-")+"-token")
+		if err != nil {
+			return nil, err
+		}
+		cfg.Credentials = map[string]utils.HostCredential{host: {Username: user, Token: token}}
+	}
+
+	if w.discover != nil {
+		dir, err := w.ask("Import existing clones from directory (empty to skip)", "")
+		if err != nil {
+			return nil, err
+		}
+		if dir != "" {
+			repos, err := w.discover(dir)
+			if err != nil {
+				return nil, err
+			}
+			fmt.Fprintf(w.out, "Found %d repositories in %s\n", len(repos), dir)
+			cfg.Repositories = append(cfg.Repositories, repos...)
+		}
+	}
+
+	for {
+		name, err := w.ask("Repository name or URL (empty to finish)", "")
+		if err != nil {
+			return nil, err
+		}
+		if name == "" {
+			break
+		}
+
+		// "api" and "other-org/api" are expanded with the host; anything with a scheme or an
+		// scp-like "host:" is used as is.
+		url := name
+		if !strings.Contains(name, ":") {
+			repoOrg, repoName := org, name
+			if i := strings.LastIndex(name, "/"); i >= 0 {
+				repoOrg, repoName = name[:i], name[i+1:]
+			}
+			if repoOrg == "" {
+				fmt.Fprintln(w.out, "No organization was given, enter org/name or a full URL")
+				continue
+			}
+			url = RepositoryURL(host, repoOrg, repoName, method)
+		}
+
+		dir, err := w.ask("Directory", strings.TrimSuffix(path.Base(url), ".git"))
+		if err != nil {
+			return nil, err
+		}
+		cfg.Repositories = append(cfg.Repositories, utils.RepoInfo{URL: url, Directory: dir})
+	}
+
+	apps, err := w.ask("Applications to check (comma-separated)", "git, go, docker")
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range strings.Split(apps, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		app, ok := knownApp(name)
+		if !ok {
+			app = utils.AppInfo{Name: name, Command: name, VersionFlag: "--version"}
+		}
+		cfg.Applications = append(cfg.Applications, app)
+	}
+
+	return cfg, nil
+}
+
+// Confirm asks a yes/no question, returning def when the answer is empty.
+func (w *Wizard) Confirm(question string, def bool) (bool, error) {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	for {
+		answer, err := w.ask(fmt.Sprintf("%s (%s)", question, hint), "")
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(answer) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		fmt.Fprintln(w.out, "Please answer yes or no")
+	}
+}
+
+// ask prints question with its default and returns the trimmed answer, or def when the answer
+// is empty or the input has ended.
+func (w *Wizard) ask(question, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(w.out, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(w.out, "%s: ", question)
+	}
+
+	line, err := w.in.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read answer: %w", err)
+	}
+	if errors.Is(err, io.EOF) {
+		fmt.Fprintln(w.out)
+	}
+
+	if answer := strings.TrimSpace(line); answer != "" {
+		return answer, nil
+	}
+	return def, nil
+}
+
+// choose asks until the answer is one of options; the first option is the default.
+func (w *Wizard) choose(question string, options []string) (string, error) {
+	for {
+		answer, err := w.ask(fmt.Sprintf("%s (%s)", question, strings.Join(options, "/")), options[0])
+		if err != nil {
+			return "", err
+		}
+		for _, opt := range options {
+			if strings.EqualFold(answer, opt) {
+				return opt, nil
+			}
+		}
+		fmt.Fprintf(w.out, "Please answer one of %s\n", strings.Join(options, ", "))
+	}
+}
+
+// RepositoryURL builds the clone URL of org/name on host for the ssh or https method.
+func RepositoryURL(host, org, name, method string) string {
+	name = strings.TrimSuffix(name, ".git")
+	if method == "https" {
+		return fmt.Sprintf("https://%s/%s/%s.git", host, org, name)
+	}
+	return fmt.Sprintf("git@%s:%s/%s.git", host, org, name)
+}
+
+// WriteConfigFile writes cfg to path as JSON when the extension is .json, or as YAML otherwise.
+func WriteConfigFile(path string, cfg *utils.ConfigFile) error {
+	var data []byte
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		out, err := json.MarshalIndent(cfg, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", path, err)
+		}
+		data = append(out, '\n')
+	} else {
+		out, err := marshalYAML(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", path, err)
+		}
+		data = out
+	}
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", dir, err)
+		}
+	}
+	// The file may hold a token, so keep it private like ~/.netrc.
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// marshalYAML encodes v with the two-space indentation used in the documentation.
+func marshalYAML(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DefaultInitPath returns the repositories file that config init writes by default: the existing
+// legacy file, or ~/.config.yaml.
+func DefaultInitPath() string {
+	if path := LegacyFilePath(); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".config.yaml"
+	}
+	return filepath.Join(home, ".config.yaml")
+}
@@ -0,0 +1,133 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fabianoflorentino/whiterose/utils"
+)
+
+func TestWizard_Run(t *testing.T) {
+	answers := strings.Join([]string{
+		"gitlab.example.com", // host
+		"team",               // organization
+		"ftp",                // invalid method, asked again
+		"https",              // method
+		"",                   // username: default git
+		"",                   // token: default reference
+		"~/src",              // import clones
+		"api",                // repository by name
+		"",                   // directory: default api
+		"other/web.git",      // repository by org/name
+		"frontend",           // directory
+		"git@github.com:org/tools.git",
+		"",              // directory: default tools
+		"",              // finish repositories
+		"go, terraform", // applications
+	}, "\n") + "\n"
+
+	discovered := []utils.RepoInfo{{URL: "https://gitlab.example.com/team/old.git", Directory: "src/old"}}
+	var out bytes.Buffer
+	cfg, err := NewWizard(strings.NewReader(answers), &out).
+		WithDiscovery(func(dir string) ([]utils.RepoInfo, error) {
+			if dir != "~/src" {
+				t.Errorf("discover(%q), want ~/src", dir)
+			}
+			return discovered, nil
+		}).
+		Run()
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	wantRepos := []utils.RepoInfo{
+		discovered[0],
+		{URL: "https://gitlab.example.com/team/api.git", Directory: "api"},
+		{URL: "https://gitlab.example.com/other/web.git", Directory: "frontend"},
+		{URL: "git@github.com:org/tools.git", Directory: "tools"},
+	}
+	if !reflect.DeepEqual(cfg.Repositories, wantRepos) {
+		t.Errorf("Repositories = %+v, want %+v", cfg.Repositories, wantRepos)
+	}
+
+	wantCred := utils.HostCredential{Username: "git", Token: "secret://gitlab-example-com-token"}
+	if cfg.Credentials["gitlab.example.com"] != wantCred {
+		t.Errorf("Credentials = %+v, want %+v", cfg.Credentials, wantCred)
+	}
+
+	if len(cfg.Applications) != 2 || cfg.Applications[0].Name != "Go" || cfg.Applications[0].VersionFlag != "version" ||
+		cfg.Applications[1].Command != "terraform" {
+		t.Errorf("Applications = %+v", cfg.Applications)
+	}
+	if !strings.Contains(out.String(), "Please answer one of ssh, https") {
+		t.Errorf("invalid method was not rejected:\n%s", out.String())
+	}
+}
+
+func TestWizard_DefaultsOnEOF(t *testing.T) {
+	cfg, err := NewWizard(strings.NewReader(""), &bytes.Buffer{}).Run()
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(cfg.Repositories) != 0 || cfg.Credentials != nil || len(cfg.Applications) != 3 {
+		t.Errorf("Run() = %+v, want the default applications only", cfg)
+	}
+}
+
+func TestWizard_Confirm(t *testing.T) {
+	tests := []struct {
+		input string
+		def   bool
+		want  bool
+	}{
+		{"\n", false, false},
+		{"\n", true, true},
+		{"yes\n", false, true},
+		{"maybe\nn\n", true, false},
+	}
+
+	for _, tt := range tests {
+		got, err := NewWizard(strings.NewReader(tt.input), &bytes.Buffer{}).Confirm("Overwrite?", tt.def)
+		if err != nil || got != tt.want {
+			t.Errorf("Confirm(%q, %v) = %v, %v, want %v", tt.input, tt.def, got, err, tt.want)
+		}
+	}
+}
+
+func TestWriteConfigFile(t *testing.T) {
+	goApp, _ := knownApp("go")
+	cfg := &utils.ConfigFile{
+		Repositories: []utils.RepoInfo{{URL: "git@github.com:org/api.git", Directory: "api"}},
+		Applications: []utils.AppInfo{goApp},
+	}
+
+	for _, name := range []string{"config.yaml", "nested/config.json"} {
+		path := filepath.Join(t.TempDir(), name)
+		if err := WriteConfigFile(path, cfg); err != nil {
+			t.Fatalf("WriteConfigFile(%s) error = %v", name, err)
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != 0o600 {
+			t.Errorf("%s mode = %v, want 0600", name, info.Mode().Perm())
+		}
+
+		repos, err := utils.FetchRepositories(path)
+		if err != nil {
+			t.Fatalf("FetchRepositories(%s) error = %v", name, err)
+		}
+		if !reflect.DeepEqual(repos, cfg.Repositories) {
+			t.Errorf("%s repositories = %+v", name, repos)
+		}
+		if issues, _ := ValidateFile(path); len(issues) != 0 {
+			t.Errorf("%s does not validate: %v", name, issues)
+		}
+	}
+}
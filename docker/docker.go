@@ -3,12 +3,15 @@ package docker
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/fabianoflorentino/whiterose/exitcode"
+	"github.com/fabianoflorentino/whiterose/output"
 	"github.com/fabianoflorentino/whiterose/utils"
 )
 
@@ -31,8 +34,7 @@ func (c *RealDockerClient) Delete(image string) error {
 	cmd := exec.Command("docker", "rmi", image)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-	_, err := cmd.CombinedOutput()
-	return err
+	return cmd.Run()
 }
 
 func (c *RealDockerClient) ListImages(pattern string) ([]string, error) {
@@ -108,8 +110,7 @@ func (dm *DockerManager) BuildDockerImage(dockerfilePath, imageName string, buil
 	err := dm.dockerClient.Build(dockerfilePath, imageName, args)
 	duration := time.Since(startTime)
 	if err != nil {
-		fmt.Printf("Error building Docker image: %v\n", err)
-		return err
+		return exitcode.Wrap(exitcode.External, fmt.Errorf("failed to build Docker image %s: %w", imageName, err))
 	}
 	fmt.Printf("Docker image '%s' built successfully in %v\n", imageName, duration)
 	return nil
@@ -117,23 +118,74 @@ func (dm *DockerManager) BuildDockerImage(dockerfilePath, imageName string, buil
 
 func (dm *DockerManager) DeleteDockerImage(imageName string) error {
 	fmt.Printf("Deleting Docker image '%s'\n", imageName)
-	return dm.dockerClient.Delete(imageName)
+	if err := dm.dockerClient.Delete(imageName); err != nil {
+		return exitcode.Wrap(exitcode.External, fmt.Errorf("failed to delete Docker image %s: %w", imageName, err))
+	}
+	return nil
+}
+
+// Image is a local Docker image reference split into its repository and tag.
+type Image struct {
+	Repository string `json:"repository"`
+	Tag        string `json:"tag"`
+}
+
+// ImageList holds the local images matching a reference pattern.
+type ImageList struct {
+	Pattern string  `json:"pattern"`
+	Images  []Image `json:"images"`
+}
+
+// Images returns the local images whose reference matches pattern.
+func (dm *DockerManager) Images(pattern string) (ImageList, error) {
+	refs, err := dm.dockerClient.ListImages(pattern)
+	if err != nil {
+		return ImageList{}, exitcode.Wrap(exitcode.External, fmt.Errorf("failed to list Docker images: %w", err))
+	}
+
+	list := ImageList{Pattern: pattern, Images: make([]Image, 0, len(refs))}
+	for _, ref := range refs {
+		list.Images = append(list.Images, parseImageRef(ref))
+	}
+	return list, nil
 }
 
+// ListDockerImages prints the local images whose reference matches pattern.
 func (dm *DockerManager) ListDockerImages(pattern string) error {
 	fmt.Printf("Listing Docker images matching '%s'\n", pattern)
-	images, err := dm.dockerClient.ListImages(pattern)
+	list, err := dm.Images(pattern)
 	if err != nil {
-		fmt.Printf("Error listing images: %v\n", err)
 		return err
 	}
-	if len(images) == 0 {
-		fmt.Println("No images found matching the pattern.")
-		return nil
+	list.Text(os.Stdout)
+	return nil
+}
+
+// Text writes the images one per line.
+func (l ImageList) Text(w io.Writer) {
+	if len(l.Images) == 0 {
+		fmt.Fprintln(w, "No images found matching the pattern.")
+		return
 	}
-	fmt.Println("Found images:")
-	for _, img := range images {
-		fmt.Printf("  %s\n", img)
+	fmt.Fprintln(w, "Found images:")
+	for _, img := range l.Images {
+		fmt.Fprintf(w, "  %s:%s\n", img.Repository, img.Tag)
 	}
-	return nil
-}
\ No newline at end of file
+}
+
+// Table lays the images out one per row.
+func (l ImageList) Table() output.Table {
+	t := output.Table{Header: []string{"REPOSITORY", "TAG"}}
+	for _, img := range l.Images {
+		t.Rows = append(t.Rows, []string{img.Repository, img.Tag})
+	}
+	return t
+}
+
+// parseImageRef splits "repository:tag"; the colon of a registry port is not mistaken for the tag.
+func parseImageRef(ref string) Image {
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		return Image{Repository: ref[:i], Tag: ref[i+1:]}
+	}
+	return Image{Repository: ref}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/fabianoflorentino/whiterose/mocks"
@@ -184,4 +185,28 @@ func TestDockerManager_ListDockerImages_Error(t *testing.T) {
 	if err == nil {
 		t.Error("expected error from ListDockerImages")
 	}
-}
\ No newline at end of file
+}
+func TestDockerManager_Images(t *testing.T) {
+	mock := &mocks.MockDockerClient{
+		ListFunc: func(pattern string) ([]string, error) {
+			return []string{"my_app:latest", "localhost:5000/team/api:v1.0", "dangling"}, nil
+		},
+	}
+
+	list, err := NewDockerManager("/tmp").WithClient(mock).Images("*")
+	if err != nil {
+		t.Fatalf("Images() error = %v", err)
+	}
+
+	want := []Image{
+		{Repository: "my_app", Tag: "latest"},
+		{Repository: "localhost:5000/team/api", Tag: "v1.0"},
+		{Repository: "dangling"},
+	}
+	if list.Pattern != "*" || !reflect.DeepEqual(list.Images, want) {
+		t.Errorf("Images() = %+v, want %+v", list, want)
+	}
+	if rows := list.Table().Rows; len(rows) != 3 || rows[1][0] != "localhost:5000/team/api" {
+		t.Errorf("Table().Rows = %v", rows)
+	}
+}
@@ -0,0 +1,410 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/fabianoflorentino/whiterose/config"
+	"github.com/fabianoflorentino/whiterose/git"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/joho/godotenv"
+	cryptossh "golang.org/x/crypto/ssh"
+)
+
+// Free space below which the disk space check warns or fails.
+const (
+	lowDiskSpace     = 5 << 30
+	minimumDiskSpace = 1 << 30
+)
+
+// checkConfigFiles reports the configuration files found in their default locations, the
+// ~/.config.{yml,yaml,json} file LoadDotConfig finds included.
+func (d *Doctor) checkConfigFiles() Result {
+	result := Result{Check: "config files"}
+
+	files, err := config.NewLoader().Files()
+	switch {
+	case err != nil:
+		result.Status, result.Detail = Fail, err.Error()
+		result.Hint = "point CONFIG_FILE to an existing file or unset it"
+	case len(files) == 0:
+		result.Status, result.Detail = Fail, "no configuration file found"
+		result.Hint = "run 'whiterose config init' to create ~/.config.yaml"
+	default:
+		result.Status, result.Detail = Pass, strings.Join(files, ", ")
+	}
+	return result
+}
+
+// checkConfig loads the configuration and returns its repositories.
+func (d *Doctor) checkConfig() ([]git.GitCloneOptions, Result) {
+	result := Result{Check: "config"}
+
+	repos, err := git.LoadConfiguredRepositories()
+	switch {
+	case err != nil:
+		result.Status, result.Detail = Fail, err.Error()
+		result.Hint = "run 'whiterose config validate' to find the problem"
+	case len(repos) == 0:
+		result.Status, result.Detail = Warn, "no repositories configured"
+		result.Hint = "add repositories to the configuration or run 'whiterose config init'"
+	default:
+		result.Status, result.Detail = Pass, fmt.Sprintf("%d repositories configured", len(repos))
+	}
+	return repos, result
+}
+
+// checkDotEnv reports whether ~/.env exists and parses.
+func (d *Doctor) checkDotEnv() Result {
+	result := Result{Check: ".env"}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		result.Status, result.Detail = Fail, err.Error()
+		result.Hint = "set HOME to your home directory"
+		return result
+	}
+
+	path := filepath.Join(home, ".env")
+	vars, err := godotenv.Read(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		result.Status, result.Detail = Warn, path+" not found"
+		result.Hint = "create it with GIT_USER, GIT_TOKEN and the SSH_KEY_* variables you need, see README#environment-variables"
+	case err != nil:
+		result.Status, result.Detail = Fail, fmt.Sprintf("cannot read %s: %v", path, err)
+		result.Hint = "fix the permissions or the KEY=value lines of the file"
+	default:
+		result.Status, result.Detail = Pass, fmt.Sprintf("%s (%d variables)", path, len(vars))
+	}
+	return result
+}
+
+// checkSSHKeys checks the key, or ssh-agent, of the repositories cloned over SSH.
+func (d *Doctor) checkSSHKeys(repos []git.GitCloneOptions) []Result {
+	keys := map[string][]git.GitCloneOptions{}
+	var order []string
+	var errs []Result
+	for _, r := range repos {
+		if !git.UsesSSH(r) {
+			continue
+		}
+		key, err := git.SSHKeyFile(r)
+		if err != nil {
+			errs = append(errs, Result{Check: "ssh key", Status: Fail, Detail: fmt.Sprintf("%s: %v", r.Name(), err),
+				Hint: "set SSH_KEY_PATH to the private key or its directory"})
+			continue
+		}
+		if _, ok := keys[key]; !ok {
+			order = append(order, key)
+		}
+		keys[key] = append(keys[key], r)
+	}
+
+	if len(order) == 0 && len(errs) == 0 {
+		return []Result{{Check: "ssh key", Status: Pass, Detail: "no repository is cloned over SSH"}}
+	}
+
+	results := errs
+	for _, key := range order {
+		result := checkSSHKey(key)
+		result.Detail += " (" + repositoriesOf(keys[key]) + ")"
+		results = append(results, result)
+	}
+	return results
+}
+
+// checkSSHKey checks that the private key file is readable, parses and, like OpenSSH requires,
+// is not accessible by other users. An empty file means ssh-agent.
+func checkSSHKey(file string) Result {
+	result := Result{Check: "ssh key"}
+
+	if file == "" {
+		sock := os.Getenv("SSH_AUTH_SOCK")
+		if _, err := os.Stat(sock); err != nil {
+			result.Status, result.Detail = Fail, fmt.Sprintf("ssh-agent socket %s: %v", sock, err)
+			result.Hint = "start ssh-agent and run ssh-add, or set SSH_KEY_PATH to use a key file"
+			return result
+		}
+		result.Status, result.Detail = Pass, "ssh-agent at "+sock
+		return result
+	}
+
+	info, err := os.Stat(file)
+	if err != nil {
+		result.Status, result.Detail = Fail, err.Error()
+		result.Hint = "create a key with ssh-keygen or set SSH_KEY_PATH and SSH_KEY_NAME to your key"
+		return result
+	}
+	if perm := info.Mode().Perm(); runtime.GOOS != "windows" && perm&0o077 != 0 {
+		result.Status, result.Detail = Fail, fmt.Sprintf("%s is accessible by other users (%04o)", file, perm)
+		result.Hint = "run 'chmod 600 " + file + "'"
+		return result
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		result.Status, result.Detail = Fail, err.Error()
+		result.Hint = "make the key readable by your user: 'chmod 600 " + file + "'"
+		return result
+	}
+
+	var missing *cryptossh.PassphraseMissingError
+	if _, err := cryptossh.ParsePrivateKey(data); err != nil && !errors.As(err, &missing) {
+		result.Status, result.Detail = Fail, fmt.Sprintf("%s: %v", file, err)
+		result.Hint = "point SSH_KEY_NAME to the private key, not the .pub file"
+		return result
+	}
+
+	result.Status, result.Detail = Pass, file
+	if missing != nil {
+		result.Detail += ", encrypted"
+	}
+	return result
+}
+
+// hostRepos are the repositories served from one address.
+type hostRepos struct {
+	address string
+	repos   []git.GitCloneOptions
+}
+
+// checkHosts dials the Git host of every repository once, at the port of its URL or of its
+// protocol, so that stand-ins such as ssh://git@localhost:2222 are dialed where they listen.
+func (d *Doctor) checkHosts(repos []git.GitCloneOptions) []Result {
+	var hosts []hostRepos
+	for _, r := range repos {
+		address, ok := hostAddress(r.URL)
+		if !ok {
+			continue
+		}
+		i := slices.IndexFunc(hosts, func(h hostRepos) bool { return h.address == address })
+		if i < 0 {
+			hosts = append(hosts, hostRepos{address: address})
+			i = len(hosts) - 1
+		}
+		hosts[i].repos = append(hosts[i].repos, r)
+	}
+
+	return parallel(hosts, func(h hostRepos) Result {
+		result := Result{Check: "git host " + h.address}
+
+		ctx, cancel := context.WithTimeout(context.Background(), d.timeout)
+		defer cancel()
+
+		conn, err := d.dialer.DialContext(ctx, "tcp", h.address)
+		if err != nil {
+			result.Status, result.Detail = Fail, err.Error()
+			var dnsErr *net.DNSError
+			if errors.As(err, &dnsErr) {
+				result.Hint = "check the host name in the URL of " + repositoriesOf(h.repos)
+			} else {
+				result.Hint = "check your network, VPN or proxy; the host serves " + repositoriesOf(h.repos)
+			}
+			return result
+		}
+		_ = conn.Close()
+
+		result.Status, result.Detail = Pass, fmt.Sprintf("reachable (%d repositories)", len(h.repos))
+		return result
+	})
+}
+
+// defaultPorts are the ports of the Git protocols whose URL has none.
+var defaultPorts = map[string]int{"ssh": 22, "https": 443, "http": 80, "git": 9418}
+
+// hostAddress returns the host:port of a repository URL, or false for local repositories.
+func hostAddress(rawURL string) (string, bool) {
+	ep, err := transport.NewEndpoint(rawURL)
+	if err != nil || ep.Host == "" {
+		return "", false
+	}
+	port := ep.Port
+	if port == 0 {
+		port = defaultPorts[ep.Protocol]
+	}
+	if port == 0 {
+		return "", false
+	}
+	return net.JoinHostPort(ep.Host, strconv.Itoa(port)), true
+}
+
+// checkDocker connects to the Docker daemon socket of DOCKER_HOST, /var/run/docker.sock by
+// default. A daemon that is not running is a warning, as only the docker command needs it; a
+// socket the user may not open is a failure.
+func (d *Doctor) checkDocker() Result {
+	result := Result{Check: "docker"}
+
+	network, address, err := dockerAddress(os.Getenv("DOCKER_HOST"))
+	if err != nil {
+		result.Status, result.Detail = Warn, err.Error()
+		return result
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.timeout)
+	defer cancel()
+
+	conn, err := d.dialer.DialContext(ctx, network, address)
+	switch {
+	case errors.Is(err, fs.ErrPermission):
+		result.Status, result.Detail = Fail, "permission denied on "+address
+		result.Hint = "add your user to the docker group with 'sudo usermod -aG docker $USER' and log in again"
+	case errors.Is(err, fs.ErrNotExist) || errors.Is(err, syscall.ECONNREFUSED):
+		result.Status, result.Detail = Warn, "Docker is not running at "+address
+		result.Hint = "start Docker with 'sudo systemctl start docker' or Docker Desktop, or set DOCKER_HOST"
+	case err != nil:
+		result.Status, result.Detail = Fail, err.Error()
+		result.Hint = "check DOCKER_HOST and that the Docker daemon is running"
+	default:
+		_ = conn.Close()
+		result.Status, result.Detail = Pass, address
+	}
+	return result
+}
+
+// dockerAddress returns the network and address of a DOCKER_HOST value.
+func dockerAddress(host string) (string, string, error) {
+	if host == "" {
+		if runtime.GOOS == "windows" {
+			return "", "", errors.New("the Docker named pipe is not checked on Windows")
+		}
+		return "unix", "/var/run/docker.sock", nil
+	}
+
+	u, err := url.Parse(host)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid DOCKER_HOST %q: %w", host, err)
+	}
+	switch u.Scheme {
+	case "unix":
+		return "unix", u.Path, nil
+	case "tcp":
+		return "tcp", u.Host, nil
+	default:
+		return "", "", fmt.Errorf("DOCKER_HOST %s is not checked", host)
+	}
+}
+
+// checkGitHubCLI runs 'gh auth status'. gh is optional, so problems are warnings.
+func (d *Doctor) checkGitHubCLI() Result {
+	result := Result{Check: "gh auth"}
+
+	if _, err := d.lookPath("gh"); err != nil {
+		result.Status, result.Detail = Warn, "gh is not installed"
+		result.Hint = "install it from https://cli.github.com or add gh to the apps of the configuration"
+		return result
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.timeout)
+	defer cancel()
+
+	out, err := d.run(ctx, "gh", "auth", "status")
+	switch {
+	case ctx.Err() != nil:
+		result.Status, result.Detail = Warn, fmt.Sprintf("gh auth status did not answer within %s", d.timeout)
+		result.Hint = "check your network or run 'gh auth status' to see where it hangs"
+	case err != nil:
+		result.Status, result.Detail = Warn, "not logged in: "+lastLine(string(out))
+		result.Hint = "run 'gh auth login'"
+	default:
+		result.Status, result.Detail = Pass, loggedIn(string(out))
+	}
+	return result
+}
+
+// loggedIn returns the "Logged in to ..." line of the output of gh auth status.
+func loggedIn(out string) string {
+	for _, line := range strings.Split(out, "\n") {
+		if i := strings.Index(line, "Logged in"); i >= 0 {
+			return strings.TrimSpace(line[i:])
+		}
+	}
+	return "logged in"
+}
+
+// lastLine returns the last non-empty line of output.
+func lastLine(out string) string {
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// checkDiskSpace checks the free space of the directories the repositories are cloned into, or of
+// the working directory without repositories.
+func (d *Doctor) checkDiskSpace(repos []git.GitCloneOptions) []Result {
+	var dirs []string
+	for _, r := range repos {
+		dir := cloneTarget(r.Directory)
+		if !slices.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	if len(dirs) == 0 {
+		dirs = []string{cloneTarget(".")}
+	}
+
+	results := make([]Result, 0, len(dirs))
+	for _, dir := range dirs {
+		results = append(results, d.checkFreeSpace(dir))
+	}
+	return results
+}
+
+// cloneTarget returns the closest existing directory containing dir.
+func cloneTarget(dir string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return dir
+	}
+	for target := filepath.Dir(abs); ; target = filepath.Dir(target) {
+		if info, err := os.Stat(target); (err == nil && info.IsDir()) || target == filepath.Dir(target) {
+			return target
+		}
+	}
+}
+
+// checkFreeSpace fails below minimumDiskSpace and warns below lowDiskSpace.
+func (d *Doctor) checkFreeSpace(dir string) Result {
+	result := Result{Check: "disk space"}
+
+	free, err := d.freeSpace(dir)
+	if err != nil {
+		result.Status, result.Detail = Warn, fmt.Sprintf("cannot read the free space of %s: %v", dir, err)
+		return result
+	}
+
+	result.Detail = fmt.Sprintf("%s free in %s", formatBytes(free), dir)
+	switch {
+	case free < minimumDiskSpace:
+		result.Status, result.Hint = Fail, "free up space or run setup from a directory on another disk"
+	case free < lowDiskSpace:
+		result.Status, result.Hint = Warn, "large repositories may not fit, free up space before cloning"
+	default:
+		result.Status = Pass
+	}
+	return result
+}
+
+// formatBytes formats n in the largest binary unit below it, such as "12.3 GiB".
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
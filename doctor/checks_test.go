@@ -0,0 +1,365 @@
+package doctor
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fabianoflorentino/whiterose/git"
+	cryptossh "golang.org/x/crypto/ssh"
+)
+
+// writeKey writes a new ed25519 private key to dir with the given permissions.
+func writeKey(t *testing.T, dir string, perm os.FileMode) string {
+	t.Helper()
+
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := cryptossh.MarshalPrivateKey(priv, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "id_ed25519")
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), perm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(path, perm); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// dialerFunc adapts a function to Dialer.
+type dialerFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+func (f dialerFunc) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	return f(ctx, network, address)
+}
+
+func TestCheckConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("WHITEROSE_CONFIG", filepath.Join(home, "missing.yaml"))
+
+	t.Setenv("CONFIG_FILE", filepath.Join(home, "nope.yaml"))
+	if r := New().checkConfigFiles(); r.Status != Fail || !strings.Contains(r.Detail, "CONFIG_FILE") {
+		t.Errorf("checkConfigFiles() with a missing CONFIG_FILE = %+v", r)
+	}
+
+	path := filepath.Join(home, ".config.yaml")
+	if err := os.WriteFile(path, []byte("repositories:\n  - url: git@github.com:org/api.git\n    directory: api\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CONFIG_FILE", "")
+
+	if r := New().checkConfigFiles(); r.Status != Pass || r.Detail != path {
+		t.Errorf("checkConfigFiles() = %+v", r)
+	}
+	repos, r := New().checkConfig()
+	if r.Status != Pass || len(repos) != 1 || repos[0].Name() != "api" {
+		t.Errorf("checkConfig() = %v, %+v", repos, r)
+	}
+}
+
+func TestCheckDotEnv(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want Status
+	}{
+		{name: "missing", want: Warn},
+		{name: "valid", data: "GIT_USER=me\nGIT_TOKEN=secret\n", want: Pass},
+		{name: "invalid", data: "GIT_USER='me\n", want: Fail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			if tt.data != "" {
+				if err := os.WriteFile(filepath.Join(home, ".env"), []byte(tt.data), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if r := New().checkDotEnv(); r.Status != tt.want {
+				t.Errorf("checkDotEnv() = %+v, want %s", r, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckSSHKeys(t *testing.T) {
+	t.Setenv("SSH_KEY_PATH", "")
+	t.Setenv("SSH_KEY_NAME", "id_ed25519")
+	dir := t.TempDir()
+	key := writeKey(t, dir, 0o600)
+	open := writeKey(t, t.TempDir(), 0o644)
+	notKey := filepath.Join(dir, "id_ed25519.pub")
+	if err := os.WriteFile(notKey, []byte("ssh-ed25519 AAAA"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		repos []git.GitCloneOptions
+		agent string
+		want  []Status
+		hint  string
+	}{
+		{
+			name:  "https only",
+			repos: []git.GitCloneOptions{{URL: "https://github.com/org/api.git"}},
+			want:  []Status{Pass},
+		},
+		{
+			name: "one result per key",
+			repos: []git.GitCloneOptions{
+				{URL: "git@github.com:org/api.git", SSHKeyPath: key},
+				{URL: "git@github.com:org/web.git", SSHKeyPath: dir},
+			},
+			want: []Status{Pass},
+		},
+		{
+			name:  "accessible by others",
+			repos: []git.GitCloneOptions{{URL: "git@github.com:org/api.git", SSHKeyPath: open}},
+			want:  []Status{Fail},
+			hint:  "chmod 600",
+		},
+		{
+			name:  "public key",
+			repos: []git.GitCloneOptions{{URL: "git@github.com:org/api.git", SSHKeyPath: notKey}},
+			want:  []Status{Fail},
+			hint:  "not the .pub file",
+		},
+		{
+			name:  "missing key",
+			repos: []git.GitCloneOptions{{URL: "git@github.com:org/api.git", SSHKeyPath: filepath.Join(dir, "missing")}},
+			want:  []Status{Fail},
+			hint:  "ssh-keygen",
+		},
+		{
+			name:  "ssh-agent",
+			repos: []git.GitCloneOptions{{URL: "ssh://git@localhost:2222/org/api.git"}},
+			agent: dir,
+			want:  []Status{Pass},
+		},
+		{
+			name:  "ssh-agent not running",
+			repos: []git.GitCloneOptions{{URL: "ssh://git@localhost:2222/org/api.git"}},
+			agent: filepath.Join(dir, "agent.sock"),
+			want:  []Status{Fail},
+			hint:  "start ssh-agent",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SSH_AUTH_SOCK", tt.agent)
+
+			results := New().checkSSHKeys(tt.repos)
+			if len(results) != len(tt.want) {
+				t.Fatalf("checkSSHKeys() = %+v, want %d results", results, len(tt.want))
+			}
+			for i, r := range results {
+				if r.Status != tt.want[i] || !strings.Contains(r.Hint, tt.hint) {
+					t.Errorf("checkSSHKeys()[%d] = %+v, want %s with hint %q", i, r, tt.want[i], tt.hint)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckHosts(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	stand := listener.Addr().String()
+
+	repos := []git.GitCloneOptions{
+		{URL: "ssh://git@" + stand + "/org/api.git"},
+		{URL: "ssh://git@" + stand + "/org/web.git"},
+		{URL: "https://github.com/org/cli.git"},
+		{URL: "/srv/git/local.git"},
+	}
+
+	// The stand-in answers on its own port; every other host does not resolve.
+	d := New().WithDialer(dialerFunc(func(ctx context.Context, network, address string) (net.Conn, error) {
+		if address == stand {
+			return (&net.Dialer{}).DialContext(ctx, network, address)
+		}
+		return nil, &net.OpError{Op: "dial", Net: network, Err: &net.DNSError{Err: "no such host", Name: address, IsNotFound: true}}
+	}))
+
+	results := d.checkHosts(repos)
+	if len(results) != 2 {
+		t.Fatalf("checkHosts() = %+v, want 2 hosts", results)
+	}
+	if r := results[0]; r.Check != "git host "+stand || r.Status != Pass || r.Detail != "reachable (2 repositories)" {
+		t.Errorf("checkHosts()[0] = %+v", r)
+	}
+	if r := results[1]; r.Check != "git host github.com:443" || r.Status != Fail || !strings.Contains(r.Hint, "host name") {
+		t.Errorf("checkHosts()[1] = %+v", r)
+	}
+}
+
+func TestHostAddress(t *testing.T) {
+	tests := []struct {
+		url    string
+		want   string
+		remote bool
+	}{
+		{url: "git@github.com:org/api.git", want: "github.com:22", remote: true},
+		{url: "https://github.com/org/api.git", want: "github.com:443", remote: true},
+		{url: "ssh://git@localhost:2222/org/api.git", want: "localhost:2222", remote: true},
+		{url: "http://127.0.0.1:3000/org/api.git", want: "127.0.0.1:3000", remote: true},
+		{url: "file:///srv/git/api.git"},
+		{url: "/srv/git/api.git"},
+	}
+
+	for _, tt := range tests {
+		got, ok := hostAddress(tt.url)
+		if got != tt.want || ok != tt.remote {
+			t.Errorf("hostAddress(%q) = %q, %v, want %q, %v", tt.url, got, ok, tt.want, tt.remote)
+		}
+	}
+}
+
+func TestCheckDocker(t *testing.T) {
+	dir := t.TempDir()
+	sock := filepath.Join(dir, "docker.sock")
+	listener, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Skipf("unix sockets not available: %v", err)
+	}
+	defer listener.Close()
+
+	tests := []struct {
+		name string
+		host string
+		want Status
+		hint string
+	}{
+		{name: "socket", host: "unix://" + sock, want: Pass},
+		{name: "not running", host: "unix://" + filepath.Join(dir, "missing.sock"), want: Warn, hint: "start Docker"},
+		{name: "not checked", host: "ssh://user@host", want: Warn},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DOCKER_HOST", tt.host)
+
+			if r := New().checkDocker(); r.Status != tt.want || !strings.Contains(r.Hint, tt.hint) {
+				t.Errorf("checkDocker() = %+v, want %s with hint %q", r, tt.want, tt.hint)
+			}
+		})
+	}
+
+	t.Run("permission denied", func(t *testing.T) {
+		t.Setenv("DOCKER_HOST", "tcp://docker:2375")
+		d := New().WithDialer(dialerFunc(func(ctx context.Context, network, address string) (net.Conn, error) {
+			return nil, &net.OpError{Op: "dial", Net: network, Err: os.ErrPermission}
+		}))
+		if r := d.checkDocker(); r.Status != Fail || !strings.Contains(r.Hint, "docker group") {
+			t.Errorf("checkDocker() = %+v", r)
+		}
+	})
+}
+
+func TestCheckGitHubCLI(t *testing.T) {
+	tests := []struct {
+		name      string
+		installed bool
+		out       string
+		err       error
+		want      Status
+		detail    string
+	}{
+		{name: "not installed", want: Warn, detail: "gh is not installed"},
+		{
+			name:      "logged in",
+			installed: true,
+			out:       "github.com\n  ✓ Logged in to github.com account octocat (keyring)\n  - Active account: true\n",
+			want:      Pass,
+			detail:    "Logged in to github.com account octocat (keyring)",
+		},
+		{
+			name:      "logged out",
+			installed: true,
+			out:       "You are not logged into any GitHub hosts. To log in, run: gh auth login\n",
+			err:       errors.New("exit status 1"),
+			want:      Warn,
+			detail:    "not logged in: You are not logged into any GitHub hosts. To log in, run: gh auth login",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := New()
+			d.lookPath = func(string) (string, error) {
+				if !tt.installed {
+					return "", errors.New("not found")
+				}
+				return "/usr/bin/gh", nil
+			}
+			d.run = func(context.Context, string, ...string) ([]byte, error) {
+				return []byte(tt.out), tt.err
+			}
+
+			if r := d.checkGitHubCLI(); r.Status != tt.want || r.Detail != tt.detail {
+				t.Errorf("checkGitHubCLI() = %+v, want %s %q", r, tt.want, tt.detail)
+			}
+		})
+	}
+}
+
+func TestCheckDiskSpace(t *testing.T) {
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, "api"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	repos := []git.GitCloneOptions{
+		{Directory: filepath.Join(root, "api")},
+		{Directory: filepath.Join(root, "new", "web")},
+	}
+
+	tests := []struct {
+		free uint64
+		err  error
+		want Status
+	}{
+		{free: 20 << 30, want: Pass},
+		{free: 2 << 30, want: Warn},
+		{free: 100 << 20, want: Fail},
+		{err: errors.New("not supported"), want: Warn},
+	}
+
+	for _, tt := range tests {
+		d := New()
+		var checked []string
+		d.freeSpace = func(dir string) (uint64, error) {
+			checked = append(checked, dir)
+			return tt.free, tt.err
+		}
+
+		results := d.checkDiskSpace(repos)
+		if len(results) != 1 || results[0].Status != tt.want {
+			t.Errorf("checkDiskSpace() with %d free = %+v, want %s", tt.free, results, tt.want)
+		}
+		if len(checked) != 1 || checked[0] != root {
+			t.Errorf("checkDiskSpace() checked %v, want %s", checked, root)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+// Package doctor checks everything whiterose depends on before a run does: the configuration
+// files and whether they load, the .env file, the SSH keys of the repositories cloned over SSH,
+// the reachability of their Git hosts, access to the Docker socket, the authentication of the
+// GitHub CLI and the free disk space where the repositories are cloned.
+//
+// Every check reports pass, warn or fail with a hint to fix what it found, instead of the error
+// the same problem causes in the middle of setup. Network checks go through a Dialer, so that a
+// local stand-in such as a Git server on localhost:2222 or a fake Docker socket can answer them.
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fabianoflorentino/whiterose/exitcode"
+	"github.com/fabianoflorentino/whiterose/git"
+	"github.com/fabianoflorentino/whiterose/output"
+)
+
+// Status is the outcome of a check.
+type Status string
+
+const (
+	// Pass means the check found nothing to fix.
+	Pass Status = "pass"
+	// Warn means whiterose works, but something may fail later or only some commands need it.
+	Warn Status = "warn"
+	// Fail means a command depending on the check will fail.
+	Fail Status = "fail"
+)
+
+// Result is the outcome of one check.
+type Result struct {
+	Check  string `json:"check"`
+	Status Status `json:"status"`
+	Detail string `json:"detail"`
+	// Hint tells how to fix a warning or failure.
+	Hint string `json:"hint,omitempty"`
+}
+
+// Report lists the results in the order the checks ran.
+type Report []Result
+
+// Dialer opens the network connections of the checks; *net.Dialer implements it.
+type Dialer interface {
+	DialContext(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+// DefaultTimeout bounds every connection and command of the checks.
+const DefaultTimeout = 5 * time.Second
+
+// Doctor runs the checks.
+type Doctor struct {
+	dialer  Dialer
+	timeout time.Duration
+
+	// lookPath, run and freeSpace are replaced in tests.
+	lookPath  func(file string) (string, error)
+	run       func(ctx context.Context, name string, args ...string) ([]byte, error)
+	freeSpace func(dir string) (uint64, error)
+}
+
+// New creates a Doctor dialing with a net.Dialer and waiting DefaultTimeout.
+func New() *Doctor {
+	return &Doctor{
+		dialer:    &net.Dialer{},
+		timeout:   DefaultTimeout,
+		lookPath:  exec.LookPath,
+		run:       runCommand,
+		freeSpace: freeSpace,
+	}
+}
+
+// WithDialer replaces the dialer of the Git host and Docker socket checks.
+func (d *Doctor) WithDialer(dialer Dialer) *Doctor {
+	d.dialer = dialer
+	return d
+}
+
+// WithTimeout sets how long a connection or command may take; DefaultTimeout when not positive.
+func (d *Doctor) WithTimeout(timeout time.Duration) *Doctor {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	d.timeout = timeout
+	return d
+}
+
+// Run checks the environment. The repository checks use the loaded configuration; when it does
+// not load they only check the working directory.
+func (d *Doctor) Run() Report {
+	report := Report{d.checkConfigFiles()}
+
+	repos, result := d.checkConfig()
+	report = append(report, result, d.checkDotEnv())
+	report = append(report, d.checkSSHKeys(repos)...)
+	report = append(report, d.checkHosts(repos)...)
+	report = append(report, d.checkDocker(), d.checkGitHubCLI())
+	report = append(report, d.checkDiskSpace(repos)...)
+
+	return report
+}
+
+// parallel runs check for every item concurrently and returns the results in item order.
+func parallel[T any](items []T, check func(T) Result) []Result {
+	results := make([]Result, len(items))
+	var wg sync.WaitGroup
+	for i, item := range items {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = check(item)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// runCommand runs a command and returns its stdout and stderr.
+func runCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.WaitDelay = time.Second
+	return cmd.CombinedOutput()
+}
+
+// repositoriesOf returns the names of repos, for details.
+func repositoriesOf(repos []git.GitCloneOptions) string {
+	names := make([]string, 0, len(repos))
+	for _, r := range repos {
+		names = append(names, r.Name())
+	}
+	return strings.Join(names, ", ")
+}
+
+// Counts returns how many checks passed, warned and failed.
+func (r Report) Counts() (passed, warnings, failed int) {
+	for _, res := range r {
+		switch res.Status {
+		case Pass:
+			passed++
+		case Warn:
+			warnings++
+		case Fail:
+			failed++
+		}
+	}
+	return passed, warnings, failed
+}
+
+// Err returns an error with the exitcode.Prerequisite status naming the failed checks, or nil
+// when none failed. Warnings do not fail.
+func (r Report) Err() error {
+	var failed []string
+	for _, res := range r {
+		if res.Status == Fail {
+			failed = append(failed, res.Check)
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return exitcode.Errorf(exitcode.Prerequisite, "%d of %d checks failed: %s", len(failed), len(r), strings.Join(failed, ", "))
+}
+
+// icons are the symbols of the statuses in text output.
+var icons = map[Status]string{Pass: "✅", Warn: "⚠️ ", Fail: "❌"}
+
+// Text writes one line per check, followed by its hint.
+func (r Report) Text(w io.Writer) {
+	fmt.Fprintf(w, "🩺 Environment diagnostics:\n")
+	fmt.Fprintf(w, "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	for _, res := range r {
+		fmt.Fprintf(w, "%s %-22s %s\n", icons[res.Status], res.Check, res.Detail)
+		if res.Hint != "" && res.Status != Pass {
+			fmt.Fprintf(w, "   💡 %s\n", res.Hint)
+		}
+	}
+
+	passed, warnings, failed := r.Counts()
+	fmt.Fprintf(w, "\n%d passed, %d warnings, %d failed\n", passed, warnings, failed)
+}
+
+// Table lays the results out one check per row.
+func (r Report) Table() output.Table {
+	t := output.Table{Header: []string{"CHECK", "STATUS", "DETAIL", "HINT"}}
+	for _, res := range r {
+		hint := res.Hint
+		if res.Status == Pass {
+			hint = ""
+		}
+		t.Rows = append(t.Rows, []string{res.Check, string(res.Status), res.Detail, hint})
+	}
+	return t
+}
@@ -0,0 +1,59 @@
+package doctor
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/fabianoflorentino/whiterose/exitcode"
+)
+
+func TestReport(t *testing.T) {
+	report := Report{
+		{Check: "config", Status: Pass, Detail: "2 repositories configured", Hint: "not shown"},
+		{Check: "docker", Status: Warn, Detail: "Docker is not running", Hint: "start Docker"},
+		{Check: "ssh key", Status: Fail, Detail: "id_rsa is accessible by other users", Hint: "run 'chmod 600 id_rsa'"},
+	}
+
+	if p, w, f := report.Counts(); p != 1 || w != 1 || f != 1 {
+		t.Errorf("Counts() = %d, %d, %d", p, w, f)
+	}
+
+	err := report.Err()
+	if exitcode.Of(err) != exitcode.Prerequisite || err.Error() != "1 of 3 checks failed: ssh key" {
+		t.Errorf("Err() = %v", err)
+	}
+	if err := report[:2].Err(); err != nil {
+		t.Errorf("Err() with warnings = %v", err)
+	}
+
+	var buf bytes.Buffer
+	report.Text(&buf)
+	text := buf.String()
+	for _, want := range []string{"❌ ssh key", "💡 run 'chmod 600 id_rsa'", "💡 start Docker", "1 passed, 1 warnings, 1 failed"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Text() does not contain %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "not shown") {
+		t.Errorf("Text() shows the hint of a passed check:\n%s", text)
+	}
+
+	if rows := report.Table().Rows; len(rows) != 3 || rows[0][3] != "" || rows[2][1] != "fail" {
+		t.Errorf("Table().Rows = %v", rows)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[uint64]string{
+		512:           "512 B",
+		1536:          "1.5 KiB",
+		5 << 30:       "5.0 GiB",
+		(3 << 40) / 2: "1.5 TiB",
+	}
+	for n, want := range tests {
+		if got := formatBytes(n); got != want {
+			t.Errorf("formatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
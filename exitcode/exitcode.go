@@ -0,0 +1,76 @@
+// Package exitcode defines the exit status of whiterose and the error type that carries it,
+// so that CI pipelines can tell a broken configuration from a missing prerequisite or from a
+// run where only some repositories failed.
+//
+// Library packages return errors created with Wrap or Errorf; the command layer returns them
+// from cobra's RunE and main exits with Of(err).
+package exitcode
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Code is a process exit status.
+type Code int
+
+const (
+	// OK means the command succeeded.
+	OK Code = 0
+	// Failure is an unexpected error that fits no other code.
+	Failure Code = 1
+	// Usage means the flags or arguments are invalid.
+	Usage Code = 2
+	// Config means the configuration could not be loaded or is invalid.
+	Config Code = 3
+	// Prerequisite means a required application is not installed.
+	Prerequisite Code = 4
+	// Partial means one or more repositories or projects failed; the others were still processed.
+	Partial Code = 5
+	// External means an external tool or service, such as docker, go, gh or a registry, failed.
+	External Code = 6
+)
+
+// Error is an error with the exit status the process should end with.
+type Error struct {
+	Code Code
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Wrap returns err with the given exit status, or nil when err is nil. An error that already
+// carries a status keeps it.
+func Wrap(code Code, err error) error {
+	if err == nil {
+		return nil
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return err
+	}
+	return &Error{Code: code, Err: err}
+}
+
+// Errorf formats an error with the given exit status; %w wraps like fmt.Errorf.
+func Errorf(code Code, format string, args ...any) error {
+	return &Error{Code: code, Err: fmt.Errorf(format, args...)}
+}
+
+// Of returns the exit status for err: OK when it is nil, the status it carries, or Failure.
+func Of(err error) Code {
+	if err == nil {
+		return OK
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return Failure
+}
@@ -0,0 +1,48 @@
+package exitcode
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestOf(t *testing.T) {
+	base := errors.New("boom")
+
+	tests := []struct {
+		name string
+		err  error
+		want Code
+	}{
+		{"nil", nil, OK},
+		{"plain error", base, Failure},
+		{"wrapped", Wrap(Config, base), Config},
+		{"wrapped by fmt", fmt.Errorf("loading: %w", Wrap(Prerequisite, base)), Prerequisite},
+		{"errorf", Errorf(Partial, "%d of %d repositories failed", 1, 3), Partial},
+		{"first status wins", Wrap(Failure, Wrap(External, base)), External},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Of(tt.err); got != tt.want {
+				t.Errorf("Of() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrap(t *testing.T) {
+	if Wrap(Config, nil) != nil {
+		t.Error("Wrap(nil) should be nil")
+	}
+
+	base := errors.New("boom")
+	err := Wrap(Config, base)
+	if err.Error() != "boom" || !errors.Is(err, base) {
+		t.Errorf("Wrap() = %v, want the message and chain of the wrapped error", err)
+	}
+
+	if err := Errorf(External, "docker failed: %w", base); !errors.Is(err, base) || err.Error() != "docker failed: boom" {
+		t.Errorf("Errorf() = %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+const (
+	// DefaultBranch is checked out after cloning when a repository does not configure a branch.
+	DefaultBranch = "development"
+	// DefaultCreateBranchTemplate names the branch created when neither the branch nor its fallback exist.
+	DefaultCreateBranchTemplate = "development/{{.User}}"
+)
+
+// branchTemplateData holds the values available to createBranchTemplate.
+type branchTemplateData struct {
+	User string
+}
+
+// renderBranchTemplate expands a createBranchTemplate such as "feature/{{.User}}".
+// An empty template uses DefaultCreateBranchTemplate.
+func renderBranchTemplate(tmpl string) (string, error) {
+	if tmpl == "" {
+		tmpl = DefaultCreateBranchTemplate
+	}
+
+	t, err := template.New("branch").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid createBranchTemplate %q: %w", tmpl, err)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, branchTemplateData{User: os.Getenv("USER")}); err != nil {
+		return "", fmt.Errorf("invalid createBranchTemplate %q: %w", tmpl, err)
+	}
+
+	name := strings.TrimSpace(buf.String())
+	if name == "" {
+		return "", fmt.Errorf("createBranchTemplate %q renders an empty branch name", tmpl)
+	}
+
+	return name, nil
+}
+
+// checkoutBranch checks out the local branch name, creating it from origin/<name> and
+// tracking it when only the remote branch exists.
+func checkoutBranch(repo *git.Repository, worktree *git.Worktree, name string) error {
+	local := plumbing.NewBranchReferenceName(name)
+	if _, err := repo.Reference(local, false); err == nil {
+		return worktree.Checkout(&git.CheckoutOptions{Branch: local})
+	}
+
+	remote, err := repo.Reference(plumbing.NewRemoteReferenceName(git.DefaultRemoteName, name), true)
+	if err != nil {
+		return fmt.Errorf("branch %s not found: %w", name, err)
+	}
+
+	if err := worktree.Checkout(&git.CheckoutOptions{Branch: local, Hash: remote.Hash(), Create: true}); err != nil {
+		return err
+	}
+
+	return repo.CreateBranch(&config.Branch{
+		Name:   name,
+		Remote: git.DefaultRemoteName,
+		Merge:  local,
+	})
+}
+
+// credentialEnvKeys returns the environment variables holding the user and token for a credential reference.
+func credentialEnvKeys(ref string) (string, string) {
+	key := strings.ToUpper(strings.NewReplacer("-", "_", ".", "_", " ", "_").Replace(ref))
+	return key + "_USER", key + "_TOKEN"
+}
@@ -0,0 +1,139 @@
+package git
+
+import (
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+func TestRenderBranchTemplate(t *testing.T) {
+	t.Setenv("USER", "elliot")
+
+	tests := []struct {
+		name    string
+		tmpl    string
+		want    string
+		wantErr bool
+	}{
+		{"default", "", "development/elliot", false},
+		{"custom", "feature/{{.User}}-setup", "feature/elliot-setup", false},
+		{"static", "sandbox", "sandbox", false},
+		{"unknown field", "{{.Team}}", "", true},
+		{"invalid syntax", "{{.User", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderBranchTemplate(tt.tmpl)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("renderBranchTemplate(%q) error = %v, wantErr %v", tt.tmpl, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("renderBranchTemplate(%q) = %q, want %q", tt.tmpl, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCredentialEnvKeys(t *testing.T) {
+	user, token := credentialEnvKeys("self-hosted.gitlab")
+	if user != "SELF_HOSTED_GITLAB_USER" || token != "SELF_HOSTED_GITLAB_TOKEN" {
+		t.Errorf("credentialEnvKeys() = %s, %s", user, token)
+	}
+}
+
+func TestClone_ChecksOutConfiguredBranch(t *testing.T) {
+	origin, originDir, _ := newOriginAndClone(t)
+
+	head, err := origin.Head()
+	if err != nil {
+		t.Fatalf("failed to read origin HEAD: %v", err)
+	}
+	ref := plumbing.NewHashReference(plumbing.NewBranchReferenceName("release"), head.Hash())
+	if err := origin.Storer.SetReference(ref); err != nil {
+		t.Fatalf("failed to create release branch: %v", err)
+	}
+
+	dir := filepath.Join(t.TempDir(), "repo")
+	if err := clone(GitCloneOptions{URL: originDir, Directory: dir, Branch: "missing", FallbackBranch: "release"}, io.Discard); err != nil {
+		t.Fatalf("clone() error = %v", err)
+	}
+
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatalf("failed to open clone: %v", err)
+	}
+	got, err := repo.Head()
+	if err != nil {
+		t.Fatalf("failed to read HEAD: %v", err)
+	}
+	if got.Name().Short() != "release" {
+		t.Errorf("HEAD = %s, want release", got.Name().Short())
+	}
+
+	upstream, err := upstreamRef(repo, got.Name())
+	if err != nil || upstream != plumbing.NewRemoteReferenceName("origin", "release") {
+		t.Errorf("upstreamRef() = %v, %v, want origin/release", upstream, err)
+	}
+}
+
+func TestClone_CreatesBranchFromTemplate(t *testing.T) {
+	_, originDir, _ := newOriginAndClone(t)
+
+	dir := filepath.Join(t.TempDir(), "repo")
+	opts := GitCloneOptions{URL: originDir, Directory: dir, Branch: "missing", CreateBranchTemplate: "sandbox"}
+	if err := clone(opts, io.Discard); err != nil {
+		t.Fatalf("clone() error = %v", err)
+	}
+
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatalf("failed to open clone: %v", err)
+	}
+	got, err := repo.Head()
+	if err != nil {
+		t.Fatalf("failed to read HEAD: %v", err)
+	}
+	if got.Name().Short() != "sandbox" {
+		t.Errorf("HEAD = %s, want sandbox", got.Name().Short())
+	}
+}
+
+func TestClone_SingleBranchFallsBack(t *testing.T) {
+	_, originDir, _ := newOriginAndClone(t)
+
+	bareDir := filepath.Join(t.TempDir(), "bare.git")
+	bare, err := git.PlainClone(bareDir, true, &git.CloneOptions{URL: originDir})
+	if err != nil {
+		t.Fatalf("failed to create bare repository: %v", err)
+	}
+	head, err := bare.Head()
+	if err != nil {
+		t.Fatalf("failed to read bare HEAD: %v", err)
+	}
+	ref := plumbing.NewHashReference(plumbing.NewBranchReferenceName("release"), head.Hash())
+	if err := bare.Storer.SetReference(ref); err != nil {
+		t.Fatalf("failed to create release branch: %v", err)
+	}
+
+	dir := filepath.Join(t.TempDir(), "repo")
+	opts := GitCloneOptions{URL: bareDir, Directory: dir, Branch: "missing", FallbackBranch: "release", SingleBranch: true}
+	if err := clone(opts, io.Discard); err != nil {
+		t.Fatalf("clone() error = %v", err)
+	}
+
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatalf("failed to open clone: %v", err)
+	}
+	got, err := repo.Head()
+	if err != nil {
+		t.Fatalf("failed to read HEAD: %v", err)
+	}
+	if got.Name().Short() != "release" {
+		t.Errorf("HEAD = %s, want release", got.Name().Short())
+	}
+}
@@ -0,0 +1,205 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/fabianoflorentino/whiterose/utils"
+)
+
+// gitCredentialFill runs "git credential fill" with input on stdin and returns its output.
+// Terminal prompts are disabled so that a missing credential fails instead of blocking a worker.
+// It is a variable so tests can replace the git binary.
+var gitCredentialFill = func(input string) (string, error) {
+	cmd := exec.Command("git", "credential", "fill")
+	cmd.Stdin = strings.NewReader(input)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_ASKPASS=", "SSH_ASKPASS=")
+	out, err := cmd.Output()
+	return string(out), err
+}
+
+// hostCredential is a username and token resolved for an HTTPS host.
+type hostCredential struct {
+	Username string
+	Password string
+}
+
+// credentialResolver looks up HTTPS credentials per host, trying in order git's credential helpers,
+// ~/.netrc and the "credentials" map of the configuration file. Lookups are cached per host.
+type credentialResolver struct {
+	configured map[string]utils.HostCredential
+	netrcPath  string
+	cache      map[string]*hostCredential
+}
+
+// newCredentialResolver creates a resolver using the per-host credentials of the configuration file.
+// The .netrc file is read from $NETRC, falling back to ~/.netrc.
+func newCredentialResolver(configured map[string]utils.HostCredential) *credentialResolver {
+	netrcPath := os.Getenv("NETRC")
+	if netrcPath == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			netrcPath = filepath.Join(home, ".netrc")
+		}
+	}
+
+	return &credentialResolver{
+		configured: configured,
+		netrcPath:  netrcPath,
+		cache:      make(map[string]*hostCredential),
+	}
+}
+
+// lookup returns the credentials for the host of rawURL, or nil when none of the sources has them.
+func (r *credentialResolver) lookup(rawURL string) *hostCredential {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return nil
+	}
+
+	if cred, ok := r.cache[u.Host]; ok {
+		return cred
+	}
+
+	cred := r.fromCredentialHelper(u)
+	if cred == nil {
+		cred = r.fromNetrc(u.Hostname())
+	}
+	if cred == nil {
+		cred = r.fromConfig(u)
+	}
+	r.cache[u.Host] = cred
+
+	return cred
+}
+
+// fromCredentialHelper asks git for the credentials of u, using whatever helper is configured.
+func (r *credentialResolver) fromCredentialHelper(u *url.URL) *hostCredential {
+	input := fmt.Sprintf("protocol=%s\nhost=%s\n", u.Scheme, u.Host)
+	if u.User != nil && u.User.Username() != "" {
+		input += fmt.Sprintf("username=%s\n", u.User.Username())
+	}
+
+	out, err := gitCredentialFill(input + "\n")
+	if err != nil {
+		return nil
+	}
+
+	cred := &hostCredential{}
+	for _, line := range strings.Split(out, "\n") {
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "username":
+			cred.Username = value
+		case "password":
+			cred.Password = value
+		}
+	}
+	if cred.Password == "" {
+		return nil
+	}
+
+	return cred
+}
+
+// fromNetrc returns the login and password of the .netrc entry for host, or of its default entry.
+func (r *credentialResolver) fromNetrc(host string) *hostCredential {
+	if r.netrcPath == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(r.netrcPath)
+	if err != nil {
+		return nil
+	}
+
+	return parseNetrc(data, host)
+}
+
+// fromConfig returns the credentials configured for the host, with or without its port.
+func (r *credentialResolver) fromConfig(u *url.URL) *hostCredential {
+	for _, key := range []string{u.Host, u.Hostname()} {
+		if c, ok := r.configured[key]; ok && c.Token != "" {
+			username := c.Username
+			if username == "" {
+				username = "git"
+			}
+			return &hostCredential{Username: username, Password: c.Token}
+		}
+	}
+
+	return nil
+}
+
+// parseNetrc returns the credentials of the "machine" entry matching host, falling back to the
+// "default" entry. Macro definitions are skipped.
+func parseNetrc(data []byte, host string) *hostCredential {
+	var (
+		fallback *hostCredential
+		current  *hostCredential
+		matched  bool
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	inMacro := false
+	for scanner.Scan() {
+		line := scanner.Text()
+		if inMacro {
+			// A macro definition ends at the first empty line.
+			inMacro = strings.TrimSpace(line) != ""
+			continue
+		}
+
+		fields := strings.Fields(line)
+		for i := 0; i < len(fields); i++ {
+			if strings.HasPrefix(fields[i], "#") {
+				break
+			}
+
+			switch fields[i] {
+			case "machine", "default":
+				if matched && current.Password != "" {
+					return current
+				}
+				current = &hostCredential{}
+				matched = false
+				if fields[i] == "default" {
+					fallback = current
+				} else if i+1 < len(fields) {
+					i++
+					matched = fields[i] == host
+				}
+			case "login", "password":
+				if current == nil || i+1 >= len(fields) {
+					continue
+				}
+				i++
+				if fields[i-1] == "login" {
+					current.Username = fields[i]
+				} else {
+					current.Password = fields[i]
+				}
+			case "macdef":
+				inMacro = true
+				i = len(fields)
+			}
+		}
+	}
+
+	if matched && current.Password != "" {
+		return current
+	}
+	if fallback != nil && fallback.Password != "" {
+		return fallback
+	}
+
+	return nil
+}
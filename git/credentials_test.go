@@ -0,0 +1,133 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fabianoflorentino/whiterose/utils"
+)
+
+// stubCredentialFill replaces git credential fill with fn for the duration of the test.
+func stubCredentialFill(t *testing.T, fn func(input string) (string, error)) {
+	t.Helper()
+	original := gitCredentialFill
+	gitCredentialFill = fn
+	t.Cleanup(func() { gitCredentialFill = original })
+}
+
+func TestParseNetrc(t *testing.T) {
+	netrc := `# work
+machine gitlab.example.com login deploy password glpat-1
+machine github.com
+  login octocat
+  password ghp-1
+macdef init
+  machine github.com login macro password ignored
+
+default login anonymous password guest
+`
+
+	tests := []struct {
+		host string
+		want *hostCredential
+	}{
+		{host: "github.com", want: &hostCredential{Username: "octocat", Password: "ghp-1"}},
+		{host: "gitlab.example.com", want: &hostCredential{Username: "deploy", Password: "glpat-1"}},
+		{host: "bitbucket.org", want: &hostCredential{Username: "anonymous", Password: "guest"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			got := parseNetrc([]byte(netrc), tt.host)
+			if got == nil || *got != *tt.want {
+				t.Errorf("parseNetrc(%s) = %+v, want %+v", tt.host, got, tt.want)
+			}
+		})
+	}
+
+	if got := parseNetrc([]byte("machine github.com login octocat password ghp-1\n"), "gitlab.com"); got != nil {
+		t.Errorf("parseNetrc(unknown host) = %+v, want nil", got)
+	}
+}
+
+func TestCredentialResolver_Order(t *testing.T) {
+	netrc := filepath.Join(t.TempDir(), ".netrc")
+	if err := os.WriteFile(netrc, []byte("machine gitlab.example.com login deploy password from-netrc\n"), 0o600); err != nil {
+		t.Fatalf("failed to write .netrc: %v", err)
+	}
+	t.Setenv("NETRC", netrc)
+
+	calls := 0
+	stubCredentialFill(t, func(input string) (string, error) {
+		calls++
+		if strings.Contains(input, "host=github.com\n") {
+			return "protocol=https\nhost=github.com\nusername=octocat\npassword=from-helper\n", nil
+		}
+		return "", errors.New("terminal prompts disabled")
+	})
+
+	r := newCredentialResolver(map[string]utils.HostCredential{
+		"gitlab.example.com": {Token: "from-config"},
+		"git.internal:8443":  {Token: "from-config"},
+	})
+
+	tests := []struct {
+		url  string
+		want *hostCredential
+	}{
+		{url: "https://github.com/team/repo.git", want: &hostCredential{Username: "octocat", Password: "from-helper"}},
+		{url: "https://gitlab.example.com/team/repo.git", want: &hostCredential{Username: "deploy", Password: "from-netrc"}},
+		{url: "https://git.internal:8443/team/repo.git", want: &hostCredential{Username: "git", Password: "from-config"}},
+		{url: "https://bitbucket.org/team/repo.git", want: nil},
+	}
+
+	for _, tt := range tests {
+		got := r.lookup(tt.url)
+		if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+			t.Errorf("lookup(%s) = %+v, want %+v", tt.url, got, tt.want)
+		}
+	}
+
+	r.lookup("https://github.com/team/other.git")
+	if calls != len(tests) {
+		t.Errorf("credential helper called %d times, want %d (cached per host)", calls, len(tests))
+	}
+}
+
+func TestApplyHTTPSCredentials(t *testing.T) {
+	t.Setenv("NETRC", filepath.Join(t.TempDir(), "missing"))
+	t.Setenv("WHITEROSE_SECRETS_FILE", filepath.Join(t.TempDir(), "missing.enc"))
+	t.Setenv("GIT_USER", "env-user")
+	t.Setenv("GIT_TOKEN", "env-token")
+	t.Setenv("GITLAB_USER", "ref-user")
+	t.Setenv("GITLAB_TOKEN", "secret://gitlab-token")
+	t.Setenv("WHITEROSE_SECRET_GITLAB_TOKEN", "ref-token")
+	stubCredentialFill(t, func(string) (string, error) { return "", errors.New("no helper") })
+
+	g := NewGitRepository()
+	r := newCredentialResolver(map[string]utils.HostCredential{"github.com": {Username: "octocat", Token: "cfg-token"}})
+
+	tests := []struct {
+		name               string
+		repo               GitCloneOptions
+		wantUser, wantPass string
+	}{
+		{name: "credential ref", repo: GitCloneOptions{URL: "https://github.com/a/b", CredentialRef: "gitlab"}, wantUser: "ref-user", wantPass: "ref-token"},
+		{name: "per host", repo: GitCloneOptions{URL: "https://github.com/a/b"}, wantUser: "octocat", wantPass: "cfg-token"},
+		{name: "env fallback", repo: GitCloneOptions{URL: "https://gitlab.com/a/b"}, wantUser: "env-user", wantPass: "env-token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := g.applyHTTPSCredentials(&tt.repo, r); err != nil {
+				t.Fatalf("applyHTTPSCredentials() error = %v", err)
+			}
+			if tt.repo.Username != tt.wantUser || tt.repo.Password != tt.wantPass {
+				t.Errorf("credentials = %s/%s, want %s/%s", tt.repo.Username, tt.repo.Password, tt.wantUser, tt.wantPass)
+			}
+		})
+	}
+}
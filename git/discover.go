@@ -0,0 +1,64 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/fabianoflorentino/whiterose/utils"
+	"github.com/go-git/go-git/v5"
+)
+
+// DiscoverRepositories returns a repository entry for every clone directly under root, using the
+// URL of its origin remote, or of its first remote when there is no origin. Directories that are
+// not clones or have no remote are skipped.
+func DiscoverRepositories(root string) ([]utils.RepoInfo, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", root, err)
+	}
+
+	var repos []utils.RepoInfo
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		dir := filepath.Join(root, entry.Name())
+		url, err := remoteURL(dir)
+		if err != nil || url == "" {
+			continue
+		}
+		repos = append(repos, utils.RepoInfo{URL: url, Directory: dir})
+	}
+
+	return repos, nil
+}
+
+// remoteURL returns the fetch URL of the origin remote of the clone in dir, falling back to the
+// first remote in name order.
+func remoteURL(dir string) (string, error) {
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return "", err
+	}
+
+	remote, err := repo.Remote(git.DefaultRemoteName)
+	if errors.Is(err, git.ErrRemoteNotFound) {
+		remotes, err := repo.Remotes()
+		if err != nil || len(remotes) == 0 {
+			return "", err
+		}
+		sort.Slice(remotes, func(i, j int) bool { return remotes[i].Config().Name < remotes[j].Config().Name })
+		remote = remotes[0]
+	} else if err != nil {
+		return "", err
+	}
+
+	if urls := remote.Config().URLs; len(urls) > 0 {
+		return urls[0], nil
+	}
+	return "", nil
+}
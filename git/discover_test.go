@@ -0,0 +1,54 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+)
+
+func TestDiscoverRepositories(t *testing.T) {
+	root := t.TempDir()
+
+	initWithRemotes := func(name string, remotes map[string]string) {
+		repo, err := git.PlainInit(filepath.Join(root, name), false)
+		if err != nil {
+			t.Fatalf("failed to init %s: %v", name, err)
+		}
+		for remote, url := range remotes {
+			if _, err := repo.CreateRemote(&gitconfig.RemoteConfig{Name: remote, URLs: []string{url}}); err != nil {
+				t.Fatalf("failed to add remote: %v", err)
+			}
+		}
+	}
+
+	initWithRemotes("api", map[string]string{"origin": "git@github.com:org/api.git", "upstream": "git@github.com:up/api.git"})
+	initWithRemotes("web", map[string]string{"upstream": "https://github.com/up/web.git", "zfork": "https://github.com/me/web.git"})
+	initWithRemotes("local-only", nil)
+	if err := os.Mkdir(filepath.Join(root, "plain"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "notes.txt"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	repos, err := DiscoverRepositories(root)
+	if err != nil {
+		t.Fatalf("DiscoverRepositories() error = %v", err)
+	}
+	if len(repos) != 2 {
+		t.Fatalf("DiscoverRepositories() = %+v, want api and web", repos)
+	}
+	if repos[0].URL != "git@github.com:org/api.git" || repos[0].Directory != filepath.Join(root, "api") {
+		t.Errorf("repos[0] = %+v, want the origin remote of api", repos[0])
+	}
+	if repos[1].URL != "https://github.com/up/web.git" {
+		t.Errorf("repos[1] = %+v, want the first remote of web", repos[1])
+	}
+
+	if _, err := DiscoverRepositories(filepath.Join(root, "missing")); err == nil {
+		t.Error("DiscoverRepositories() of a missing directory succeeded")
+	}
+}
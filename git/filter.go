@@ -0,0 +1,88 @@
+package git
+
+import (
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// RepoFilter selects a subset of the configured repositories. Each non-empty criterion must
+// match (AND); within a criterion any value may match (OR). An empty filter matches everything.
+type RepoFilter struct {
+	// Names matches the repository name derived from its URL or its directory.
+	Names []string
+	// Tags matches repositories carrying at least one of the tags.
+	Tags []string
+	// Groups matches repositories belonging to at least one of the groups.
+	Groups []string
+	// Globs matches the repository directory, or its base name, against shell patterns.
+	Globs []string
+}
+
+// IsEmpty reports whether the filter has no criteria.
+func (f RepoFilter) IsEmpty() bool {
+	return len(f.Names) == 0 && len(f.Tags) == 0 && len(f.Groups) == 0 && len(f.Globs) == 0
+}
+
+// Match reports whether the repository satisfies the filter.
+func (f RepoFilter) Match(repo GitCloneOptions) bool {
+	if len(f.Names) > 0 && !slices.ContainsFunc(f.Names, func(n string) bool {
+		return strings.EqualFold(n, repo.Name()) || strings.EqualFold(filepath.Clean(n), filepath.Clean(repo.Directory))
+	}) {
+		return false
+	}
+
+	if len(f.Tags) > 0 && !containsAnyFold(repo.Tags, f.Tags) {
+		return false
+	}
+
+	if len(f.Groups) > 0 && !containsAnyFold(repo.Groups, f.Groups) {
+		return false
+	}
+
+	if len(f.Globs) > 0 && !slices.ContainsFunc(f.Globs, func(g string) bool {
+		full, _ := filepath.Match(g, repo.Directory)
+		base, _ := filepath.Match(g, filepath.Base(repo.Directory))
+		return full || base
+	}) {
+		return false
+	}
+
+	return true
+}
+
+// containsAnyFold reports whether values contains any of wanted, ignoring case.
+func containsAnyFold(values, wanted []string) bool {
+	return slices.ContainsFunc(wanted, func(w string) bool {
+		return slices.ContainsFunc(values, func(v string) bool { return strings.EqualFold(v, w) })
+	})
+}
+
+// FilterRepositories returns the repositories matching f, preserving their order.
+func FilterRepositories(repos []GitCloneOptions, f RepoFilter) []GitCloneOptions {
+	if f.IsEmpty() {
+		return repos
+	}
+
+	var selected []GitCloneOptions
+	for _, r := range repos {
+		if f.Match(r) {
+			selected = append(selected, r)
+		}
+	}
+	return selected
+}
+
+// Name returns the repository name derived from its URL, e.g. "mr-robot" for
+// git@github.com:fabianoflorentino/mr-robot.git, falling back to the directory base name.
+func (g GitCloneOptions) Name() string {
+	u := strings.TrimSuffix(strings.TrimSuffix(g.URL, "/"), ".git")
+	if i := strings.LastIndexAny(u, "/:"); i >= 0 {
+		u = u[i+1:]
+	}
+	if u == "" {
+		return path.Base(filepath.ToSlash(g.Directory))
+	}
+	return u
+}
@@ -0,0 +1,65 @@
+package git
+
+import (
+	"testing"
+)
+
+func TestGitCloneOptions_Name(t *testing.T) {
+	tests := []struct {
+		url, dir, want string
+	}{
+		{"git@github.com:fabianoflorentino/mr-robot.git", "mr_robot", "mr-robot"},
+		{"https://github.com/org/service.git", "svc", "service"},
+		{"https://github.com/org/service/", "svc", "service"},
+		{"", "path/to/dir", "dir"},
+	}
+
+	for _, tt := range tests {
+		if got := (GitCloneOptions{URL: tt.url, Directory: tt.dir}).Name(); got != tt.want {
+			t.Errorf("Name(%q, %q) = %q, want %q", tt.url, tt.dir, got, tt.want)
+		}
+	}
+}
+
+func TestFilterRepositories(t *testing.T) {
+	repos := []GitCloneOptions{
+		{URL: "git@github.com:org/api.git", Directory: "services/api", Tags: []string{"backend", "go"}, Groups: []string{"payments"}},
+		{URL: "git@github.com:org/web.git", Directory: "apps/web", Tags: []string{"frontend"}, Groups: []string{"payments", "web"}},
+		{URL: "git@github.com:org/worker.git", Directory: "services/worker", Tags: []string{"backend"}},
+	}
+
+	tests := []struct {
+		name   string
+		filter RepoFilter
+		want   []string
+	}{
+		{"empty", RepoFilter{}, []string{"services/api", "apps/web", "services/worker"}},
+		{"by name", RepoFilter{Names: []string{"web"}}, []string{"apps/web"}},
+		{"by directory", RepoFilter{Names: []string{"services/worker"}}, []string{"services/worker"}},
+		{"by tag", RepoFilter{Tags: []string{"Backend"}}, []string{"services/api", "services/worker"}},
+		{"by glob", RepoFilter{Globs: []string{"services/*"}}, []string{"services/api", "services/worker"}},
+		{"by base glob", RepoFilter{Globs: []string{"w*"}}, []string{"apps/web", "services/worker"}},
+		{"tag and glob", RepoFilter{Tags: []string{"backend"}, Globs: []string{"a*"}}, []string{"services/api"}},
+		{"by group", RepoFilter{Groups: []string{"payments"}}, []string{"services/api", "apps/web"}},
+		{"group and only", RepoFilter{Groups: []string{"payments"}, Names: []string{"web", "worker"}}, []string{"apps/web"}},
+		{"no match", RepoFilter{Names: []string{"nope"}}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FilterRepositories(repos, tt.filter)
+			var dirs []string
+			for _, r := range got {
+				dirs = append(dirs, r.Directory)
+			}
+			if len(dirs) != len(tt.want) {
+				t.Fatalf("FilterRepositories() = %v, want %v", dirs, tt.want)
+			}
+			for i := range dirs {
+				if dirs[i] != tt.want[i] {
+					t.Errorf("FilterRepositories() = %v, want %v", dirs, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -3,25 +3,38 @@
 //
 // Types:
 //   - GitCloneOptions: Options for cloning a Git repository, including URL, directory, credentials, and SSH key information.
+//   - SetupOptions: Options controlling a Setup run, such as the number of concurrent clone workers.
+//   - CloneResult: The outcome of cloning a single repository.
 //
 // Functions:
-//   - FetchRepositories: Clones multiple repositories based on provided options.
+//   - Setup: Clones (or synchronises) the configured repositories concurrently using a bounded worker pool.
+//   - Pull / Fetch: Fast-forward or fetch every configured repository concurrently.
 //   - LoadRepositoriesFromFile: Loads repository clone options from a JSON file.
-//   - clone: Clones a single repository and checks out the 'development' branch or creates a user-specific branch if not present.
-//   - createSSHAuth: Creates SSH authentication using a private key file, with support for default key locations and names.
+//   - cloneOrSync: Clones a repository, or fetches and fast-forwards it when the directory already exists.
+//   - pullRepository / fetchRepository: Fast-forward or fetch an existing clone, used by Pull and Fetch.
+//   - clone: Clones a single repository and checks out the configured branch (default 'development'), its fallback,
+//     or creates a branch from the configured template (default 'development/<user>').
+//   - credentialResolver: Resolves HTTPS credentials per host from git credential helpers, ~/.netrc or the config file.
+//   - createSSHAuth: Creates SSH authentication from ssh-agent or a private key file, decrypting passphrase-protected keys.
+//   - hostKeyCallbackFor: Verifies SSH host keys against known_hosts, optionally trusting new hosts on first use.
 package git
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
-	"path/filepath"
-	"strings"
 
+	"github.com/fabianoflorentino/whiterose/config"
+	"github.com/fabianoflorentino/whiterose/exitcode"
+	"github.com/fabianoflorentino/whiterose/internal/domain/entities"
+	"github.com/fabianoflorentino/whiterose/output"
+	"github.com/fabianoflorentino/whiterose/secrets"
 	"github.com/fabianoflorentino/whiterose/utils"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
-	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
 )
 
 // GitCloneOptions holds options for cloning a Git repository, including URL, directory, credentials, and SSH key information.
@@ -32,6 +45,33 @@ type GitCloneOptions struct {
 	Password   string
 	SSHKeyPath string
 	SSHKeyName string
+	// SSHKeyPassphrase decrypts an encrypted SSH key; when empty the user is prompted for it.
+	SSHKeyPassphrase string
+
+	// Branch is checked out after cloning; defaults to DefaultBranch.
+	Branch string
+	// FallbackBranch is checked out when Branch does not exist.
+	FallbackBranch string
+	// CreateBranchTemplate names the branch created when neither Branch nor FallbackBranch exist;
+	// defaults to DefaultCreateBranchTemplate.
+	CreateBranchTemplate string
+	// Depth limits the clone to the given number of commits; 0 clones the full history.
+	Depth int
+	// SingleBranch fetches only Branch instead of every remote branch.
+	SingleBranch bool
+	// Submodules recursively initialises the repository submodules.
+	Submodules bool
+	// AuthMethod forces "ssh" or "https" authentication; when empty it is derived from the URL.
+	AuthMethod string
+	// CredentialRef selects the <REF>_USER and <REF>_TOKEN environment variables as HTTPS credentials.
+	CredentialRef string
+	// Tags are free-form labels used to select repositories.
+	Tags []string
+	// Groups are the teams or squads the repository belongs to, used by --group.
+	Groups []string
+
+	// progress receives the go-git transfer progress; nil disables it.
+	progress io.Writer
 }
 
 // NewGitRepository creates and returns a new GitCloneOptions instance.
@@ -39,37 +79,156 @@ func NewGitRepository() *GitCloneOptions {
 	return &GitCloneOptions{}
 }
 
+// SetupOptions controls how Setup, Pull and Fetch process the configured repositories.
+type SetupOptions struct {
+	// Workers is the number of repositories processed concurrently. When zero, the
+	// "workers" key of the configuration file is used, falling back to DefaultWorkers.
+	Workers int
+	// Filter restricts the run to the matching repositories; the zero value selects all.
+	Filter RepoFilter
+	// Format selects how the summary is printed. With JSON or YAML the progress messages are
+	// written to stderr so that stdout only holds the summary; the zero value prints text.
+	Format output.Format
+}
+
 // Setup loads repository configuration, sets authentication options from environment variables, and clones repositories.
-func (g *GitCloneOptions) Setup() {
-	cfg := g.loadConfigFile(filepath.Base(os.Getenv("CONFIG_FILE")))
-	repos, err := LoadRepositoriesFromFile(cfg)
+// Configuration errors carry the exitcode.Config status and failed repositories the exitcode.Partial status.
+func (g *GitCloneOptions) Setup(setupOpts SetupOptions) error {
+	repos, workers, err := g.prepareRepositories(setupOpts)
+	if err != nil {
+		return exitcode.Wrap(exitcode.Config, fmt.Errorf("failed to load repositories: %w", err))
+	}
+
+	return g.fetchRepositories(repos, workers, cloneOrSync, setupOpts.Format)
+}
+
+// Pull fetches every configured repository and fast-forwards its current branch.
+// Repositories that are not cloned, have local changes or have diverged from upstream are skipped.
+func (g *GitCloneOptions) Pull(setupOpts SetupOptions) error {
+	repos, workers, err := g.prepareRepositories(setupOpts)
+	if err != nil {
+		return exitcode.Wrap(exitcode.Config, fmt.Errorf("failed to load repositories: %w", err))
+	}
+
+	return g.fetchRepositories(repos, workers, pullRepository, setupOpts.Format)
+}
+
+// Fetch fetches every configured repository without touching its worktree.
+func (g *GitCloneOptions) Fetch(setupOpts SetupOptions) error {
+	repos, workers, err := g.prepareRepositories(setupOpts)
+	if err != nil {
+		return exitcode.Wrap(exitcode.Config, fmt.Errorf("failed to load repositories: %w", err))
+	}
+
+	return g.fetchRepositories(repos, workers, fetchRepository, setupOpts.Format)
+}
+
+// prepareRepositories loads the configured repositories, applies credentials from environment
+// variables and resolves the number of workers.
+func (g *GitCloneOptions) prepareRepositories(setupOpts SetupOptions) ([]GitCloneOptions, int, error) {
+	cfg, err := config.Load()
 	if err != nil {
-		fmt.Printf("failed to load repositories: %v", err)
-		os.Exit(1)
+		return nil, 0, err
+	}
+	repos := FilterRepositories(repositoriesFromConfig(cfg.Repositories), setupOpts.Filter)
+
+	workers := setupOpts.Workers
+	if workers <= 0 {
+		workers = cfg.Workers
 	}
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+
+	resolver := newCredentialResolver(cfg.Credentials)
 
 	for i := range repos {
-		repos[i].Username = utils.GetEnvOrDefault("GIT_USER", "")
-		repos[i].Password = utils.GetEnvOrDefault("GIT_TOKEN", "")
-		repos[i].SSHKeyPath = utils.GetEnvOrDefault("SSH_KEY_PATH", "")
+		if err := g.applyHTTPSCredentials(&repos[i], resolver); err != nil {
+			return nil, 0, fmt.Errorf("%s: %w", repos[i].Directory, err)
+		}
+		if repos[i].SSHKeyPath == "" {
+			repos[i].SSHKeyPath = utils.GetEnvOrDefault("SSH_KEY_PATH", "")
+		}
 		repos[i].SSHKeyName = utils.GetEnvOrDefault("SSH_KEY_NAME", "id_rsa")
+		repos[i].SSHKeyPassphrase, err = secrets.Resolve(utils.GetEnvOrDefault("SSH_KEY_PASSPHRASE", ""))
+		if err != nil {
+			return nil, 0, fmt.Errorf("SSH_KEY_PASSPHRASE: %w", err)
+		}
+	}
+
+	return repos, workers, nil
+}
+
+// applyHTTPSCredentials sets the HTTPS username and password of repo. A CredentialRef selects the
+// <REF>_USER and <REF>_TOKEN environment variables; otherwise HTTPS repositories use the credentials
+// resolved for their host, falling back to GIT_USER and GIT_TOKEN. Values written as "secret://name"
+// are looked up in the secret backends.
+func (g *GitCloneOptions) applyHTTPSCredentials(repo *GitCloneOptions, resolver *credentialResolver) error {
+	username := utils.GetEnvOrDefault("GIT_USER", "")
+	password := utils.GetEnvOrDefault("GIT_TOKEN", "")
+
+	if ref := repo.CredentialRef; ref != "" {
+		userKey, tokenKey := credentialEnvKeys(ref)
+		username = utils.GetEnvOrDefault(userKey, "")
+		password = utils.GetEnvOrDefault(tokenKey, "")
+	} else if usesHTTPS(*repo) {
+		if cred := resolver.lookup(repo.URL); cred != nil {
+			username, password = cred.Username, cred.Password
+		}
+	}
+
+	if !usesHTTPS(*repo) {
+		repo.Username, repo.Password = username, password
+		return nil
 	}
 
-	if err := g.fetchRepositories(repos); err != nil {
-		fmt.Printf("failed to fetch repositories: %v", err)
-		os.Exit(1)
+	var err error
+	if repo.Username, err = secrets.Resolve(username); err != nil {
+		return err
 	}
+	if repo.Password, err = secrets.Resolve(password); err != nil {
+		return err
+	}
+
+	return nil
 }
 
-// fetchRepositories clones multiple repositories based on the provided options.
-func (g *GitCloneOptions) fetchRepositories(repos []GitCloneOptions) error {
-	for _, opts := range repos {
-		fmt.Printf("Cloning %s into %s...\n", opts.URL, opts.Directory)
-		if err := clone(opts); err != nil {
-			return fmt.Errorf("error cloning %s: %w", opts.URL, err)
+// fetchRepositories runs fn for multiple repositories concurrently using up to workers goroutines,
+// prints a summary of the results in format and returns an error if any repository failed.
+func (g *GitCloneOptions) fetchRepositories(repos []GitCloneOptions, workers int, fn cloneFunc, format output.Format) error {
+	progress := io.Writer(os.Stdout)
+	if format.Structured() {
+		progress = os.Stderr
+	}
+
+	if len(repos) == 0 {
+		fmt.Fprintln(progress, "No repositories configured.")
+		if format.Structured() {
+			return output.Render(os.Stdout, format, CloneResults{})
+		}
+		return nil
+	}
+
+	fmt.Fprintf(progress, "Processing %d repositories with %d workers...\n", len(repos), min(workers, len(repos)))
+
+	// go-git transfer progress is only readable when a single repository is processed at a time.
+	if workers == 1 {
+		for i := range repos {
+			repos[i].progress = progress
 		}
 	}
 
+	results := cloneAll(repos, workers, fn, progress)
+
+	fmt.Fprintln(progress)
+	if err := output.Render(os.Stdout, format, CloneResults(results)); err != nil {
+		return err
+	}
+
+	if failed := countFailed(results); failed > 0 {
+		return exitcode.Errorf(exitcode.Partial, "%d of %d repositories failed", failed, len(results))
+	}
+
 	return nil
 }
 
@@ -79,116 +238,148 @@ func LoadRepositoriesFromFile(file string) ([]GitCloneOptions, error) {
 	if err != nil {
 		return nil, err
 	}
+	return repositoriesFromConfig(repoInfos), nil
+}
+
+// repositoriesFromConfig converts the configured repositories into clone options.
+func repositoriesFromConfig(repoInfos []utils.RepoInfo) []GitCloneOptions {
 	var opts []GitCloneOptions
 	for _, r := range repoInfos {
-		opts = append(opts, GitCloneOptions{
-			URL:       r.URL,
-			Directory: r.Directory,
+		o := GitCloneOptions{
+			URL:                  r.URL,
+			Directory:            r.Directory,
+			Branch:               r.Branch,
+			FallbackBranch:       r.FallbackBranch,
+			CreateBranchTemplate: r.CreateBranchTemplate,
+			Depth:                r.Depth,
+			SingleBranch:         r.SingleBranch,
+			Submodules:           r.Submodules,
+			Tags:                 r.Tags,
+			Groups:               r.Groups,
 			// Username, Password, SSHKeyPath, SSHKeyName can be set later or via env
-		})
+		}
+		if r.Auth != nil {
+			o.AuthMethod = r.Auth.Method
+			o.CredentialRef = r.Auth.CredentialRef
+			o.SSHKeyPath = r.Auth.SSHKeyPath
+		}
+		opts = append(opts, o)
 	}
-	return opts, nil
+	return opts
 }
 
-// clone clones a single Git repository into the specified directory, checks out the 'development' branch, or creates a user-specific branch if not present.
-func clone(opts GitCloneOptions) error {
+// cloneOrSync clones the repository when its directory does not exist yet, or
+// synchronises the existing clone otherwise, so that Setup can be re-run safely.
+func cloneOrSync(opts GitCloneOptions, out io.Writer) (CloneStatus, error) {
 	if _, err := os.Stat(opts.Directory); err == nil {
-		return fmt.Errorf("directory %s already exists", opts.Directory)
+		return syncRepository(opts, out)
 	}
 
-	cloneOpts := &git.CloneOptions{
-		URL:      opts.URL,
-		Progress: os.Stdout,
+	if err := clone(opts, out); err != nil {
+		return CloneStatusFailed, err
 	}
 
-	if strings.HasPrefix(opts.URL, "https://") {
-		cloneOpts.Auth = &http.BasicAuth{
-			Username: opts.Username,
-			Password: opts.Password,
-		}
-	} else if strings.HasPrefix(opts.URL, "git@") || strings.HasPrefix(opts.URL, "ssh://") {
-		auth, err := createSSHAuth(opts.SSHKeyPath)
-		if err != nil {
-			return fmt.Errorf("failed to create SSH auth: %w", err)
-		}
-		cloneOpts.Auth = auth
+	return CloneStatusCloned, nil
+}
+
+// clone clones a single Git repository into the specified directory and checks out the configured branch,
+// its fallback, or a newly created branch named after CreateBranchTemplate.
+func clone(opts GitCloneOptions, out io.Writer) error {
+	auth, err := authFor(opts)
+	if err != nil {
+		return err
+	}
+
+	branch := opts.Branch
+	if branch == "" {
+		branch = DefaultBranch
 	}
 
-	fmt.Println("Cloning repository...")
+	cloneOpts := &git.CloneOptions{
+		URL:          opts.URL,
+		Auth:         auth,
+		Progress:     opts.progress,
+		Depth:        opts.Depth,
+		SingleBranch: opts.SingleBranch,
+	}
+	if opts.Submodules {
+		cloneOpts.RecurseSubmodules = git.DefaultSubmoduleRecursionDepth
+	}
+	if opts.SingleBranch {
+		cloneOpts.ReferenceName = plumbing.NewBranchReferenceName(branch)
+	}
+
+	fmt.Fprintln(out, "Cloning repository...")
 	repo, err := git.PlainClone(opts.Directory, false, cloneOpts)
+	if err != nil && opts.SingleBranch && opts.FallbackBranch != "" && errors.Is(err, git.NoMatchingRefSpecError{}) {
+		fmt.Fprintf(out, "Branch %s not found, cloning %s instead...\n", branch, opts.FallbackBranch)
+		_ = os.RemoveAll(opts.Directory)
+		cloneOpts.ReferenceName = plumbing.NewBranchReferenceName(opts.FallbackBranch)
+		repo, err = git.PlainClone(opts.Directory, false, cloneOpts)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to clone repository: %w", err)
 	}
 
-	// After cloning, try to checkout the development branch
 	worktree, err := repo.Worktree()
 	if err != nil {
 		return fmt.Errorf("failed to get worktree: %w", err)
 	}
 
-	err = worktree.Checkout(&git.CheckoutOptions{
-		Branch: "refs/heads/development",
-	})
-	if err == nil {
-		fmt.Println("Checked out to development branch.")
-		return nil
+	for _, name := range []string{branch, opts.FallbackBranch} {
+		if name == "" {
+			continue
+		}
+		if err := checkoutBranch(repo, worktree, name); err == nil {
+			fmt.Fprintf(out, "Checked out to %s branch.\n", name)
+			return nil
+		}
 	}
 
-	// If it does not exist, create the local branch development/<user_name>
-	newBranch := fmt.Sprintf("development/%s", os.Getenv("USER"))
+	// If neither exists, create a local branch from the template, e.g. development/<user_name>
+	newBranch, err := renderBranchTemplate(opts.CreateBranchTemplate)
+	if err != nil {
+		return err
+	}
 	err = worktree.Checkout(&git.CheckoutOptions{
-		Branch: plumbing.ReferenceName("refs/heads/" + newBranch),
+		Branch: plumbing.NewBranchReferenceName(newBranch),
 		Create: true,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create and checkout branch %s: %w", newBranch, err)
 	}
-	fmt.Printf("Created and checked out to branch %s.\n", newBranch)
+	fmt.Fprintf(out, "Created and checked out to branch %s.\n", newBranch)
 
 	return nil
 }
 
-// createSSHAuth creates SSH authentication using a private key file, supporting default key locations and names.
-func createSSHAuth(keyPath string) (*ssh.PublicKeys, error) {
-	if keyPath == "" {
-		homeDir, err := os.UserHomeDir()
+// authFor returns the authentication method matching the repository URL scheme:
+// BasicAuth for HTTPS, ssh-agent or public keys for SSH and nil for anything else (e.g. local paths).
+// An explicit AuthMethod takes precedence over the URL scheme.
+func authFor(opts GitCloneOptions) (transport.AuthMethod, error) {
+	switch {
+	case usesHTTPS(opts):
+		return &http.BasicAuth{
+			Username: opts.Username,
+			Password: opts.Password,
+		}, nil
+	case UsesSSH(opts):
+		auth, err := sshAuthFor(opts)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get user home directory: %w", err)
-		}
-
-		keyName := utils.GetEnvOrDefault("SSH_KEY_NAME", "id_rsa")
-		keyPath = filepath.Join(homeDir, ".ssh", keyName)
-
-	} else {
-		fi, err := os.Stat(keyPath)
-		if err == nil && fi.IsDir() {
-			keyName := utils.GetEnvOrDefault("SSH_KEY_NAME", "id_rsa")
-			keyPath = filepath.Join(keyPath, keyName)
+			return nil, fmt.Errorf("failed to create SSH auth: %w", err)
 		}
+		return auth, nil
+	default:
+		return nil, nil
 	}
-
-	sshKey, err := os.ReadFile(keyPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read SSH key file: %w", err)
-	}
-
-	publickeys, err := ssh.NewPublicKeys("git", sshKey, "")
-	if err != nil {
-		return nil, fmt.Errorf("failed to create SSH public keys: %w", err)
-	}
-
-	return publickeys, nil
 }
 
-// loadConfigFile determines the configuration file path based on file extension and existence of YAML/YML files in the user's home directory.
-func (g *GitCloneOptions) loadConfigFile(f string) string {
-	if utils.YmlOrYamlExistsInHomeDir() {
-		if strings.HasSuffix(f, ".yaml") {
-			return utils.GetEnvOrDefault("CONFIG_FILE", os.Getenv("HOME")+"/.config.yaml")
-		}
-
-		return utils.GetEnvOrDefault("CONFIG_FILE", os.Getenv("HOME")+"/.config.yml")
-	}
+// UsesSSH reports whether the repository authenticates over SSH, either explicitly or by its URL.
+func UsesSSH(opts GitCloneOptions) bool {
+	return opts.AuthMethod == "ssh" || (opts.AuthMethod == "" && entities.IsSSHURL(opts.URL))
+}
 
-	return utils.GetEnvOrDefault("CONFIG_FILE", os.Getenv("HOME")+"/.config.json")
+// usesHTTPS reports whether the repository authenticates over HTTPS, either explicitly or by its URL.
+func usesHTTPS(opts GitCloneOptions) bool {
+	return opts.AuthMethod == "https" || (opts.AuthMethod == "" && entities.IsHTTPSURL(opts.URL))
 }
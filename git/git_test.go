@@ -3,6 +3,7 @@ package git
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -79,18 +80,65 @@ func TestGitCloneOptions_fetchRepositories(t *testing.T) {
 	t.Skip("fetchRepositories clones real repos")
 }
 
-func TestLoadConfigFile(t *testing.T) {
-	g := NewGitRepository()
-	path := g.loadConfigFile("")
-	if path == "" {
-		t.Error("expected non-empty path")
+func TestLoadRepositoriesFromFile_PerRepoSettings(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	config := `repositories:
+  - url: https://gitlab.example.com/team/repo.git
+    directory: repo
+    branch: main
+    fallbackBranch: master
+    createBranchTemplate: "feature/{{.User}}"
+    depth: 1
+    singleBranch: true
+    submodules: true
+    tags: [backend, go]
+    groups: [payments]
+    auth:
+      method: https
+      credentialRef: gitlab
+      sshKeyPath: /keys/gitlab`
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatalf("failed to create config: %v", err)
+	}
+
+	repos, err := LoadRepositoriesFromFile(configPath)
+	if err != nil {
+		t.Fatalf("LoadRepositoriesFromFile() error = %v", err)
+	}
+
+	want := GitCloneOptions{
+		URL:                  "https://gitlab.example.com/team/repo.git",
+		Directory:            "repo",
+		Branch:               "main",
+		FallbackBranch:       "master",
+		CreateBranchTemplate: "feature/{{.User}}",
+		Depth:                1,
+		SingleBranch:         true,
+		Submodules:           true,
+		Tags:                 []string{"backend", "go"},
+		Groups:               []string{"payments"},
+		AuthMethod:           "https",
+		CredentialRef:        "gitlab",
+		SSHKeyPath:           "/keys/gitlab",
+	}
+	if !reflect.DeepEqual(repos[0], want) {
+		t.Errorf("repos[0] = %+v, want %+v", repos[0], want)
 	}
 }
 
-func TestLoadConfigFile_WithYaml(t *testing.T) {
-	g := NewGitRepository()
-	path := g.loadConfigFile("/some/path.yaml")
-	if path == "" {
-		t.Error("expected non-empty path")
+func TestAuthFor_ExplicitMethod(t *testing.T) {
+	auth, err := authFor(GitCloneOptions{URL: "gitlab@git.example.com:team/repo.git", AuthMethod: "https", Username: "u", Password: "p"})
+	if err != nil {
+		t.Fatalf("authFor() error = %v", err)
+	}
+	if auth == nil || auth.Name() != "http-basic-auth" {
+		t.Errorf("authFor() = %v, want http-basic-auth", auth)
+	}
+
+	auth, err = authFor(GitCloneOptions{URL: "/local/path"})
+	if err != nil || auth != nil {
+		t.Errorf("authFor(local) = %v, %v, want nil, nil", auth, err)
 	}
 }
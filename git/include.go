@@ -0,0 +1,51 @@
+package git
+
+import (
+	"fmt"
+
+	"github.com/fabianoflorentino/whiterose/config"
+	"github.com/fabianoflorentino/whiterose/secrets"
+	"github.com/fabianoflorentino/whiterose/utils"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+)
+
+func init() {
+	config.IncludeAuth = includeAuth
+}
+
+// includeAuth authenticates the configuration include read from the Git repository at rawURL.
+// HTTPS includes get the credentials resolved for their own host, never GIT_USER and GIT_TOKEN,
+// so that an include naming another host receives nothing. SSH includes use SSH_KEY_PATH or
+// ssh-agent like the repositories, with the host key verified against known_hosts.
+func includeAuth(rawURL string) (transport.AuthMethod, error) {
+	opts := GitCloneOptions{URL: rawURL}
+
+	switch {
+	case usesHTTPS(opts):
+		cred := newCredentialResolver(nil).lookup(rawURL)
+		if cred == nil {
+			return nil, nil
+		}
+		username, err := secrets.Resolve(cred.Username)
+		if err != nil {
+			return nil, err
+		}
+		password, err := secrets.Resolve(cred.Password)
+		if err != nil {
+			return nil, err
+		}
+		return &http.BasicAuth{Username: username, Password: password}, nil
+	case UsesSSH(opts):
+		opts.SSHKeyPath = utils.GetEnvOrDefault("SSH_KEY_PATH", "")
+		opts.SSHKeyName = utils.GetEnvOrDefault("SSH_KEY_NAME", "id_rsa")
+		passphrase, err := secrets.Resolve(utils.GetEnvOrDefault("SSH_KEY_PASSPHRASE", ""))
+		if err != nil {
+			return nil, fmt.Errorf("SSH_KEY_PASSPHRASE: %w", err)
+		}
+		opts.SSHKeyPassphrase = passphrase
+		return sshAuthFor(opts)
+	default:
+		return nil, nil
+	}
+}
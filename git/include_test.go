@@ -0,0 +1,87 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fabianoflorentino/whiterose/config"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+)
+
+func TestIncludeAuth_HTTPS(t *testing.T) {
+	if config.IncludeAuth == nil {
+		t.Fatal("config.IncludeAuth is not set")
+	}
+
+	netrc := filepath.Join(t.TempDir(), ".netrc")
+	if err := os.WriteFile(netrc, []byte("machine gitlab.example.com login deploy password from-netrc\n"), 0o600); err != nil {
+		t.Fatalf("failed to write .netrc: %v", err)
+	}
+	t.Setenv("NETRC", netrc)
+	t.Setenv("GIT_USER", "env-user")
+	t.Setenv("GIT_TOKEN", "env-token")
+	stubCredentialFill(t, func(string) (string, error) { return "", errors.New("terminal prompts disabled") })
+
+	tests := []struct {
+		url  string
+		want *http.BasicAuth
+	}{
+		{url: "https://gitlab.example.com/team/config.git", want: &http.BasicAuth{Username: "deploy", Password: "from-netrc"}},
+		{url: "https://attacker.example.net/config.git"},
+		{url: "/srv/git/config"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			auth, err := includeAuth(tt.url)
+			if err != nil {
+				t.Fatalf("includeAuth() error = %v", err)
+			}
+			if tt.want == nil {
+				if auth != nil {
+					t.Errorf("includeAuth() = %v, want no credentials", auth)
+				}
+				return
+			}
+			got, ok := auth.(*http.BasicAuth)
+			if !ok || *got != *tt.want {
+				t.Errorf("includeAuth() = %v, want %v", auth, tt.want)
+			}
+		})
+	}
+}
+
+func TestIncludeAuth_SSH(t *testing.T) {
+	dir := t.TempDir()
+	keyPath, _ := writeTestKey(t, dir, "")
+	t.Setenv("SSH_AUTH_SOCK", "")
+	t.Setenv("SSH_KEY_PATH", keyPath)
+	t.Setenv("SSH_KEY_PASSPHRASE", "")
+	t.Setenv("SSH_STRICT_HOST_KEY_CHECKING", HostKeyCheckingStrict)
+
+	t.Setenv("SSH_KNOWN_HOSTS", filepath.Join(dir, "missing"))
+	if _, err := includeAuth("git@github.com:team/config.git"); err == nil || !strings.Contains(err.Error(), "known_hosts") {
+		t.Errorf("includeAuth() without known_hosts error = %v, want a known_hosts error", err)
+	}
+
+	knownHosts := filepath.Join(dir, "known_hosts")
+	if err := os.WriteFile(knownHosts, nil, 0o600); err != nil {
+		t.Fatalf("failed to write known_hosts: %v", err)
+	}
+	t.Setenv("SSH_KNOWN_HOSTS", knownHosts)
+	auth, err := includeAuth("git@github.com:team/config.git")
+	if err != nil {
+		t.Fatalf("includeAuth() error = %v", err)
+	}
+	keys, ok := auth.(*ssh.PublicKeys)
+	if !ok {
+		t.Fatalf("includeAuth() = %T, want *ssh.PublicKeys", auth)
+	}
+	if keys.HostKeyCallback == nil {
+		t.Error("includeAuth() does not verify host keys")
+	}
+}
@@ -0,0 +1,216 @@
+package git
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fabianoflorentino/whiterose/output"
+)
+
+// DefaultWorkers is the number of repositories cloned concurrently when neither the
+// --workers flag nor the "workers" config key is set.
+const DefaultWorkers = 4
+
+// CloneStatus describes the outcome of processing a single repository.
+type CloneStatus string
+
+const (
+	CloneStatusCloned           CloneStatus = "cloned"
+	CloneStatusUpdated          CloneStatus = "updated"
+	CloneStatusUpToDate         CloneStatus = "up-to-date"
+	CloneStatusFetched          CloneStatus = "fetched"
+	CloneStatusSkippedDirty     CloneStatus = "skipped-dirty"
+	CloneStatusSkippedDiverged  CloneStatus = "skipped-diverged"
+	CloneStatusMismatchedRemote CloneStatus = "mismatched-remote"
+	CloneStatusNotCloned        CloneStatus = "not-cloned"
+	CloneStatusFailed           CloneStatus = "failed"
+)
+
+// summaryOrder lists the statuses in the order they are counted in the summary line.
+var summaryOrder = []CloneStatus{
+	CloneStatusCloned,
+	CloneStatusUpdated,
+	CloneStatusUpToDate,
+	CloneStatusFetched,
+	CloneStatusSkippedDirty,
+	CloneStatusSkippedDiverged,
+	CloneStatusMismatchedRemote,
+	CloneStatusNotCloned,
+	CloneStatusFailed,
+}
+
+// CloneResult holds the outcome of cloning a single repository.
+type CloneResult struct {
+	URL       string
+	Directory string
+	Status    CloneStatus
+	Err       error
+	Duration  time.Duration
+}
+
+// cloneFunc clones or synchronises a single repository, writing its progress messages to out.
+type cloneFunc func(opts GitCloneOptions, out io.Writer) (CloneStatus, error)
+
+// cloneAll runs cloneFn for every repository using a bounded pool of workers.
+// Each repository is processed independently, so a failure never stops the others.
+// Results are returned in the same order as repos.
+func cloneAll(repos []GitCloneOptions, workers int, cloneFn cloneFunc, out io.Writer) []CloneResult {
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(repos) {
+		workers = len(repos)
+	}
+
+	results := make([]CloneResult, len(repos))
+	jobs := make(chan int)
+	mu := &sync.Mutex{}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				opts := repos[i]
+				prefix := fmt.Sprintf("[%d/%d %s] ", i+1, len(repos), opts.Directory)
+				pw := &prefixWriter{mu: mu, out: out, prefix: prefix}
+
+				fmt.Fprintf(pw, "started %s\n", opts.URL)
+				start := time.Now()
+				status, err := cloneFn(opts, pw)
+
+				result := CloneResult{
+					URL:       opts.URL,
+					Directory: opts.Directory,
+					Status:    status,
+					Err:       err,
+					Duration:  time.Since(start).Round(time.Millisecond),
+				}
+				if err != nil {
+					fmt.Fprintf(pw, "%s: %v\n", status, err)
+				} else {
+					fmt.Fprintf(pw, "%s in %s\n", status, result.Duration)
+				}
+				pw.Flush()
+
+				results[i] = result
+			}
+		}()
+	}
+
+	for i := range repos {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// CloneResults are the outcomes of a setup, pull or fetch run, in config order.
+type CloneResults []CloneResult
+
+// Text writes the summary table followed by a count per status.
+func (r CloneResults) Text(w io.Writer) {
+	_ = output.WriteTable(w, r.Table())
+
+	counts := make(map[CloneStatus]int)
+	for _, res := range r {
+		counts[res.Status]++
+	}
+
+	var parts []string
+	for _, st := range summaryOrder {
+		if counts[st] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[st], st))
+		}
+	}
+	parts = append(parts, fmt.Sprintf("%d total", len(r)))
+
+	fmt.Fprintf(w, "\n%s\n", strings.Join(parts, ", "))
+}
+
+// Table lays the results out one repository per row.
+func (r CloneResults) Table() output.Table {
+	t := output.Table{Header: []string{"DIRECTORY", "STATUS", "DURATION", "DETAILS"}}
+	for _, res := range r {
+		details := res.URL
+		if res.Err != nil {
+			details = res.Err.Error()
+		}
+		t.Rows = append(t.Rows, []string{res.Directory, string(res.Status), res.Duration.String(), details})
+	}
+	return t
+}
+
+// MarshalJSON encodes the result with its error as a message and its duration as a string.
+func (r CloneResult) MarshalJSON() ([]byte, error) {
+	var errMsg string
+	if r.Err != nil {
+		errMsg = r.Err.Error()
+	}
+	return json.Marshal(struct {
+		Directory string      `json:"directory"`
+		URL       string      `json:"url"`
+		Status    CloneStatus `json:"status"`
+		Duration  string      `json:"duration"`
+		Error     string      `json:"error,omitempty"`
+	}{r.Directory, r.URL, r.Status, r.Duration.String(), errMsg})
+}
+
+// countFailed returns the number of results whose status is CloneStatusFailed.
+func countFailed(results []CloneResult) int {
+	n := 0
+	for _, r := range results {
+		if r.Status == CloneStatusFailed {
+			n++
+		}
+	}
+	return n
+}
+
+// prefixWriter prefixes every complete line written to it and serialises
+// writes to the shared output so concurrent repositories don't interleave mid-line.
+type prefixWriter struct {
+	mu     *sync.Mutex
+	out    io.Writer
+	prefix string
+	buf    bytes.Buffer
+}
+
+func (p *prefixWriter) Write(b []byte) (int, error) {
+	p.buf.Write(b)
+	for {
+		line, err := p.buf.ReadBytes('\n')
+		if err != nil {
+			// Keep the incomplete line for the next write.
+			p.buf.Reset()
+			p.buf.Write(line)
+			break
+		}
+		p.writeLine(line)
+	}
+	return len(b), nil
+}
+
+// Flush writes any buffered partial line.
+func (p *prefixWriter) Flush() {
+	if p.buf.Len() == 0 {
+		return
+	}
+	line := append(p.buf.Bytes(), '\n')
+	p.buf.Reset()
+	p.writeLine(line)
+}
+
+func (p *prefixWriter) writeLine(line []byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, _ = fmt.Fprintf(p.out, "%s%s", p.prefix, line)
+}
@@ -0,0 +1,127 @@
+package git
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCloneAll_ContinuesAfterFailure(t *testing.T) {
+	repos := []GitCloneOptions{
+		{URL: "https://example.com/a.git", Directory: "a"},
+		{URL: "https://example.com/b.git", Directory: "b"},
+		{URL: "https://example.com/c.git", Directory: "c"},
+	}
+
+	fake := func(opts GitCloneOptions, out io.Writer) (CloneStatus, error) {
+		if opts.Directory == "b" {
+			return CloneStatusFailed, errors.New("boom")
+		}
+		return CloneStatusCloned, nil
+	}
+
+	var out bytes.Buffer
+	results := cloneAll(repos, 2, fake, &out)
+
+	if len(results) != 3 {
+		t.Fatalf("len(results) = %d, want 3", len(results))
+	}
+	for i, want := range []CloneStatus{CloneStatusCloned, CloneStatusFailed, CloneStatusCloned} {
+		if results[i].Status != want {
+			t.Errorf("results[%d].Status = %v, want %v", i, results[i].Status, want)
+		}
+		if results[i].Directory != repos[i].Directory {
+			t.Errorf("results[%d].Directory = %v, want %v", i, results[i].Directory, repos[i].Directory)
+		}
+	}
+	if countFailed(results) != 1 {
+		t.Errorf("countFailed() = %d, want 1", countFailed(results))
+	}
+}
+
+func TestCloneAll_BoundedWorkers(t *testing.T) {
+	var repos []GitCloneOptions
+	for i := 0; i < 10; i++ {
+		repos = append(repos, GitCloneOptions{Directory: fmt.Sprintf("repo%d", i)})
+	}
+
+	var running, peak int32
+	fake := func(opts GitCloneOptions, out io.Writer) (CloneStatus, error) {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return CloneStatusCloned, nil
+	}
+
+	cloneAll(repos, 3, fake, io.Discard)
+
+	if peak > 3 {
+		t.Errorf("peak concurrency = %d, want <= 3", peak)
+	}
+}
+
+func TestCloneAll_PrefixesOutput(t *testing.T) {
+	repos := []GitCloneOptions{{URL: "https://example.com/a.git", Directory: "a"}}
+
+	fake := func(opts GitCloneOptions, out io.Writer) (CloneStatus, error) {
+		fmt.Fprint(out, "partial")
+		fmt.Fprintln(out, " line")
+		return CloneStatusCloned, nil
+	}
+
+	var out bytes.Buffer
+	cloneAll(repos, 0, fake, &out)
+
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if !strings.HasPrefix(line, "[1/1 a] ") {
+			t.Errorf("line %q is missing repository prefix", line)
+		}
+	}
+	if !strings.Contains(out.String(), "[1/1 a] partial line\n") {
+		t.Errorf("output = %q, want joined partial line", out.String())
+	}
+}
+
+func TestCloneResults_Text(t *testing.T) {
+	results := CloneResults{
+		{URL: "https://example.com/a.git", Directory: "a", Status: CloneStatusCloned},
+		{URL: "https://example.com/b.git", Directory: "b", Status: CloneStatusFailed, Err: errors.New("boom")},
+	}
+
+	var out bytes.Buffer
+	results.Text(&out)
+
+	got := out.String()
+	for _, want := range []string{"DIRECTORY", "cloned", "failed", "boom", "1 cloned, 1 failed, 2 total"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("summary missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestCloneResult_MarshalJSON(t *testing.T) {
+	data, err := json.Marshal(CloneResult{
+		URL: "https://example.com/b.git", Directory: "b", Status: CloneStatusFailed,
+		Err: errors.New("boom"), Duration: 1500 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	want := `{"directory":"b","url":"https://example.com/b.git","status":"failed","duration":"1.5s","error":"boom"}`
+	if string(data) != want {
+		t.Errorf("Marshal() = %s, want %s", data, want)
+	}
+}
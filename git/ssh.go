@@ -0,0 +1,301 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/fabianoflorentino/whiterose/internal/domain/entities"
+	"github.com/fabianoflorentino/whiterose/utils"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/skeema/knownhosts"
+	cryptossh "golang.org/x/crypto/ssh"
+	"golang.org/x/term"
+)
+
+// Host key checking modes selected with SSH_STRICT_HOST_KEY_CHECKING.
+const (
+	// HostKeyCheckingStrict rejects hosts missing from known_hosts.
+	HostKeyCheckingStrict = "yes"
+	// HostKeyCheckingAcceptNew trusts unknown hosts on first use and records them in known_hosts.
+	// Hosts whose key changed are always rejected.
+	HostKeyCheckingAcceptNew = "accept-new"
+)
+
+// promptPassphrase asks the user for the passphrase of an encrypted SSH key.
+// It is a variable so tests can replace the terminal prompt.
+var promptPassphrase = func(keyPath string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("SSH key %s is encrypted, set SSH_KEY_PASSPHRASE or add the key to ssh-agent", keyPath)
+	}
+
+	fmt.Fprintf(os.Stderr, "Enter passphrase for key '%s': ", keyPath)
+	passphrase, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase: %w", err)
+	}
+
+	return string(passphrase), nil
+}
+
+var (
+	// passphrases caches prompted passphrases per key path so concurrent workers only ask once.
+	passphrases   = map[string]string{}
+	passphrasesMu sync.Mutex
+
+	// knownHostsMu serialises known_hosts updates made in accept-new mode.
+	knownHostsMu sync.Mutex
+)
+
+// sshAuthFor builds the SSH authentication for a repository, including its host key verification.
+func sshAuthFor(opts GitCloneOptions) (transport.AuthMethod, error) {
+	hostKeys, err := hostKeyCallbackFor(opts.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	return createSSHAuth(entities.SSHKeyConfig{
+		Path:       opts.SSHKeyPath,
+		Name:       opts.SSHKeyName,
+		Passphrase: opts.SSHKeyPassphrase,
+	}, hostKeys)
+}
+
+// createSSHAuth creates SSH authentication from ssh-agent or a private key file.
+// When no key path is configured and SSH_AUTH_SOCK is set the agent is used; otherwise the key is read
+// from the configured path (a file, or a directory joined with the key name) or ~/.ssh/<name>.
+// Encrypted keys are decrypted with key.Passphrase, prompting for it when it is empty.
+func createSSHAuth(key entities.SSHKeyConfig, hostKeys ssh.HostKeyCallbackHelper) (ssh.AuthMethod, error) {
+	if key.Path == "" && os.Getenv("SSH_AUTH_SOCK") != "" {
+		auth, err := ssh.NewSSHAgentAuth("git")
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to ssh-agent: %w", err)
+		}
+		auth.HostKeyCallbackHelper = hostKeys
+		return auth, nil
+	}
+
+	keyPath, err := resolveSSHKeyPath(key)
+	if err != nil {
+		return nil, err
+	}
+
+	sshKey, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SSH key file: %w", err)
+	}
+
+	signer, err := parseSSHKey(keyPath, sshKey, key.Passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ssh.PublicKeys{User: "git", Signer: signer, HostKeyCallbackHelper: hostKeys}, nil
+}
+
+// SSHKeyFile returns the private key file the SSH authentication of opts reads, with the
+// SSH_KEY_PATH and SSH_KEY_NAME defaults setup applies, or an empty string when ssh-agent is used.
+func SSHKeyFile(opts GitCloneOptions) (string, error) {
+	key := entities.SSHKeyConfig{
+		Path: opts.SSHKeyPath,
+		Name: opts.SSHKeyName,
+	}
+	if key.Path == "" {
+		key.Path = utils.GetEnvOrDefault("SSH_KEY_PATH", "")
+	}
+	if key.Name == "" {
+		key.Name = utils.GetEnvOrDefault("SSH_KEY_NAME", "id_rsa")
+	}
+	if key.Path == "" && os.Getenv("SSH_AUTH_SOCK") != "" {
+		return "", nil
+	}
+	return resolveSSHKeyPath(key)
+}
+
+// resolveSSHKeyPath returns the private key file described by key, defaulting to ~/.ssh/id_rsa.
+func resolveSSHKeyPath(key entities.SSHKeyConfig) (string, error) {
+	name := key.Name
+	if name == "" {
+		name = "id_rsa"
+	}
+
+	if key.Path == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get user home directory: %w", err)
+		}
+		return filepath.Join(homeDir, ".ssh", name), nil
+	}
+
+	if fi, err := os.Stat(key.Path); err == nil && fi.IsDir() {
+		return filepath.Join(key.Path, name), nil
+	}
+
+	return key.Path, nil
+}
+
+// parseSSHKey parses a private key, decrypting it with passphrase or, when the key is encrypted
+// and no passphrase was supplied, with one prompted from the user.
+func parseSSHKey(keyPath string, pem []byte, passphrase string) (cryptossh.Signer, error) {
+	if passphrase == "" {
+		signer, err := cryptossh.ParsePrivateKey(pem)
+		var missing *cryptossh.PassphraseMissingError
+		if !errors.As(err, &missing) {
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse SSH key %s: %w", keyPath, err)
+			}
+			return signer, nil
+		}
+
+		if passphrase, err = cachedPassphrase(keyPath); err != nil {
+			return nil, err
+		}
+	}
+
+	signer, err := cryptossh.ParsePrivateKeyWithPassphrase(pem, []byte(passphrase))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt SSH key %s: %w", keyPath, err)
+	}
+
+	return signer, nil
+}
+
+// cachedPassphrase returns the passphrase already entered for keyPath or prompts for it once.
+func cachedPassphrase(keyPath string) (string, error) {
+	passphrasesMu.Lock()
+	defer passphrasesMu.Unlock()
+
+	if p, ok := passphrases[keyPath]; ok {
+		return p, nil
+	}
+
+	p, err := promptPassphrase(keyPath)
+	if err != nil {
+		return "", err
+	}
+	passphrases[keyPath] = p
+
+	return p, nil
+}
+
+// hostKeyCallbackFor verifies server host keys against the known_hosts files listed in SSH_KNOWN_HOSTS
+// (default ~/.ssh/known_hosts and /etc/ssh/ssh_known_hosts). SSH_STRICT_HOST_KEY_CHECKING selects
+// strict checking (the default) or accept-new, which records unknown hosts in the first known_hosts file.
+func hostKeyCallbackFor(rawURL string) (ssh.HostKeyCallbackHelper, error) {
+	mode := utils.GetEnvOrDefault("SSH_STRICT_HOST_KEY_CHECKING", HostKeyCheckingStrict)
+	if mode != HostKeyCheckingStrict && mode != HostKeyCheckingAcceptNew {
+		return ssh.HostKeyCallbackHelper{}, fmt.Errorf("invalid SSH_STRICT_HOST_KEY_CHECKING %q, expected %q or %q",
+			mode, HostKeyCheckingStrict, HostKeyCheckingAcceptNew)
+	}
+
+	files, err := knownHostsFiles()
+	if err != nil {
+		return ssh.HostKeyCallbackHelper{}, err
+	}
+
+	if mode == HostKeyCheckingAcceptNew {
+		if err := ensureFile(files[0]); err != nil {
+			return ssh.HostKeyCallbackHelper{}, fmt.Errorf("failed to create %s: %w", files[0], err)
+		}
+	}
+
+	var existing []string
+	for _, f := range files {
+		if _, err := os.Stat(f); err == nil {
+			existing = append(existing, f)
+		}
+	}
+	if len(existing) == 0 {
+		return ssh.HostKeyCallbackHelper{}, fmt.Errorf("no known_hosts file found in %s, add the host with ssh-keyscan or set SSH_STRICT_HOST_KEY_CHECKING=%s",
+			strings.Join(files, ", "), HostKeyCheckingAcceptNew)
+	}
+
+	db, err := knownhosts.NewDB(existing...)
+	if err != nil {
+		return ssh.HostKeyCallbackHelper{}, fmt.Errorf("failed to load known_hosts: %w", err)
+	}
+
+	helper := ssh.HostKeyCallbackHelper{HostKeyCallback: db.HostKeyCallback()}
+	if ep, err := transport.NewEndpoint(rawURL); err == nil && ep.Host != "" {
+		port := ep.Port
+		if port == 0 {
+			port = 22
+		}
+		helper.HostKeyAlgorithms = db.HostKeyAlgorithms(net.JoinHostPort(ep.Host, fmt.Sprint(port)))
+	}
+
+	if mode == HostKeyCheckingAcceptNew {
+		helper.HostKeyCallback = acceptNewHostKeys(helper.HostKeyCallback, files[0])
+	}
+
+	return helper, nil
+}
+
+// acceptNewHostKeys wraps verify so that hosts missing from known_hosts are trusted and appended to file.
+// Keys that do not match a recorded host are still rejected.
+func acceptNewHostKeys(verify cryptossh.HostKeyCallback, file string) cryptossh.HostKeyCallback {
+	return func(hostname string, remote net.Addr, key cryptossh.PublicKey) error {
+		knownHostsMu.Lock()
+		defer knownHostsMu.Unlock()
+
+		err := verify(hostname, remote, key)
+		if err == nil || !knownhosts.IsHostUnknown(err) {
+			return err
+		}
+
+		// Another worker may have recorded the host since verify was loaded.
+		if current, loadErr := knownhosts.NewDB(file); loadErr == nil {
+			if err = current.HostKeyCallback()(hostname, remote, key); err == nil || !knownhosts.IsHostUnknown(err) {
+				return err
+			}
+		}
+
+		f, err := os.OpenFile(file, os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", file, err)
+		}
+		defer f.Close()
+
+		if err := knownhosts.WriteKnownHost(f, hostname, remote, key); err != nil {
+			return fmt.Errorf("failed to add %s to %s: %w", hostname, file, err)
+		}
+		fmt.Fprintf(os.Stderr, "Permanently added '%s' (%s) to the list of known hosts.\n", knownhosts.Normalize(hostname), key.Type())
+
+		return nil
+	}
+}
+
+// knownHostsFiles returns the files listed in SSH_KNOWN_HOSTS, or ~/.ssh/known_hosts and /etc/ssh/ssh_known_hosts.
+func knownHostsFiles() ([]string, error) {
+	if files := filepath.SplitList(os.Getenv("SSH_KNOWN_HOSTS")); len(files) > 0 {
+		return files, nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user home directory: %w", err)
+	}
+
+	return []string{filepath.Join(homeDir, ".ssh", "known_hosts"), "/etc/ssh/ssh_known_hosts"}, nil
+}
+
+// ensureFile creates path and its parent directory with private permissions when they do not exist.
+func ensureFile(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	return f.Close()
+}
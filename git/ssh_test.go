@@ -0,0 +1,191 @@
+package git
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fabianoflorentino/whiterose/internal/domain/entities"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	cryptossh "golang.org/x/crypto/ssh"
+)
+
+// writeTestKey writes a new ed25519 private key to dir, encrypted when passphrase is not empty.
+func writeTestKey(t *testing.T, dir, passphrase string) (string, cryptossh.PublicKey) {
+	t.Helper()
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	var block *pem.Block
+	if passphrase == "" {
+		block, err = cryptossh.MarshalPrivateKey(priv, "")
+	} else {
+		block, err = cryptossh.MarshalPrivateKeyWithPassphrase(priv, "", []byte(passphrase))
+	}
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+
+	path := filepath.Join(dir, "id_ed25519")
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatalf("failed to write key: %v", err)
+	}
+
+	sshPub, err := cryptossh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatalf("failed to convert public key: %v", err)
+	}
+
+	return path, sshPub
+}
+
+func TestCreateSSHAuth_KeyFile(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "")
+	dir := t.TempDir()
+	path, pub := writeTestKey(t, dir, "")
+
+	tests := []struct {
+		name string
+		key  entities.SSHKeyConfig
+	}{
+		{name: "file", key: entities.SSHKeyConfig{Path: path}},
+		{name: "directory and name", key: entities.SSHKeyConfig{Path: dir, Name: "id_ed25519"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth, err := createSSHAuth(tt.key, ssh.HostKeyCallbackHelper{})
+			if err != nil {
+				t.Fatalf("createSSHAuth() error = %v", err)
+			}
+			keys, ok := auth.(*ssh.PublicKeys)
+			if !ok {
+				t.Fatalf("createSSHAuth() = %T, want *ssh.PublicKeys", auth)
+			}
+			if string(keys.Signer.PublicKey().Marshal()) != string(pub.Marshal()) {
+				t.Error("createSSHAuth() loaded a different key")
+			}
+		})
+	}
+}
+
+func TestCreateSSHAuth_EncryptedKey(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "")
+	path, _ := writeTestKey(t, t.TempDir(), "s3cret")
+
+	if _, err := createSSHAuth(entities.SSHKeyConfig{Path: path, Passphrase: "s3cret"}, ssh.HostKeyCallbackHelper{}); err != nil {
+		t.Errorf("createSSHAuth() with passphrase error = %v", err)
+	}
+	if _, err := createSSHAuth(entities.SSHKeyConfig{Path: path, Passphrase: "wrong"}, ssh.HostKeyCallbackHelper{}); err == nil {
+		t.Error("createSSHAuth() with wrong passphrase succeeded")
+	}
+
+	prompts := 0
+	original := promptPassphrase
+	promptPassphrase = func(string) (string, error) {
+		prompts++
+		return "s3cret", nil
+	}
+	t.Cleanup(func() { promptPassphrase = original })
+
+	for i := 0; i < 2; i++ {
+		if _, err := createSSHAuth(entities.SSHKeyConfig{Path: path}, ssh.HostKeyCallbackHelper{}); err != nil {
+			t.Fatalf("createSSHAuth() with prompt error = %v", err)
+		}
+	}
+	if prompts != 1 {
+		t.Errorf("prompted %d times, want 1", prompts)
+	}
+}
+
+func TestHostKeyCallbackFor(t *testing.T) {
+	knownHosts := filepath.Join(t.TempDir(), "ssh", "known_hosts")
+	t.Setenv("SSH_KNOWN_HOSTS", knownHosts)
+	_, hostKey := writeTestKey(t, t.TempDir(), "")
+	_, otherKey := writeTestKey(t, t.TempDir(), "")
+	remote := &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 22}
+
+	t.Setenv("SSH_STRICT_HOST_KEY_CHECKING", "")
+	if _, err := hostKeyCallbackFor("git@git.example.com:team/repo.git"); err == nil {
+		t.Error("strict mode without known_hosts succeeded")
+	}
+
+	t.Setenv("SSH_STRICT_HOST_KEY_CHECKING", HostKeyCheckingAcceptNew)
+	helper, err := hostKeyCallbackFor("git@git.example.com:team/repo.git")
+	if err != nil {
+		t.Fatalf("hostKeyCallbackFor(accept-new) error = %v", err)
+	}
+	if err := helper.HostKeyCallback("git.example.com:22", remote, hostKey); err != nil {
+		t.Fatalf("accept-new rejected unknown host: %v", err)
+	}
+	data, err := os.ReadFile(knownHosts)
+	if err != nil || !strings.HasPrefix(string(data), "git.example.com,192.0.2.1 ssh-ed25519 ") {
+		t.Errorf("known_hosts = %q, %v, want a git.example.com entry", data, err)
+	}
+
+	t.Setenv("SSH_STRICT_HOST_KEY_CHECKING", HostKeyCheckingStrict)
+	helper, err = hostKeyCallbackFor("git@git.example.com:team/repo.git")
+	if err != nil {
+		t.Fatalf("hostKeyCallbackFor(strict) error = %v", err)
+	}
+	if err := helper.HostKeyCallback("git.example.com:22", remote, hostKey); err != nil {
+		t.Errorf("strict mode rejected recorded host: %v", err)
+	}
+	if err := helper.HostKeyCallback("git.example.com:22", remote, otherKey); err == nil {
+		t.Error("strict mode accepted a changed host key")
+	}
+	if err := helper.HostKeyCallback("other.example.com:22", remote, hostKey); err == nil {
+		t.Error("strict mode accepted an unknown host")
+	}
+
+	t.Setenv("SSH_STRICT_HOST_KEY_CHECKING", HostKeyCheckingAcceptNew)
+	helper, err = hostKeyCallbackFor("git@git.example.com:team/repo.git")
+	if err != nil {
+		t.Fatalf("hostKeyCallbackFor(accept-new) error = %v", err)
+	}
+	if err := helper.HostKeyCallback("git.example.com:22", remote, otherKey); err == nil {
+		t.Error("accept-new accepted a changed host key")
+	}
+
+	t.Setenv("SSH_STRICT_HOST_KEY_CHECKING", "no")
+	if _, err := hostKeyCallbackFor("git@git.example.com:team/repo.git"); err == nil {
+		t.Error("invalid mode succeeded")
+	}
+}
+
+func TestSSHKeyFile(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		opts    GitCloneOptions
+		keyPath string
+		agent   string
+		want    string
+	}{
+		{name: "repository key", opts: GitCloneOptions{SSHKeyPath: filepath.Join(dir, "deploy")}, agent: "/tmp/agent.sock", want: filepath.Join(dir, "deploy")},
+		{name: "SSH_KEY_PATH directory", keyPath: dir, want: filepath.Join(dir, "id_ed25519")},
+		{name: "ssh-agent", agent: "/tmp/agent.sock", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SSH_KEY_PATH", tt.keyPath)
+			t.Setenv("SSH_KEY_NAME", "id_ed25519")
+			t.Setenv("SSH_AUTH_SOCK", tt.agent)
+
+			got, err := SSHKeyFile(tt.opts)
+			if err != nil || got != tt.want {
+				t.Errorf("SSHKeyFile() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
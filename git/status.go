@@ -0,0 +1,221 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fabianoflorentino/whiterose/config"
+	"github.com/fabianoflorentino/whiterose/output"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// RepoStatus describes the state of a local clone.
+type RepoStatus struct {
+	Directory  string      `json:"directory"`
+	URL        string      `json:"url"`
+	Cloned     bool        `json:"cloned"`
+	Branch     string      `json:"branch,omitempty"`
+	Upstream   string      `json:"upstream,omitempty"`
+	Modified   int         `json:"modified"`
+	Untracked  int         `json:"untracked"`
+	Ahead      int         `json:"ahead"`
+	Behind     int         `json:"behind"`
+	LastCommit *CommitInfo `json:"lastCommit,omitempty"`
+	Error      string      `json:"error,omitempty"`
+}
+
+// CommitInfo summarises a single commit.
+type CommitInfo struct {
+	Hash    string    `json:"hash"`
+	Author  string    `json:"author"`
+	Date    time.Time `json:"date"`
+	Subject string    `json:"subject"`
+}
+
+// IsDirty reports whether the clone has modified, staged or untracked files.
+func (s RepoStatus) IsDirty() bool {
+	return s.Modified > 0 || s.Untracked > 0
+}
+
+// LoadConfiguredRepositories loads the repositories from the configuration the same way Setup does.
+func LoadConfiguredRepositories() ([]GitCloneOptions, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	return repositoriesFromConfig(cfg.Repositories), nil
+}
+
+// Statuses are the states of the configured clones, in config order.
+type Statuses []RepoStatus
+
+// CollectStatus inspects every repository with go-git and returns their status in config order.
+// Repositories that are not cloned or cannot be read are reported rather than returned as errors.
+func CollectStatus(repos []GitCloneOptions) Statuses {
+	statuses := make(Statuses, 0, len(repos))
+	for _, r := range repos {
+		statuses = append(statuses, repoStatus(r))
+	}
+	return statuses
+}
+
+// Table lays the statuses out one repository per row.
+func (s Statuses) Table() output.Table {
+	t := output.Table{Header: []string{"DIRECTORY", "BRANCH", "MODIFIED", "UNTRACKED", "AHEAD", "BEHIND", "LAST COMMIT"}}
+
+	for _, st := range s {
+		switch {
+		case st.Error != "":
+			t.Rows = append(t.Rows, []string{st.Directory, "-", "-", "-", "-", "-", "error: " + st.Error})
+		case !st.Cloned:
+			t.Rows = append(t.Rows, []string{st.Directory, "-", "-", "-", "-", "-", "not cloned"})
+		default:
+			last := "-"
+			if st.LastCommit != nil {
+				last = fmt.Sprintf("%s %s (%s, %s)", st.LastCommit.Hash, st.LastCommit.Subject,
+					st.LastCommit.Author, st.LastCommit.Date.Format("2006-01-02"))
+			}
+			ahead, behind := "-", "-"
+			if st.Upstream != "" {
+				ahead, behind = fmt.Sprint(st.Ahead), fmt.Sprint(st.Behind)
+			}
+			t.Rows = append(t.Rows, []string{st.Directory, st.Branch, fmt.Sprint(st.Modified), fmt.Sprint(st.Untracked), ahead, behind, last})
+		}
+	}
+	return t
+}
+
+// repoStatus opens a single clone and gathers its branch, worktree and upstream state.
+func repoStatus(opts GitCloneOptions) RepoStatus {
+	status := RepoStatus{Directory: opts.Directory, URL: opts.URL}
+
+	if _, err := os.Stat(opts.Directory); err != nil {
+		return status
+	}
+
+	repo, err := git.PlainOpen(opts.Directory)
+	if err != nil {
+		status.Error = fmt.Sprintf("not a git repository: %v", err)
+		return status
+	}
+	status.Cloned = true
+
+	head, err := repo.Head()
+	if err != nil {
+		status.Error = fmt.Sprintf("failed to read HEAD: %v", err)
+		return status
+	}
+	status.Branch = "(detached)"
+	if head.Name().IsBranch() {
+		status.Branch = head.Name().Short()
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		status.Error = fmt.Sprintf("failed to get worktree: %v", err)
+		return status
+	}
+	wtStatus, err := worktree.Status()
+	if err != nil {
+		status.Error = fmt.Sprintf("failed to get worktree status: %v", err)
+		return status
+	}
+	for _, fs := range wtStatus {
+		if fs.Worktree == git.Untracked {
+			status.Untracked++
+		} else if fs.Staging != git.Unmodified || fs.Worktree != git.Unmodified {
+			status.Modified++
+		}
+	}
+
+	headCommit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		status.Error = fmt.Sprintf("failed to read HEAD commit: %v", err)
+		return status
+	}
+	status.LastCommit = &CommitInfo{
+		Hash:    head.Hash().String()[:7],
+		Author:  headCommit.Author.Name,
+		Date:    headCommit.Author.When,
+		Subject: strings.SplitN(strings.TrimSpace(headCommit.Message), "\n", 2)[0],
+	}
+
+	if !head.Name().IsBranch() {
+		return status
+	}
+
+	upstream, err := upstreamRef(repo, head.Name())
+	if err != nil || upstream == "" {
+		return status
+	}
+	upstreamRefObj, err := repo.Reference(upstream, true)
+	if err != nil {
+		return status
+	}
+	status.Upstream = upstream.Short()
+
+	upstreamCommit, err := repo.CommitObject(upstreamRefObj.Hash())
+	if err != nil {
+		status.Error = fmt.Sprintf("failed to read %s commit: %v", upstream.Short(), err)
+		return status
+	}
+
+	status.Ahead, status.Behind, err = aheadBehind(headCommit, upstreamCommit)
+	if err != nil {
+		status.Error = fmt.Sprintf("failed to compare with %s: %v", upstream.Short(), err)
+	}
+
+	return status
+}
+
+// aheadBehind counts the commits reachable from local but not upstream (ahead) and
+// the commits reachable from upstream but not local (behind), relative to their merge base.
+func aheadBehind(local, upstream *object.Commit) (int, int, error) {
+	if local.Hash == upstream.Hash {
+		return 0, 0, nil
+	}
+
+	bases, err := local.MergeBase(upstream)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var ignore []plumbing.Hash
+	for _, b := range bases {
+		ignore = append(ignore, b.Hash)
+	}
+
+	ahead, err := countCommits(local, ignore)
+	if err != nil {
+		return 0, 0, err
+	}
+	behind, err := countCommits(upstream, ignore)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return ahead, behind, nil
+}
+
+// countCommits counts the commits reachable from c without walking past the ignored hashes.
+func countCommits(c *object.Commit, ignore []plumbing.Hash) (int, error) {
+	iter := object.NewCommitPreorderIter(c, nil, ignore)
+	defer iter.Close()
+
+	n := 0
+	err := iter.ForEach(func(*object.Commit) error {
+		n++
+		return nil
+	})
+	if err != nil && !errors.Is(err, io.EOF) {
+		return 0, err
+	}
+
+	return n, nil
+}
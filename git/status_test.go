@@ -0,0 +1,65 @@
+package git
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fabianoflorentino/whiterose/output"
+	"github.com/go-git/go-git/v5"
+)
+
+func TestCollectStatus(t *testing.T) {
+	origin, originDir, cloneDir := newOriginAndClone(t)
+
+	clone, err := git.PlainOpen(cloneDir)
+	if err != nil {
+		t.Fatalf("failed to open clone: %v", err)
+	}
+	commitFile(t, clone, cloneDir, "local.txt", "local")
+	commitFile(t, origin, originDir, "remote1.txt", "r1")
+	commitFile(t, origin, originDir, "remote2.txt", "r2")
+	if err := clone.Fetch(&git.FetchOptions{}); err != nil {
+		t.Fatalf("failed to fetch: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(cloneDir, "README.md"), []byte("changed"), 0644); err != nil {
+		t.Fatalf("failed to modify file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(cloneDir, "new.txt"), []byte("new"), 0644); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+
+	statuses := CollectStatus([]GitCloneOptions{
+		{URL: originDir, Directory: cloneDir},
+		{URL: originDir, Directory: filepath.Join(t.TempDir(), "missing")},
+	})
+
+	got := statuses[0]
+	if !got.Cloned || got.Branch != "master" || got.Upstream != "origin/master" {
+		t.Errorf("status = %+v, want cloned master tracking origin/master", got)
+	}
+	if got.Modified != 1 || got.Untracked != 1 || !got.IsDirty() {
+		t.Errorf("modified/untracked = %d/%d, want 1/1", got.Modified, got.Untracked)
+	}
+	if got.Ahead != 1 || got.Behind != 2 {
+		t.Errorf("ahead/behind = %d/%d, want 1/2", got.Ahead, got.Behind)
+	}
+	if got.LastCommit == nil || got.LastCommit.Subject != "update local.txt" {
+		t.Errorf("LastCommit = %+v, want subject 'update local.txt'", got.LastCommit)
+	}
+
+	if statuses[1].Cloned {
+		t.Error("missing repository should not be reported as cloned")
+	}
+
+	var out bytes.Buffer
+	if err := output.WriteTable(&out, statuses.Table()); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "not cloned") || !strings.Contains(out.String(), "master") {
+		t.Errorf("unexpected table:\n%s", out.String())
+	}
+}
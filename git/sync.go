@@ -0,0 +1,205 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// fastForwardResult describes what fastForward did with the current branch.
+type fastForwardResult int
+
+const (
+	ffUpToDate fastForwardResult = iota
+	ffUpdated
+	ffDiverged
+	ffNoUpstream
+	ffDetached
+)
+
+// syncRepository brings an existing clone up to date instead of failing because its directory exists.
+// It verifies that the "origin" remote points at the configured URL, fetches it and, when the
+// worktree is clean, fast-forwards the current branch to its upstream.
+func syncRepository(opts GitCloneOptions, out io.Writer) (CloneStatus, error) {
+	repo, status, err := openAndFetch(opts, out)
+	if err != nil {
+		return status, err
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return CloneStatusFailed, fmt.Errorf("failed to get worktree: %w", err)
+	}
+
+	wtStatus, err := worktree.Status()
+	if err != nil {
+		return CloneStatusFailed, fmt.Errorf("failed to get worktree status: %w", err)
+	}
+	if !wtStatus.IsClean() {
+		return CloneStatusSkippedDirty, fmt.Errorf("worktree has local changes, fetched only")
+	}
+
+	result, err := fastForward(repo, worktree, out)
+	if err != nil {
+		return CloneStatusFailed, err
+	}
+
+	switch result {
+	case ffUpdated:
+		return CloneStatusUpdated, nil
+	case ffDiverged:
+		return CloneStatusSkippedDiverged, fmt.Errorf("branch has diverged from its upstream, fetched only")
+	default:
+		// Up to date, or nothing to fast-forward to: a branch without upstream or a detached HEAD.
+		return CloneStatusUpToDate, nil
+	}
+}
+
+// pullRepository fast-forwards an existing clone; repositories that are not cloned yet are skipped.
+func pullRepository(opts GitCloneOptions, out io.Writer) (CloneStatus, error) {
+	if _, err := os.Stat(opts.Directory); err != nil {
+		return CloneStatusNotCloned, fmt.Errorf("directory %s does not exist, run 'whiterose setup --repos'", opts.Directory)
+	}
+
+	return syncRepository(opts, out)
+}
+
+// fetchRepository fetches an existing clone without touching its worktree.
+func fetchRepository(opts GitCloneOptions, out io.Writer) (CloneStatus, error) {
+	if _, err := os.Stat(opts.Directory); err != nil {
+		return CloneStatusNotCloned, fmt.Errorf("directory %s does not exist, run 'whiterose setup --repos'", opts.Directory)
+	}
+
+	if _, status, err := openAndFetch(opts, out); err != nil {
+		return status, err
+	}
+
+	return CloneStatusFetched, nil
+}
+
+// openAndFetch opens the clone, checks that its origin matches the configured URL and fetches it.
+// On failure the returned status tells why the repository could not be processed.
+func openAndFetch(opts GitCloneOptions, out io.Writer) (*git.Repository, CloneStatus, error) {
+	repo, err := git.PlainOpen(opts.Directory)
+	if err != nil {
+		return nil, CloneStatusFailed, fmt.Errorf("directory %s exists but is not a git repository: %w", opts.Directory, err)
+	}
+
+	remote, err := repo.Remote(git.DefaultRemoteName)
+	if err != nil {
+		return nil, CloneStatusFailed, fmt.Errorf("failed to read remote %s: %w", git.DefaultRemoteName, err)
+	}
+
+	if urls := remote.Config().URLs; len(urls) == 0 || !sameRemoteURL(urls[0], opts.URL) {
+		return nil, CloneStatusMismatchedRemote, fmt.Errorf("remote %s is %v, expected %s", git.DefaultRemoteName, urls, opts.URL)
+	}
+
+	auth, err := authFor(opts)
+	if err != nil {
+		return nil, CloneStatusFailed, err
+	}
+
+	fmt.Fprintln(out, "Fetching repository...")
+	err = repo.Fetch(&git.FetchOptions{
+		RemoteName: git.DefaultRemoteName,
+		Auth:       auth,
+		Progress:   opts.progress,
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return nil, CloneStatusFailed, fmt.Errorf("failed to fetch: %w", err)
+	}
+
+	return repo, "", nil
+}
+
+// fastForward moves the current branch to its upstream when the upstream is a descendant of HEAD.
+// Branches without an upstream, already up to date or diverged are left untouched.
+func fastForward(repo *git.Repository, worktree *git.Worktree, out io.Writer) (fastForwardResult, error) {
+	head, err := repo.Head()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read HEAD: %w", err)
+	}
+	if !head.Name().IsBranch() {
+		fmt.Fprintln(out, "HEAD is detached, nothing to fast-forward.")
+		return ffDetached, nil
+	}
+
+	upstream, err := upstreamRef(repo, head.Name())
+	if err != nil {
+		return 0, err
+	}
+	if upstream == "" {
+		fmt.Fprintf(out, "Branch %s has no upstream, nothing to fast-forward.\n", head.Name().Short())
+		return ffNoUpstream, nil
+	}
+
+	remoteRef, err := repo.Reference(upstream, true)
+	if err != nil {
+		return 0, fmt.Errorf("failed to resolve %s: %w", upstream.Short(), err)
+	}
+	if remoteRef.Hash() == head.Hash() {
+		fmt.Fprintf(out, "Branch %s is up to date.\n", head.Name().Short())
+		return ffUpToDate, nil
+	}
+
+	headCommit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return 0, fmt.Errorf("failed to read HEAD commit: %w", err)
+	}
+	remoteCommit, err := repo.CommitObject(remoteRef.Hash())
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %s commit: %w", upstream.Short(), err)
+	}
+
+	isAncestor, err := headCommit.IsAncestor(remoteCommit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to compare %s with %s: %w", head.Name().Short(), upstream.Short(), err)
+	}
+	if !isAncestor {
+		// HEAD ahead of its upstream is not a divergence, there is just nothing to pull.
+		if ahead, _ := remoteCommit.IsAncestor(headCommit); ahead {
+			fmt.Fprintf(out, "Branch %s is ahead of %s.\n", head.Name().Short(), upstream.Short())
+			return ffUpToDate, nil
+		}
+		fmt.Fprintf(out, "Branch %s has diverged from %s, not fast-forwarding.\n", head.Name().Short(), upstream.Short())
+		return ffDiverged, nil
+	}
+
+	if err := worktree.Reset(&git.ResetOptions{Commit: remoteRef.Hash(), Mode: git.HardReset}); err != nil {
+		return 0, fmt.Errorf("failed to fast-forward %s: %w", head.Name().Short(), err)
+	}
+
+	fmt.Fprintf(out, "Fast-forwarded %s to %s.\n", head.Name().Short(), remoteRef.Hash().String()[:7])
+	return ffUpdated, nil
+}
+
+// upstreamRef returns the remote-tracking reference configured as upstream of branch,
+// or an empty name when the branch does not track anything.
+func upstreamRef(repo *git.Repository, branch plumbing.ReferenceName) (plumbing.ReferenceName, error) {
+	cfg, err := repo.Config()
+	if err != nil {
+		return "", fmt.Errorf("failed to read repository config: %w", err)
+	}
+
+	b, ok := cfg.Branches[branch.Short()]
+	if !ok || b.Remote == "" || b.Merge == "" {
+		return "", nil
+	}
+
+	return plumbing.NewRemoteReferenceName(b.Remote, b.Merge.Short()), nil
+}
+
+// sameRemoteURL reports whether two remote URLs point at the same repository,
+// ignoring a trailing slash or ".git" suffix.
+func sameRemoteURL(a, b string) bool {
+	normalize := func(u string) string {
+		u = strings.TrimSuffix(strings.TrimSpace(u), "/")
+		return strings.TrimSuffix(u, ".git")
+	}
+	return normalize(a) == normalize(b)
+}
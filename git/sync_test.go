@@ -0,0 +1,236 @@
+package git
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// commitFile writes name with content into the worktree of repo and commits it.
+func commitFile(t *testing.T, repo *git.Repository, dir, name, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+	if _, err := wt.Add(name); err != nil {
+		t.Fatalf("failed to add %s: %v", name, err)
+	}
+
+	sig := &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()}
+	if _, err := wt.Commit("update "+name, &git.CommitOptions{Author: sig}); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+}
+
+// newOriginAndClone creates an origin repository with one commit and a clone tracking it.
+func newOriginAndClone(t *testing.T) (*git.Repository, string, string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	originDir := filepath.Join(tmpDir, "origin")
+	cloneDir := filepath.Join(tmpDir, "clone")
+
+	origin, err := git.PlainInit(originDir, false)
+	if err != nil {
+		t.Fatalf("failed to init origin: %v", err)
+	}
+	commitFile(t, origin, originDir, "README.md", "v1")
+
+	if _, err := git.PlainClone(cloneDir, false, &git.CloneOptions{URL: originDir}); err != nil {
+		t.Fatalf("failed to clone origin: %v", err)
+	}
+
+	return origin, originDir, cloneDir
+}
+
+func TestSyncRepository_FastForwards(t *testing.T) {
+	origin, originDir, cloneDir := newOriginAndClone(t)
+	commitFile(t, origin, originDir, "README.md", "v2")
+
+	status, err := cloneOrSync(GitCloneOptions{URL: originDir, Directory: cloneDir}, io.Discard)
+	if err != nil {
+		t.Fatalf("cloneOrSync() error = %v", err)
+	}
+	if status != CloneStatusUpdated {
+		t.Errorf("status = %v, want %v", status, CloneStatusUpdated)
+	}
+
+	content, err := os.ReadFile(filepath.Join(cloneDir, "README.md"))
+	if err != nil {
+		t.Fatalf("failed to read README.md: %v", err)
+	}
+	if string(content) != "v2" {
+		t.Errorf("README.md = %q, want v2", content)
+	}
+}
+
+func TestSyncRepository_SkipsDirty(t *testing.T) {
+	origin, originDir, cloneDir := newOriginAndClone(t)
+	commitFile(t, origin, originDir, "README.md", "v2")
+
+	if err := os.WriteFile(filepath.Join(cloneDir, "README.md"), []byte("local"), 0644); err != nil {
+		t.Fatalf("failed to modify clone: %v", err)
+	}
+
+	status, err := syncRepository(GitCloneOptions{URL: originDir, Directory: cloneDir}, io.Discard)
+	if status != CloneStatusSkippedDirty {
+		t.Errorf("status = %v, want %v", status, CloneStatusSkippedDirty)
+	}
+	if err == nil {
+		t.Error("expected error describing the skip")
+	}
+
+	content, _ := os.ReadFile(filepath.Join(cloneDir, "README.md"))
+	if string(content) != "local" {
+		t.Errorf("README.md = %q, local changes should be kept", content)
+	}
+}
+
+func TestSyncRepository_MismatchedRemote(t *testing.T) {
+	_, _, cloneDir := newOriginAndClone(t)
+
+	status, err := syncRepository(GitCloneOptions{URL: "https://example.com/other.git", Directory: cloneDir}, io.Discard)
+	if status != CloneStatusMismatchedRemote {
+		t.Errorf("status = %v, want %v", status, CloneStatusMismatchedRemote)
+	}
+	if err == nil {
+		t.Error("expected error for mismatched remote")
+	}
+}
+
+func TestSyncRepository_NotARepository(t *testing.T) {
+	status, err := syncRepository(GitCloneOptions{URL: "https://example.com/repo.git", Directory: t.TempDir()}, io.Discard)
+	if status != CloneStatusFailed || err == nil {
+		t.Errorf("syncRepository() = %v, %v, want failed with error", status, err)
+	}
+}
+
+func TestSameRemoteURL(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"https://github.com/org/repo.git", "https://github.com/org/repo", true},
+		{"git@github.com:org/repo.git", "git@github.com:org/repo.git", true},
+		{"https://github.com/org/repo/", "https://github.com/org/repo.git", true},
+		{"https://github.com/org/repo.git", "https://github.com/org/other.git", false},
+	}
+
+	for _, tt := range tests {
+		if got := sameRemoteURL(tt.a, tt.b); got != tt.want {
+			t.Errorf("sameRemoteURL(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestPullRepository_SkipsDiverged(t *testing.T) {
+	origin, originDir, cloneDir := newOriginAndClone(t)
+	commitFile(t, origin, originDir, "remote.txt", "remote")
+
+	clone, err := git.PlainOpen(cloneDir)
+	if err != nil {
+		t.Fatalf("failed to open clone: %v", err)
+	}
+	commitFile(t, clone, cloneDir, "local.txt", "local")
+
+	status, err := pullRepository(GitCloneOptions{URL: originDir, Directory: cloneDir}, io.Discard)
+	if status != CloneStatusSkippedDiverged {
+		t.Errorf("status = %v, want %v", status, CloneStatusSkippedDiverged)
+	}
+	if err == nil {
+		t.Error("expected error describing the skip")
+	}
+}
+
+func TestPullRepository_UpToDate(t *testing.T) {
+	_, originDir, cloneDir := newOriginAndClone(t)
+
+	status, err := pullRepository(GitCloneOptions{URL: originDir, Directory: cloneDir}, io.Discard)
+	if err != nil || status != CloneStatusUpToDate {
+		t.Errorf("pullRepository() = %v, %v, want %v", status, err, CloneStatusUpToDate)
+	}
+}
+
+func TestPullRepository_NothingToFastForward(t *testing.T) {
+	tests := []struct {
+		name     string
+		checkout func(*git.Worktree, *git.Repository) error
+	}{
+		{
+			name: "branch without upstream",
+			checkout: func(wt *git.Worktree, _ *git.Repository) error {
+				return wt.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("development/tester"), Create: true})
+			},
+		},
+		{
+			name: "detached HEAD",
+			checkout: func(wt *git.Worktree, repo *git.Repository) error {
+				head, err := repo.Head()
+				if err != nil {
+					return err
+				}
+				return wt.Checkout(&git.CheckoutOptions{Hash: head.Hash()})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			origin, originDir, cloneDir := newOriginAndClone(t)
+			commitFile(t, origin, originDir, "README.md", "v2")
+
+			clone, err := git.PlainOpen(cloneDir)
+			if err != nil {
+				t.Fatalf("failed to open clone: %v", err)
+			}
+			wt, err := clone.Worktree()
+			if err != nil {
+				t.Fatalf("failed to get worktree: %v", err)
+			}
+			if err := tt.checkout(wt, clone); err != nil {
+				t.Fatalf("failed to check out: %v", err)
+			}
+
+			status, err := pullRepository(GitCloneOptions{URL: originDir, Directory: cloneDir}, io.Discard)
+			if err != nil || status != CloneStatusUpToDate {
+				t.Errorf("pullRepository() = %v, %v, want %v", status, err, CloneStatusUpToDate)
+			}
+			if content, _ := os.ReadFile(filepath.Join(cloneDir, "README.md")); string(content) != "v1" {
+				t.Errorf("README.md = %q, want v1 left untouched", content)
+			}
+		})
+	}
+}
+
+func TestPullRepository_NotCloned(t *testing.T) {
+	status, err := pullRepository(GitCloneOptions{URL: "https://example.com/repo.git", Directory: filepath.Join(t.TempDir(), "missing")}, io.Discard)
+	if status != CloneStatusNotCloned || err == nil {
+		t.Errorf("pullRepository() = %v, %v, want not-cloned with error", status, err)
+	}
+}
+
+func TestFetchRepository_LeavesWorktree(t *testing.T) {
+	origin, originDir, cloneDir := newOriginAndClone(t)
+	commitFile(t, origin, originDir, "README.md", "v2")
+
+	status, err := fetchRepository(GitCloneOptions{URL: originDir, Directory: cloneDir}, io.Discard)
+	if err != nil || status != CloneStatusFetched {
+		t.Fatalf("fetchRepository() = %v, %v, want %v", status, err, CloneStatusFetched)
+	}
+
+	content, _ := os.ReadFile(filepath.Join(cloneDir, "README.md"))
+	if string(content) != "v1" {
+		t.Errorf("README.md = %q, fetch must not touch the worktree", content)
+	}
+}
@@ -0,0 +1,196 @@
+package install
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/fabianoflorentino/whiterose/internal/interfaces"
+	"github.com/fabianoflorentino/whiterose/utils"
+)
+
+// download installs the executable of a release archive downloaded over HTTP.
+type download struct {
+	client *http.Client
+	binDir string
+}
+
+// Download installs the executable of the release archive configured for the platform into
+// binDir, after checking the SHA-256 checksum of the archive.
+func Download(client *http.Client, binDir string) Driver {
+	return download{client: client, binDir: binDir}
+}
+
+func (d download) Name() string {
+	return "tarball"
+}
+
+// Available is always true: the download needs no other tool.
+func (d download) Available(exec interfaces.Executor, p Platform) bool {
+	return true
+}
+
+func (d download) Plan(app utils.AppInfo, p Platform) (Step, bool) {
+	if app.Install == nil {
+		return Step{}, false
+	}
+	tarball, ok := app.Install.Tarball[p.OS+"/"+p.Arch]
+	if !ok || tarball.URL == "" {
+		return Step{}, false
+	}
+
+	binary := tarball.Binary
+	if binary == "" {
+		binary = app.Command
+	}
+	dest := filepath.Join(d.binDir, path.Base(app.Command))
+
+	return newStep(app.Name, d.Name(), action{
+		description: fmt.Sprintf("download %s, verify sha256 %s and install %s to %s", tarball.URL, tarball.SHA256, binary, dest),
+		run: func() error {
+			return d.install(tarball.URL, tarball.SHA256, binary, dest)
+		},
+	}), true
+}
+
+// install downloads url, checks its checksum and writes the executable binary of the archive to dest.
+func (d download) install(url, checksum, binary, dest string) error {
+	data, err := d.fetch(url)
+	if err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); !strings.EqualFold(got, strings.TrimSpace(checksum)) {
+		return fmt.Errorf("checksum mismatch for %s: got %s, want %s", url, got, checksum)
+	}
+
+	exe, err := extract(url, data, binary)
+	if err != nil {
+		return err
+	}
+
+	return writeExecutable(dest, exe)
+}
+
+func (d download) fetch(url string) ([]byte, error) {
+	resp, err := d.client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: %s", url, resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	return data, nil
+}
+
+// extract returns the content of binary in the archive at url, or the download itself when it
+// is not a .tar.gz, .tgz or .zip archive.
+func extract(url string, data []byte, binary string) ([]byte, error) {
+	switch {
+	case strings.HasSuffix(url, ".tar.gz"), strings.HasSuffix(url, ".tgz"):
+		return extractTarGz(data, binary)
+	case strings.HasSuffix(url, ".zip"):
+		return extractZip(data, binary)
+	default:
+		return data, nil
+	}
+}
+
+func extractTarGz(data []byte, binary string) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+	tr := tar.NewReader(gz)
+
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%s not found in archive", binary)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+		if hdr.Typeflag == tar.TypeReg && matchesBinary(hdr.Name, binary) {
+			return io.ReadAll(tr)
+		}
+	}
+}
+
+func extractZip(data []byte, binary string) ([]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || !matchesBinary(f.Name, binary) {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+		defer func() { _ = rc.Close() }()
+		return io.ReadAll(rc)
+	}
+	return nil, fmt.Errorf("%s not found in archive", binary)
+}
+
+// matchesBinary reports whether the archive entry name is binary: the same path, or the same
+// file name when binary has no directory.
+func matchesBinary(name, binary string) bool {
+	name = strings.TrimPrefix(path.Clean(name), "./")
+	if name == path.Clean(binary) {
+		return true
+	}
+	return !strings.Contains(binary, "/") && path.Base(name) == binary
+}
+
+// writeExecutable writes data to dest through a temporary file, so that a failed write never
+// leaves a truncated executable behind.
+func writeExecutable(dest string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(dest), err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dest), "."+filepath.Base(dest)+"-*")
+	if err != nil {
+		return fmt.Errorf("failed to install %s: %w", dest, err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to install %s: %w", dest, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to install %s: %w", dest, err)
+	}
+	if err := os.Chmod(tmp.Name(), 0755); err != nil {
+		return fmt.Errorf("failed to install %s: %w", dest, err)
+	}
+	if err := os.Rename(tmp.Name(), dest); err != nil {
+		return fmt.Errorf("failed to install %s: %w", dest, err)
+	}
+	return nil
+}
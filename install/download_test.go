@@ -0,0 +1,158 @@
+package install
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fabianoflorentino/whiterose/utils"
+)
+
+func tarGz(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func zipArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestDownload(t *testing.T) {
+	archives := map[string][]byte{
+		"/tool.tar.gz": tarGz(t, map[string]string{"tool-1.0/README": "readme", "tool-1.0/bin/tool": "#!/bin/sh\necho tar\n"}),
+		"/tool.zip":    zipArchive(t, map[string]string{"tool": "zip binary"}),
+		"/tool":        []byte("bare binary"),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, ok := archives[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		path    string
+		sum     string
+		binary  string
+		want    string
+		wantErr string
+	}{
+		{"tar.gz with path", "/tool.tar.gz", checksum(archives["/tool.tar.gz"]), "tool-1.0/bin/tool", "#!/bin/sh\necho tar\n", ""},
+		{"zip by name", "/tool.zip", checksum(archives["/tool.zip"]), "", "zip binary", ""},
+		{"bare executable", "/tool", strings.ToUpper(checksum(archives["/tool"])), "", "bare binary", ""},
+		{"checksum mismatch", "/tool", checksum([]byte("other")), "", "", "checksum mismatch"},
+		{"binary not in archive", "/tool.tar.gz", checksum(archives["/tool.tar.gz"]), "bin/other", "", "bin/other not found in archive"},
+		{"not found", "/missing.tar.gz", "", "", "", "404 Not Found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			binDir := filepath.Join(t.TempDir(), "bin")
+			app := utils.AppInfo{Name: "Tool", Command: "tool", Install: &utils.AppInstall{
+				Tarball: map[string]utils.Tarball{"linux/amd64": {URL: server.URL + tt.path, SHA256: tt.sum, Binary: tt.binary}},
+			}}
+
+			step, ok := Download(server.Client(), binDir).Plan(app, Platform{OS: "linux", Arch: "amd64"})
+			if !ok {
+				t.Fatal("Plan() found no tarball for linux/amd64")
+			}
+			err := (&Engine{}).run(step, map[string]bool{}, &bytes.Buffer{})
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("run() error = %v, want %q", err, tt.wantErr)
+				}
+				if _, err := os.Stat(filepath.Join(binDir, "tool")); !os.IsNotExist(err) {
+					t.Error("a failed download left an executable behind")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("run() error = %v", err)
+			}
+
+			dest := filepath.Join(binDir, "tool")
+			data, err := os.ReadFile(dest)
+			if err != nil || string(data) != tt.want {
+				t.Fatalf("installed %q, %v, want %q", data, err, tt.want)
+			}
+			if info, _ := os.Stat(dest); info.Mode().Perm() != 0755 {
+				t.Errorf("mode = %v, want 0755", info.Mode().Perm())
+			}
+		})
+	}
+}
+
+func TestDefaultDrivers_DownloadTimeout(t *testing.T) {
+	drivers := DefaultDrivers()
+	d, ok := drivers[len(drivers)-1].(download)
+	if !ok || d.client.Timeout != DownloadTimeout {
+		t.Errorf("DefaultDrivers() download = %+v, want a client with a %v timeout", drivers[len(drivers)-1], DownloadTimeout)
+	}
+}
+
+func TestDownload_Plan(t *testing.T) {
+	d := Download(http.DefaultClient, "/opt/bin")
+	app := utils.AppInfo{Name: "Tool", Command: "tool", Install: &utils.AppInstall{
+		Tarball: map[string]utils.Tarball{"darwin/arm64": {URL: "https://example.com/tool.tgz", SHA256: "abc"}},
+	}}
+
+	if _, ok := d.Plan(app, Platform{OS: "linux", Arch: "amd64"}); ok {
+		t.Error("Plan() used a tarball of another platform")
+	}
+
+	step, ok := d.Plan(app, Platform{OS: "darwin", Arch: "arm64"})
+	if !ok || len(step.Commands) != 1 || step.Commands[0] != "download https://example.com/tool.tgz, verify sha256 abc and install tool to /opt/bin/tool" {
+		t.Errorf("Plan() = %+v, %v", step, ok)
+	}
+}
@@ -0,0 +1,181 @@
+package install
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/fabianoflorentino/whiterose/internal/interfaces"
+	"github.com/fabianoflorentino/whiterose/utils"
+)
+
+// DownloadTimeout bounds the download of a release archive, so that a stalled server fails the
+// installation instead of blocking it.
+const DownloadTimeout = 5 * time.Minute
+
+// DefaultDrivers returns the drivers in order of preference: the version managers the user chose
+// to install, then Homebrew and the system package managers, then the release archive download.
+func DefaultDrivers() []Driver {
+	return []Driver{
+		Mise(),
+		Asdf(),
+		Brew(),
+		Apt(),
+		Dnf(),
+		Pacman(),
+		Download(&http.Client{Timeout: DownloadTimeout}, DefaultBinDir()),
+	}
+}
+
+// packageManager is a driver installing a package with a single command.
+type packageManager struct {
+	name   string
+	binary string
+	// oses limits the driver to these operating systems; any when empty.
+	oses []string
+	// sudo runs the command with sudo unless whiterose runs as root.
+	sudo bool
+	pkg  func(utils.AppInstall) string
+	// command returns the command installing pkg at version, for the drivers that pin versions.
+	command func(pkg, version string) []string
+	// refresh is the command updating the package lists, run once before the first install.
+	refresh []string
+}
+
+func (m packageManager) Name() string {
+	return m.name
+}
+
+func (m packageManager) Available(exec interfaces.Executor, p Platform) bool {
+	if len(m.oses) > 0 && !slices.Contains(m.oses, p.OS) {
+		return false
+	}
+	_, err := exec.Which(m.binary)
+	return err == nil
+}
+
+func (m packageManager) Plan(app utils.AppInfo, p Platform) (Step, bool) {
+	if app.Install == nil || m.pkg(*app.Install) == "" {
+		return Step{}, false
+	}
+
+	var actions []action
+	if m.refresh != nil {
+		refresh := m.privileged(m.refresh, p)
+		update := command(refresh[0], refresh[1:]...)
+		update.once = true
+		actions = append(actions, update)
+	}
+	cmd := m.privileged(m.command(m.pkg(*app.Install), version(app.Install)), p)
+	actions = append(actions, command(cmd[0], cmd[1:]...))
+	return newStep(app.Name, m.name, actions...), true
+}
+
+// privileged prefixes cmd with sudo when the driver needs root and whiterose is not running as root.
+func (m packageManager) privileged(cmd []string, p Platform) []string {
+	if m.sudo && !p.Root {
+		return append([]string{"sudo"}, cmd...)
+	}
+	return cmd
+}
+
+// Apt installs the apt package of an application on Debian and Ubuntu, after updating the
+// package lists, which fresh containers and CI images ship empty.
+func Apt() Driver {
+	return packageManager{
+		name: "apt", binary: "apt-get", oses: []string{"linux"}, sudo: true,
+		pkg:     func(i utils.AppInstall) string { return i.Apt },
+		command: func(pkg, _ string) []string { return []string{"apt-get", "install", "-y", pkg} },
+		refresh: []string{"apt-get", "update"},
+	}
+}
+
+// Dnf installs the dnf package of an application on Fedora and RHEL.
+func Dnf() Driver {
+	return packageManager{
+		name: "dnf", binary: "dnf", oses: []string{"linux"}, sudo: true,
+		pkg:     func(i utils.AppInstall) string { return i.Dnf },
+		command: func(pkg, _ string) []string { return []string{"dnf", "install", "-y", pkg} },
+	}
+}
+
+// Pacman installs the pacman package of an application on Arch Linux.
+func Pacman() Driver {
+	return packageManager{
+		name: "pacman", binary: "pacman", oses: []string{"linux"}, sudo: true,
+		pkg:     func(i utils.AppInstall) string { return i.Pacman },
+		command: func(pkg, _ string) []string { return []string{"pacman", "-S", "--noconfirm", "--needed", pkg} },
+	}
+}
+
+// Brew installs the Homebrew formula of an application on macOS and Linux.
+func Brew() Driver {
+	return packageManager{
+		name: "brew", binary: "brew", oses: []string{"darwin", "linux"},
+		pkg:     func(i utils.AppInstall) string { return i.Brew },
+		command: func(pkg, _ string) []string { return []string{"brew", "install", pkg} },
+	}
+}
+
+// Mise installs the mise tool of an application and makes it the global version.
+func Mise() Driver {
+	return packageManager{
+		name: "mise", binary: "mise",
+		pkg:     func(i utils.AppInstall) string { return i.Mise },
+		command: func(pkg, v string) []string { return []string{"mise", "use", "--global", pkg + "@" + v} },
+	}
+}
+
+// asdf installs an application with its asdf plugin, which takes three commands.
+type asdf struct{}
+
+// Asdf installs an application with its asdf plugin and makes it the version of the home directory.
+func Asdf() Driver {
+	return asdf{}
+}
+
+func (asdf) Name() string {
+	return "asdf"
+}
+
+func (asdf) Available(exec interfaces.Executor, p Platform) bool {
+	_, err := exec.Which("asdf")
+	return err == nil
+}
+
+func (asdf) Plan(app utils.AppInfo, p Platform) (Step, bool) {
+	if app.Install == nil || app.Install.Asdf == "" {
+		return Step{}, false
+	}
+
+	plugin, v := app.Install.Asdf, version(app.Install)
+	addPlugin := command("asdf", "plugin", "add", plugin)
+	// Adding a plugin that is already there fails.
+	addPlugin.optional = true
+
+	return newStep(app.Name, "asdf",
+		addPlugin,
+		command("asdf", "install", plugin, v),
+		// asdf set only exists from asdf 0.16, which dropped asdf global.
+		command("asdf", "set", "--home", plugin, v).orElse("asdf", "global", plugin, v),
+	), true
+}
+
+// version returns the version installed by the version managers.
+func version(i *utils.AppInstall) string {
+	if i == nil || i.Version == "" {
+		return "latest"
+	}
+	return i.Version
+}
+
+// DefaultBinDir returns the directory downloaded executables are installed to, ~/.local/bin.
+func DefaultBinDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".local", "bin")
+	}
+	return filepath.Join(home, ".local", "bin")
+}
@@ -0,0 +1,305 @@
+// Package install installs missing prerequisites with the package managers of the machine: apt,
+// dnf, pacman, Homebrew, asdf and mise, or a direct download of a release archive verified
+// against its SHA-256 checksum.
+//
+// Installation happens in two phases. Engine.Plan picks, for every application, the first
+// driver that has a package for it and is available on the machine, and lists the commands it
+// will run; Engine.Execute then runs the plan, usually after the user confirmed it. Commands run
+// through an interfaces.Executor so that drivers can be tested with fakes.
+//
+// How an application is installed is configured in the install key of its entry:
+//
+//	applications:
+//	  - name: yq
+//	    command: yq
+//	    install:
+//	      version: 4.44.3
+//	      apt: yq
+//	      brew: yq
+//	      mise: yq
+//	      tarball:
+//	        linux/amd64:
+//	          url: https://github.com/mikefarah/yq/releases/download/v4.44.3/yq_linux_amd64.tar.gz
+//	          sha256: <checksum published with the release>
+//	          binary: yq_linux_amd64
+//
+// The tarball driver installs a single executable, so it suits tools shipped as one binary
+// rather than toolchains such as Go that need the rest of their archive.
+package install
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/fabianoflorentino/whiterose/internal/interfaces"
+	"github.com/fabianoflorentino/whiterose/internal/services"
+	"github.com/fabianoflorentino/whiterose/output"
+	"github.com/fabianoflorentino/whiterose/utils"
+)
+
+// Platform is the machine applications are installed on.
+type Platform struct {
+	OS   string
+	Arch string
+	// Root is true when running as root, so system package managers need no sudo.
+	Root bool
+}
+
+// CurrentPlatform returns the platform whiterose is running on.
+func CurrentPlatform() Platform {
+	return Platform{OS: runtime.GOOS, Arch: runtime.GOARCH, Root: os.Geteuid() == 0}
+}
+
+// Driver installs applications with one package manager or installation method.
+type Driver interface {
+	// Name identifies the driver in plans and reports, e.g. "apt".
+	Name() string
+	// Available reports whether the driver can be used on the machine, e.g. its package manager is installed.
+	Available(exec interfaces.Executor, p Platform) bool
+	// Plan describes how to install app, or returns false when app has no package for the driver.
+	Plan(app utils.AppInfo, p Platform) (Step, bool)
+}
+
+// Step installs one application with one driver.
+type Step struct {
+	App    string `json:"app"`
+	Driver string `json:"driver"`
+	// Commands describe the actions of the step, in the order they run.
+	Commands []string `json:"commands"`
+	actions  []action
+}
+
+// action is a command run through the executor, or a function for what no command does.
+type action struct {
+	description string
+	command     []string
+	run         func() error
+	// optional actions may fail without failing the step, e.g. adding an asdf plugin that exists.
+	optional bool
+	// once actions run at most once per Execute, e.g. refreshing the apt package lists.
+	once bool
+	// fallback runs instead when command fails, e.g. asdf global on asdf older than 0.16.
+	fallback []string
+}
+
+// command returns the action running name with args.
+func command(name string, args ...string) action {
+	cmd := append([]string{name}, args...)
+	return action{description: strings.Join(cmd, " "), command: cmd}
+}
+
+// orElse returns a, running name with args when its command fails.
+func (a action) orElse(name string, args ...string) action {
+	a.fallback = append([]string{name}, args...)
+	a.description += " || " + strings.Join(a.fallback, " ")
+	return a
+}
+
+// newStep builds the step installing app with driver.
+func newStep(app, driver string, actions ...action) Step {
+	step := Step{App: app, Driver: driver, actions: actions}
+	for _, a := range actions {
+		step.Commands = append(step.Commands, a.description)
+	}
+	return step
+}
+
+// Plan is the list of steps that install a set of applications.
+type Plan struct {
+	Steps []Step `json:"steps"`
+	// Unavailable are the applications that no available driver can install.
+	Unavailable []string `json:"unavailable,omitempty"`
+}
+
+// Empty reports whether the plan neither installs nor reports anything.
+func (p Plan) Empty() bool {
+	return len(p.Steps) == 0 && len(p.Unavailable) == 0
+}
+
+// Text writes the steps and the commands they run.
+func (p Plan) Text(w io.Writer) {
+	if len(p.Steps) > 0 {
+		fmt.Fprintln(w, "📋 Installation plan:")
+		for _, s := range p.Steps {
+			fmt.Fprintf(w, "  %s (%s)\n", s.App, s.Driver)
+			for _, c := range s.Commands {
+				fmt.Fprintf(w, "    $ %s\n", c)
+			}
+		}
+	}
+	for _, app := range p.Unavailable {
+		fmt.Fprintf(w, "⚠️  No available installer for %s, install it manually\n", app)
+	}
+}
+
+// Table lays the plan out one command per row.
+func (p Plan) Table() output.Table {
+	t := output.Table{Header: []string{"APP", "DRIVER", "COMMAND"}}
+	for _, s := range p.Steps {
+		for _, c := range s.Commands {
+			t.Rows = append(t.Rows, []string{s.App, s.Driver, c})
+		}
+	}
+	for _, app := range p.Unavailable {
+		t.Rows = append(t.Rows, []string{app, "-", "install manually"})
+	}
+	return t
+}
+
+// Result is the outcome of one step.
+type Result struct {
+	App    string `json:"app"`
+	Driver string `json:"driver"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Report holds the results of the executed steps.
+type Report []Result
+
+// Text writes one line per application.
+func (r Report) Text(w io.Writer) {
+	for _, res := range r {
+		if res.Error != "" {
+			fmt.Fprintf(w, "❌ %s (%s): %s\n", res.App, res.Driver, res.Error)
+			continue
+		}
+		fmt.Fprintf(w, "✅ %s installed with %s\n", res.App, res.Driver)
+	}
+}
+
+// Table lays the report out one application per row.
+func (r Report) Table() output.Table {
+	t := output.Table{Header: []string{"APP", "DRIVER", "STATUS", "ERROR"}}
+	for _, res := range r {
+		status := "installed"
+		if res.Error != "" {
+			status = "failed"
+		}
+		t.Rows = append(t.Rows, []string{res.App, res.Driver, status, dash(res.Error)})
+	}
+	return t
+}
+
+// Engine plans and runs the installation of applications.
+type Engine struct {
+	executor interfaces.Executor
+	drivers  []Driver
+	platform Platform
+}
+
+// NewEngine creates an Engine running commands on the current platform with DefaultDrivers.
+func NewEngine() *Engine {
+	return &Engine{
+		executor: services.NewExecutorService(),
+		drivers:  DefaultDrivers(),
+		platform: CurrentPlatform(),
+	}
+}
+
+// WithExecutor replaces the executor used to look up and run commands.
+func (e *Engine) WithExecutor(exec interfaces.Executor) *Engine {
+	e.executor = exec
+	return e
+}
+
+// WithDrivers replaces the drivers, in order of preference.
+func (e *Engine) WithDrivers(drivers ...Driver) *Engine {
+	e.drivers = drivers
+	return e
+}
+
+// WithPlatform sets the platform applications are installed for.
+func (e *Engine) WithPlatform(p Platform) *Engine {
+	e.platform = p
+	return e
+}
+
+// Plan picks, for every application, the first driver with a package for it that is available
+// on the machine. Applications without one are listed in Plan.Unavailable.
+func (e *Engine) Plan(apps []utils.AppInfo) Plan {
+	plan := Plan{Steps: []Step{}}
+	for _, app := range apps {
+		step, ok := e.planApp(app)
+		if !ok {
+			plan.Unavailable = append(plan.Unavailable, app.Name)
+			continue
+		}
+		plan.Steps = append(plan.Steps, step)
+	}
+	return plan
+}
+
+func (e *Engine) planApp(app utils.AppInfo) (Step, bool) {
+	for _, d := range e.drivers {
+		step, ok := d.Plan(app, e.platform)
+		if ok && d.Available(e.executor, e.platform) {
+			return step, true
+		}
+	}
+	return Step{}, false
+}
+
+// Execute runs the steps of plan, printing each command to out. A failed step stops at its
+// failing action and the next step still runs.
+func (e *Engine) Execute(plan Plan, out io.Writer) Report {
+	report := make(Report, 0, len(plan.Steps))
+	// done holds the once actions that already succeeded.
+	done := map[string]bool{}
+	for _, step := range plan.Steps {
+		fmt.Fprintf(out, "📥 Installing %s with %s\n", step.App, step.Driver)
+		result := Result{App: step.App, Driver: step.Driver}
+		if err := e.run(step, done, out); err != nil {
+			result.Error = err.Error()
+		}
+		report = append(report, result)
+	}
+	return report
+}
+
+func (e *Engine) run(step Step, done map[string]bool, out io.Writer) error {
+	for _, a := range step.actions {
+		if a.once && done[a.description] {
+			continue
+		}
+		fmt.Fprintf(out, "   $ %s\n", a.description)
+
+		var err error
+		if a.run != nil {
+			err = a.run()
+		} else {
+			err = e.command(a.command)
+			if err != nil && a.fallback != nil {
+				err = e.command(a.fallback)
+			}
+		}
+
+		if err != nil && !a.optional {
+			return err
+		}
+		if err == nil && a.once {
+			done[a.description] = true
+		}
+	}
+	return nil
+}
+
+// command runs cmd through the executor.
+func (e *Engine) command(cmd []string) error {
+	output, err := e.executor.Run(cmd[0], cmd[1:]...)
+	// The output of a failed command tells why, such as a package missing from the index.
+	if err != nil && output != "" {
+		err = fmt.Errorf("%w: %s", err, output)
+	}
+	return err
+}
+
+// dash returns s, or "-" when it is empty.
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
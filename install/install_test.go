@@ -0,0 +1,206 @@
+package install
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/fabianoflorentino/whiterose/mocks"
+	"github.com/fabianoflorentino/whiterose/utils"
+)
+
+// fakeManagers returns an executor on which only the given binaries are installed, recording the
+// commands it runs.
+func fakeManagers(installed ...string) (*mocks.MockExecutor, *[]string) {
+	var ran []string
+	return &mocks.MockExecutor{
+		WhichFunc: func(cmd string) (string, error) {
+			for _, b := range installed {
+				if b == cmd {
+					return "/usr/bin/" + cmd, nil
+				}
+			}
+			return "", errors.New("command not found: " + cmd)
+		},
+		RunFunc: func(cmd string, args ...string) (string, error) {
+			ran = append(ran, strings.Join(append([]string{cmd}, args...), " "))
+			return "", nil
+		},
+	}, &ran
+}
+
+func TestEngine_Plan(t *testing.T) {
+	goApp := utils.AppInfo{Name: "Go", Command: "go", Install: &utils.AppInstall{
+		Version: "1.25.3", Apt: "golang-go", Brew: "go", Mise: "go", Asdf: "golang",
+	}}
+	jqApp := utils.AppInfo{Name: "jq", Command: "jq", Install: &utils.AppInstall{Pacman: "jq", Dnf: "jq"}}
+	manual := utils.AppInfo{Name: "Manual", Command: "manual"}
+
+	tests := []struct {
+		name      string
+		platform  Platform
+		installed []string
+		want      []string
+		missing   []string
+	}{
+		{
+			name:      "version manager first",
+			platform:  Platform{OS: "linux"},
+			installed: []string{"apt-get", "mise", "dnf"},
+			want:      []string{"Go/mise: mise use --global go@1.25.3", "jq/dnf: sudo dnf install -y jq"},
+			missing:   []string{"Manual"},
+		},
+		{
+			name:      "system package manager as root",
+			platform:  Platform{OS: "linux", Root: true},
+			installed: []string{"apt-get", "pacman"},
+			want:      []string{"Go/apt: apt-get update | apt-get install -y golang-go", "jq/pacman: pacman -S --noconfirm --needed jq"},
+			missing:   []string{"Manual"},
+		},
+		{
+			name:      "linux package managers are not used on macOS",
+			platform:  Platform{OS: "darwin"},
+			installed: []string{"brew", "apt-get", "dnf"},
+			want:      []string{"Go/brew: brew install go"},
+			missing:   []string{"jq", "Manual"},
+		},
+		{
+			name:      "asdf",
+			platform:  Platform{OS: "linux"},
+			installed: []string{"asdf"},
+			want:      []string{"Go/asdf: asdf plugin add golang | asdf install golang 1.25.3 | asdf set --home golang 1.25.3 || asdf global golang 1.25.3"},
+			missing:   []string{"jq", "Manual"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exec, _ := fakeManagers(tt.installed...)
+			plan := NewEngine().
+				WithExecutor(exec).
+				WithDrivers(Mise(), Asdf(), Brew(), Apt(), Dnf(), Pacman()).
+				WithPlatform(tt.platform).
+				Plan([]utils.AppInfo{goApp, jqApp, manual})
+
+			var got []string
+			for _, s := range plan.Steps {
+				got = append(got, s.App+"/"+s.Driver+": "+strings.Join(s.Commands, " | "))
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("Plan().Steps =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+			if strings.Join(plan.Unavailable, ",") != strings.Join(tt.missing, ",") {
+				t.Errorf("Plan().Unavailable = %v, want %v", plan.Unavailable, tt.missing)
+			}
+		})
+	}
+}
+
+func TestEngine_Execute(t *testing.T) {
+	exec, ran := fakeManagers("asdf", "brew")
+	exec.RunFunc = func(cmd string, args ...string) (string, error) {
+		line := strings.Join(append([]string{cmd}, args...), " ")
+		*ran = append(*ran, line)
+		switch line {
+		case "asdf plugin add nodejs":
+			return "", errors.New("plugin already added")
+		case "brew install jq":
+			return "Error: No available formula with the name \"jq\".", errors.New("exit status 1")
+		}
+		return "", nil
+	}
+
+	engine := NewEngine().WithExecutor(exec).WithDrivers(Asdf(), Brew()).WithPlatform(Platform{OS: "darwin"})
+	plan := engine.Plan([]utils.AppInfo{
+		{Name: "Node.js", Command: "node", Install: &utils.AppInstall{Asdf: "nodejs"}},
+		{Name: "jq", Command: "jq", Install: &utils.AppInstall{Brew: "jq"}},
+		{Name: "yq", Command: "yq", Install: &utils.AppInstall{Brew: "yq"}},
+	})
+
+	var out strings.Builder
+	report := engine.Execute(plan, &out)
+
+	wantRan := []string{
+		"asdf plugin add nodejs",
+		"asdf install nodejs latest",
+		"asdf set --home nodejs latest",
+		"brew install jq",
+		"brew install yq",
+	}
+	if strings.Join(*ran, "\n") != strings.Join(wantRan, "\n") {
+		t.Errorf("ran =\n%s\nwant\n%s", strings.Join(*ran, "\n"), strings.Join(wantRan, "\n"))
+	}
+
+	if len(report) != 3 || report[0].Error != "" || report[1].Error != `exit status 1: Error: No available formula with the name "jq".` || report[2].Error != "" {
+		t.Fatalf("Execute() = %+v", report)
+	}
+
+	var text strings.Builder
+	report.Text(&text)
+	for _, want := range []string{"✅ Node.js installed with asdf", `❌ jq (brew): exit status 1: Error: No available formula with the name "jq".`, "✅ yq installed with brew"} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("Report.Text() missing %q:\n%s", want, text.String())
+		}
+	}
+	if !strings.Contains(out.String(), "📥 Installing jq with brew\n   $ brew install jq\n") {
+		t.Errorf("Execute() output =\n%s", out.String())
+	}
+}
+
+func TestEngine_Execute_RefreshAndFallback(t *testing.T) {
+	exec, ran := fakeManagers("apt-get", "asdf")
+	exec.RunFunc = func(cmd string, args ...string) (string, error) {
+		line := strings.Join(append([]string{cmd}, args...), " ")
+		*ran = append(*ran, line)
+		if line == "asdf set --home nodejs 20" {
+			return "Unknown command: `asdf set --home nodejs 20`", errors.New("exit status 1")
+		}
+		return "", nil
+	}
+
+	engine := NewEngine().WithExecutor(exec).WithDrivers(Asdf(), Apt()).WithPlatform(Platform{OS: "linux", Root: true})
+	plan := engine.Plan([]utils.AppInfo{
+		{Name: "jq", Command: "jq", Install: &utils.AppInstall{Apt: "jq"}},
+		{Name: "yq", Command: "yq", Install: &utils.AppInstall{Apt: "yq"}},
+		{Name: "Node.js", Command: "node", Install: &utils.AppInstall{Asdf: "nodejs", Version: "20"}},
+	})
+	report := engine.Execute(plan, io.Discard)
+
+	wantRan := []string{
+		"apt-get update",
+		"apt-get install -y jq",
+		"apt-get install -y yq",
+		"asdf plugin add nodejs",
+		"asdf install nodejs 20",
+		"asdf set --home nodejs 20",
+		"asdf global nodejs 20",
+	}
+	if strings.Join(*ran, "\n") != strings.Join(wantRan, "\n") {
+		t.Errorf("ran =\n%s\nwant\n%s", strings.Join(*ran, "\n"), strings.Join(wantRan, "\n"))
+	}
+	for _, res := range report {
+		if res.Error != "" {
+			t.Errorf("Execute() %s error = %s", res.App, res.Error)
+		}
+	}
+}
+
+func TestPlan_Text(t *testing.T) {
+	plan := Plan{
+		Steps:       []Step{newStep("Go", "brew", command("brew", "install", "go"))},
+		Unavailable: []string{"Manual"},
+	}
+
+	var out strings.Builder
+	plan.Text(&out)
+	want := "📋 Installation plan:\n  Go (brew)\n    $ brew install go\n⚠️  No available installer for Manual, install it manually\n"
+	if out.String() != want {
+		t.Errorf("Text() =\n%s\nwant\n%s", out.String(), want)
+	}
+
+	rows := plan.Table().Rows
+	if len(rows) != 2 || rows[0][2] != "brew install go" || rows[1][1] != "-" {
+		t.Errorf("Table().Rows = %v", rows)
+	}
+}
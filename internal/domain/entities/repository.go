@@ -139,6 +139,22 @@ func (a *AuthenticationMethod) Validate() error {
 	return nil
 }
 
+// ValidateURL checks that rawURL is a repository URL supported by whiterose (SSH or HTTPS).
+func ValidateURL(rawURL string) error {
+	return validateURL(rawURL)
+}
+
+// IsSSHURL reports whether rawURL is an SSH repository URL, in scp-like (git@host:path) or
+// ssh:// form.
+func IsSSHURL(rawURL string) bool {
+	return strings.HasPrefix(rawURL, "git@") || strings.HasPrefix(rawURL, "ssh://")
+}
+
+// IsHTTPSURL reports whether rawURL is an HTTPS repository URL.
+func IsHTTPSURL(rawURL string) bool {
+	return strings.HasPrefix(rawURL, "https://")
+}
+
 // private helper functions
 
 // validateURL checks if the repository URL is valid and supported (SSH or HTTPS).
@@ -151,7 +167,7 @@ func validateURL(rawURL string) error {
 		return nil
 	}
 
-	if strings.HasPrefix(rawURL, "https://") {
+	if IsSSHURL(rawURL) || IsHTTPSURL(rawURL) {
 		_, err := url.Parse(rawURL)
 		return err
 	}
@@ -171,7 +187,7 @@ func extractRepositoryName(rawURL string) string {
 				return pathParts[len(pathParts)-1]
 			}
 		}
-	} else if strings.HasPrefix(rawURL, "https://") {
+	} else if IsSSHURL(rawURL) || IsHTTPSURL(rawURL) {
 		u, err := url.Parse(rawURL)
 		if err == nil {
 			path := strings.TrimPrefix(u.Path, "/")
@@ -188,7 +204,7 @@ func extractRepositoryName(rawURL string) string {
 
 // determineAuthMethod determines the appropriate authentication method based on the repository URL.
 func determineAuthMethod(rawURL string) AuthenticationMethod {
-	if strings.HasPrefix(rawURL, "git@") {
+	if IsSSHURL(rawURL) {
 		return AuthenticationMethod{
 			Type: AuthTypeSSH,
 			SSHKey: SSHKeyConfig{
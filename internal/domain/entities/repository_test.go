@@ -191,6 +191,7 @@ func TestExtractRepositoryName(t *testing.T) {
 	}{
 		{"https", "https://github.com/fabianoflorentino/repo.git", "repo"},
 		{"ssh", "git@github.com:fabianoflorentino/repo.git", "repo"},
+		{"ssh url", "ssh://git@github.com/fabianoflorentino/repo.git", "repo"},
 		{"no git suffix", "https://github.com/org/repo", "repo"},
 		{"invalid", "invalid", "unknown"},
 	}
@@ -212,6 +213,7 @@ func TestDetermineAuthMethod(t *testing.T) {
 		want AuthType
 	}{
 		{"ssh", "git@github.com:org/repo.git", AuthTypeSSH},
+		{"ssh url", "ssh://git@github.com/org/repo.git", AuthTypeSSH},
 		{"https", "https://github.com/org/repo.git", AuthTypeHTTPS},
 	}
 
@@ -224,3 +226,23 @@ func TestDetermineAuthMethod(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr bool
+	}{
+		{"https://github.com/fabianoflorentino/repo.git", false},
+		{"git@github.com:fabianoflorentino/repo.git", false},
+		{"ssh://git@github.com/fabianoflorentino/repo.git", false},
+		{"ssh://git@git.internal:2222/team/repo.git", false},
+		{"", true},
+		{"ftp://example.com/repo.git", true},
+	}
+
+	for _, tt := range tests {
+		if err := ValidateURL(tt.url); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateURL(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+		}
+	}
+}
@@ -17,22 +17,22 @@ const (
 )
 
 type GoModConfig struct {
-	UpdateStrategy UpdateStrategy `json:"updateStrategy" yaml:"updateStrategy"`
+	UpdateStrategy UpdateStrategy `json:"updateStrategy" yaml:"updateStrategy" jsonschema:"enum=patch|minor|major"`
 }
 
 type DockerImageConfig struct {
 	Base           string         `json:"base" yaml:"base"`
-	UpdateStrategy UpdateStrategy `json:"updateStrategy" yaml:"updateStrategy"`
+	UpdateStrategy UpdateStrategy `json:"updateStrategy" yaml:"updateStrategy" jsonschema:"enum=patch|minor|major"`
 }
 
 type GoVersionConfig struct {
 	Version        string         `json:"version" yaml:"version"`
-	UpdateStrategy UpdateStrategy `json:"updateStrategy" yaml:"updateStrategy"`
+	UpdateStrategy UpdateStrategy `json:"updateStrategy" yaml:"updateStrategy" jsonschema:"enum=patch|minor|major"`
 }
 
 type UpdateProject struct {
-	Name        string             `json:"name" yaml:"name"`
-	Path        string             `json:"path" yaml:"path"`
+	Name        string             `json:"name" yaml:"name" jsonschema:"required"`
+	Path        string             `json:"path" yaml:"path" jsonschema:"required"`
 	GoMod       *GoModConfig       `json:"goMod" yaml:"goMod"`
 	GoVersion   *GoVersionConfig   `json:"goVersion" yaml:"goVersion"`
 	DockerImage *DockerImageConfig `json:"dockerImage" yaml:"dockerImage"`
@@ -42,14 +42,19 @@ type AppChecker interface {
 }
 
 type AppValidation struct {
-	Name                string
-	Command             string
-	IsInstalled         bool
-	CurrentVersion      string
-	RecommendedVersion  string
-	IsUpToDate          bool
-	InstallInstruction  string
-	OS                  string
+	Name               string   `json:"name" yaml:"name"`
+	Command            string   `json:"command" yaml:"command"`
+	IsInstalled        bool     `json:"installed" yaml:"installed"`
+	TimedOut           bool     `json:"timedOut,omitempty" yaml:"timedOut,omitempty"`
+	CurrentVersion     string   `json:"currentVersion,omitempty" yaml:"currentVersion,omitempty"`
+	RecommendedVersion string   `json:"recommendedVersion,omitempty" yaml:"recommendedVersion,omitempty"`
+	IsUpToDate         bool     `json:"upToDate" yaml:"upToDate"`
+	VersionError       string   `json:"versionError,omitempty" yaml:"versionError,omitempty"`
+	Health             string   `json:"health,omitempty" yaml:"health,omitempty"`
+	HealthError        string   `json:"healthError,omitempty" yaml:"healthError,omitempty"`
+	BlockedBy          []string `json:"blockedBy,omitempty" yaml:"blockedBy,omitempty"`
+	InstallInstruction string   `json:"installInstruction,omitempty" yaml:"installInstruction,omitempty"`
+	OS                 string   `json:"os" yaml:"os"`
 }
 
 type ImageBuilder interface {
@@ -92,9 +97,9 @@ type VersionLister interface {
 }
 
 type PackageUpdate struct {
-	Name           string
-	CurrentVersion string
-	LatestVersion  string
+	Name           string `json:"name" yaml:"name"`
+	CurrentVersion string `json:"currentVersion" yaml:"currentVersion"`
+	LatestVersion  string `json:"latestVersion" yaml:"latestVersion"`
 }
 
 type DockerImageLister interface {
@@ -124,4 +129,4 @@ func ToRepoInfo(repos []utils.RepoInfo) []RepoInfo {
 		})
 	}
 	return result
-}
\ No newline at end of file
+}
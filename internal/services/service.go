@@ -7,8 +7,10 @@ import (
 	"runtime"
 	"strings"
 
+	"github.com/fabianoflorentino/whiterose/catalog"
+	"github.com/fabianoflorentino/whiterose/config"
 	"github.com/fabianoflorentino/whiterose/internal/interfaces"
-	"github.com/fabianoflorentino/whiterose/utils"
+	"github.com/fabianoflorentino/whiterose/semver"
 )
 
 type Executor interface {
@@ -24,70 +26,43 @@ func NewConfigService(configPath string) *ConfigService {
 	return &ConfigService{configPath: configPath}
 }
 
-func (c *ConfigService) LoadRepositories() ([]interfaces.RepoInfo, error) {
-	if c.configPath == "" {
-		cfg, err := utils.LoadDotConfig()
-		if err != nil {
+// load reads the configuration with config.Load, so that includes, profiles, interpolation and
+// flags apply; configPath, when set, replaces the repositories file of CONFIG_FILE or the home
+// directory.
+func (c *ConfigService) load() (*config.Config, error) {
+	loader := config.NewLoader()
+	if c.configPath != "" {
+		if _, err := os.Stat(c.configPath); err != nil {
 			return nil, fmt.Errorf("config not found: %w", err)
 		}
-		c.configPath = cfg
+		loader = loader.WithLegacyFile(c.configPath)
 	}
+	return loader.Load()
+}
 
-	repos, err := utils.FetchRepositories(c.configPath)
+func (c *ConfigService) LoadRepositories() ([]interfaces.RepoInfo, error) {
+	cfg, err := c.load()
 	if err != nil {
 		return nil, err
 	}
 
-	return interfaces.ToRepoInfo(repos), nil
+	return interfaces.ToRepoInfo(cfg.Repositories), nil
 }
 
 func (c *ConfigService) LoadApps() ([]interfaces.AppInfo, error) {
-	if c.configPath == "" {
-		cfg, err := utils.LoadDotConfig()
-		if err == nil {
-			c.configPath = cfg
-		}
-	}
-
-	var apps []interfaces.AppInfo
-	if c.configPath != "" {
-		utilsApps, err := utils.FetchAppsInfo(c.configPath)
-		if err == nil {
-			apps = interfaces.ToAppInfo(utilsApps)
-		}
+	cfg, err := c.load()
+	if err != nil {
+		return nil, err
 	}
 
-	if len(apps) == 0 {
-		apps = defaultApps()
+	if len(cfg.Applications) == 0 {
+		return defaultApps(), nil
 	}
-
-	return apps, nil
+	return interfaces.ToAppInfo(cfg.Applications), nil
 }
 
 func defaultApps() []interfaces.AppInfo {
-	return []interfaces.AppInfo{
-		{
-			Name:                "Go",
-			Command:             "go",
-			VersionFlag:         "version",
-			RecommendedVersion:  "1.25.0",
-			InstallInstructions: map[string]string{"linux": "sudo apt install golang", "darwin": "brew install go"},
-		},
-		{
-			Name:                "Git",
-			Command:             "git",
-			VersionFlag:         "--version",
-			RecommendedVersion:  "2.40.0",
-			InstallInstructions: map[string]string{"linux": "sudo apt install git", "darwin": "brew install git"},
-		},
-		{
-			Name:                "Docker",
-			Command:             "docker",
-			VersionFlag:         "--version",
-			RecommendedVersion:  "20.10.0",
-			InstallInstructions: map[string]string{"linux": "curl https://get.docker.com", "darwin": "brew install docker"},
-		},
-	}
+	return interfaces.ToAppInfo(catalog.Defaults())
 }
 
 type ExecutorService struct{}
@@ -96,13 +71,16 @@ func NewExecutorService() *ExecutorService {
 	return &ExecutorService{}
 }
 
+// Run runs cmd and returns its stdout and stderr without trailing blank space, also when it
+// fails, so that callers can report what the command printed.
 func (e *ExecutorService) Run(cmd string, args ...string) (string, error) {
 	c := exec.Command(cmd, args...)
-	out, err := c.Output()
+	out, err := c.CombinedOutput()
+	output := strings.TrimRight(string(out), " \t\r\n")
 	if err != nil {
-		return "", fmt.Errorf("%s %v failed: %w", cmd, args, err)
+		return output, fmt.Errorf("%s %v failed: %w", cmd, args, err)
 	}
-	return strings.TrimSpace(string(out)), nil
+	return output, nil
 }
 
 func (e *ExecutorService) Which(cmd string) (string, error) {
@@ -148,7 +126,26 @@ func (a *AppValidatorService) ValidateOne(app interfaces.AppInfo) interfaces.App
 
 	result.IsInstalled = true
 	result.CurrentVersion = strings.TrimSpace(out)
-	result.IsUpToDate = result.CurrentVersion == app.RecommendedVersion
+
+	// RecommendedVersion is a constraint, as in the prereq package: "2.40" means at least 2.40.
+	version, err := semver.Extract(out, "")
+	if err == nil {
+		result.CurrentVersion = version.String()
+	}
+	if app.RecommendedVersion == "" {
+		result.IsUpToDate = true
+		return result
+	}
+	if err != nil {
+		result.VersionError = err.Error()
+		return result
+	}
+	constraint, err := semver.ParseConstraint(app.RecommendedVersion)
+	if err != nil {
+		result.VersionError = err.Error()
+		return result
+	}
+	result.IsUpToDate = constraint.Check(version)
 
 	return result
 }
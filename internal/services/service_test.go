@@ -2,6 +2,8 @@ package services
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/fabianoflorentino/whiterose/internal/interfaces"
@@ -29,7 +31,33 @@ func TestConfigService_LoadRepositories_NotFound(t *testing.T) {
 	}
 }
 
+func TestConfigService_LoadRepositories(t *testing.T) {
+	t.Setenv("WHITEROSE_CONFIG", filepath.Join(t.TempDir(), "missing.yaml"))
+	t.Setenv("WHITEROSE_TEST_ORG", "")
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "team.yaml"), []byte("workers: 2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "config.yaml")
+	content := "include: team.yaml\nrepositories:\n  - url: https://github.com/${WHITEROSE_TEST_ORG:-org}/api.git\n    directory: api\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	repos, err := NewConfigService(path).LoadRepositories()
+	if err != nil {
+		t.Fatalf("LoadRepositories() error = %v", err)
+	}
+	if len(repos) != 1 || repos[0].URL != "https://github.com/org/api.git" {
+		t.Errorf("LoadRepositories() = %+v, want the interpolated repository", repos)
+	}
+}
+
 func TestConfigService_LoadApps(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("WHITEROSE_CONFIG", "")
 	svc := NewConfigService("")
 	apps, err := svc.LoadApps()
 	if err != nil {
@@ -112,6 +140,29 @@ func TestAppValidatorService_ValidateOne_Installed(t *testing.T) {
 	}
 }
 
+func TestAppValidatorService_ValidateOne_Constraint(t *testing.T) {
+	tests := []struct {
+		installed   string
+		recommended string
+		want        bool
+	}{
+		{installed: "git version 2.43.0", recommended: "2.40", want: true},
+		{installed: "git version 2.39.2", recommended: "2.40", want: false},
+		{installed: "git version 2.43.0", recommended: "~2.40", want: false},
+		{installed: "git version 2.43.0", recommended: "", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.installed+" "+tt.recommended, func(t *testing.T) {
+			svc := NewAppValidatorService().WithExecutor(&mockExecutor{found: true, version: tt.installed})
+			result := svc.ValidateOne(interfaces.AppInfo{Name: "Git", Command: "git", VersionFlag: "--version", RecommendedVersion: tt.recommended})
+			if result.IsUpToDate != tt.want {
+				t.Errorf("IsUpToDate = %v, want %v (%+v)", result.IsUpToDate, tt.want, result)
+			}
+		})
+	}
+}
+
 func TestAppValidatorService_List(t *testing.T) {
 	svc := NewAppValidatorService()
 	apps := svc.List()
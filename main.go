@@ -2,16 +2,17 @@ package main
 
 import (
 	"log"
+	"os"
 
 	"github.com/fabianoflorentino/whiterose/cmd"
-	"github.com/fabianoflorentino/whiterose/config"
+	"github.com/fabianoflorentino/whiterose/exitcode"
 )
 
 func main() {
-	cfg := config.LoadOrDefault()
-	log.Printf("Using config: git.base=%s, repo.path=%s", cfg.Git.Base, cfg.Repo.Path)
-
+	// The configuration is loaded by the commands that need it, after cobra has parsed
+	// --profile and without resolving secrets for --help or secret set.
 	if err := cmd.Execute(); err != nil {
-		log.Fatalf("Failed to execute command: %v", err)
+		log.Printf("Failed to execute command: %v", err)
+		os.Exit(int(exitcode.Of(err)))
 	}
 }
\ No newline at end of file
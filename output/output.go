@@ -0,0 +1,215 @@
+// Package output renders command results as human-readable text, aligned tables, JSON or YAML
+// so that whiterose can be scripted against in CI.
+//
+// Commands build a typed result and hand it to Render with the format chosen by --output.
+// JSON and YAML are encoded from the json tags of the result, so both formats always carry
+// the same keys. Table and text output are provided by the result itself through the Tabler
+// and Texter interfaces.
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format selects how a result is written.
+type Format string
+
+const (
+	// FormatText is the human-readable output each command printed before --output existed.
+	FormatText Format = "text"
+	// FormatTable writes the result as an aligned table.
+	FormatTable Format = "table"
+	// FormatJSON writes the result as indented JSON.
+	FormatJSON Format = "json"
+	// FormatYAML writes the result as YAML.
+	FormatYAML Format = "yaml"
+)
+
+// Formats lists the supported formats, in the order they are documented.
+var Formats = []Format{FormatText, FormatTable, FormatJSON, FormatYAML}
+
+// ParseFormat returns the Format named by s, case-insensitively. An empty string selects FormatText.
+func ParseFormat(s string) (Format, error) {
+	if s == "" {
+		return FormatText, nil
+	}
+	for _, f := range Formats {
+		if strings.EqualFold(s, string(f)) {
+			return f, nil
+		}
+	}
+
+	names := make([]string, len(Formats))
+	for i, f := range Formats {
+		names[i] = string(f)
+	}
+	return "", fmt.Errorf("unknown output format %q (supported: %s)", s, strings.Join(names, ", "))
+}
+
+// Structured reports whether the format is meant to be parsed by programs rather than read.
+// Commands send their progress messages to stderr when it is, keeping stdout parseable.
+func (f Format) Structured() bool {
+	return f == FormatJSON || f == FormatYAML
+}
+
+// Table is a result laid out as rows of cells under a header.
+type Table struct {
+	Header []string
+	Rows   [][]string
+}
+
+// Tabler is implemented by results that can be written as a table.
+type Tabler interface {
+	Table() Table
+}
+
+// Texter is implemented by results with a human-readable representation of their own.
+type Texter interface {
+	Text(w io.Writer)
+}
+
+// List is a plain list of values, such as names. It is written one value per line as text,
+// under Header as a table and as an array in JSON and YAML.
+type List struct {
+	Header string
+	Values []string
+}
+
+// Text writes one value per line.
+func (l List) Text(w io.Writer) {
+	for _, v := range l.Values {
+		fmt.Fprintln(w, v)
+	}
+}
+
+// Table lays the values out in a single column.
+func (l List) Table() Table {
+	t := Table{Header: []string{l.Header}}
+	for _, v := range l.Values {
+		t.Rows = append(t.Rows, []string{v})
+	}
+	return t
+}
+
+// MarshalJSON encodes the values as an array, empty rather than null when there are none.
+func (l List) MarshalJSON() ([]byte, error) {
+	return json.Marshal(append([]string{}, l.Values...))
+}
+
+// ErrUnsupported is wrapped by the errors of Render for a format it cannot write v in, as opposed
+// to the errors encoding or writing v.
+var ErrUnsupported = errors.New("not supported")
+
+// Render writes v to w in the given format. Text output falls back to the table of v when it
+// has no text representation; table output fails when v cannot be written as a table.
+func Render(w io.Writer, format Format, v any) error {
+	switch format {
+	case FormatJSON:
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+		_, err = fmt.Fprintf(w, "%s\n", data)
+		return err
+	case FormatYAML:
+		data, err := encodeYAML(v)
+		if err != nil {
+			return fmt.Errorf("failed to encode YAML: %w", err)
+		}
+		_, err = w.Write(data)
+		return err
+	case FormatTable:
+		t, ok := v.(Tabler)
+		if !ok {
+			return fmt.Errorf("table output is %w for %T", ErrUnsupported, v)
+		}
+		return WriteTable(w, t.Table())
+	case FormatText, "":
+		if t, ok := v.(Texter); ok {
+			ew := &errWriter{w: w}
+			t.Text(ew)
+			return ew.err
+		}
+		if t, ok := v.(Tabler); ok {
+			return WriteTable(w, t.Table())
+		}
+		_, err := fmt.Fprintln(w, v)
+		return err
+	default:
+		return fmt.Errorf("output format %q is %w", format, ErrUnsupported)
+	}
+}
+
+// errWriter records the first error writing to w, which Texter implementations cannot return,
+// and fails the writes after it.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (e *errWriter) Write(p []byte) (int, error) {
+	if e.err != nil {
+		return 0, e.err
+	}
+	n, err := e.w.Write(p)
+	if err != nil {
+		e.err = err
+	}
+	return n, err
+}
+
+// WriteTable writes t with its columns aligned, the header first.
+func WriteTable(w io.Writer, t Table) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if len(t.Header) > 0 {
+		fmt.Fprintln(tw, strings.Join(t.Header, "\t"))
+	}
+	for _, row := range t.Rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// encodeYAML converts v to YAML through its JSON encoding, so that the json tags name the keys
+// and fields keep their declaration order.
+func encodeYAML(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	// JSON is valid YAML; decoding it into a node keeps the key order of the JSON document.
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	clearStyle(&doc)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// clearStyle drops the flow and quoting styles inherited from JSON so the node is written as
+// block YAML; the encoder still quotes strings that would otherwise be read as another type.
+func clearStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		clearStyle(c)
+	}
+}
@@ -0,0 +1,152 @@
+package output
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+type item struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	Enabled bool   `json:"enabled"`
+}
+
+type items []item
+
+func (l items) Table() Table {
+	t := Table{Header: []string{"NAME", "VERSION"}}
+	for _, i := range l {
+		t.Rows = append(t.Rows, []string{i.Name, i.Version})
+	}
+	return t
+}
+
+type greeting string
+
+func (g greeting) Text(w io.Writer) {
+	fmt.Fprintf(w, "hello %s\n", string(g))
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Format
+		wantErr bool
+	}{
+		{"", FormatText, false},
+		{"text", FormatText, false},
+		{"table", FormatTable, false},
+		{"JSON", FormatJSON, false},
+		{"yaml", FormatYAML, false},
+		{"xml", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseFormat(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseFormat(%q) = %q, %v", tt.in, got, err)
+		}
+	}
+
+	if FormatText.Structured() || FormatTable.Structured() || !FormatJSON.Structured() || !FormatYAML.Structured() {
+		t.Error("Structured() should only be true for json and yaml")
+	}
+}
+
+func TestRender(t *testing.T) {
+	list := items{{Name: "go", Version: "1.25.0", Enabled: true}, {Name: "true"}}
+
+	tests := []struct {
+		name   string
+		format Format
+		v      any
+		want   string
+	}{
+		{
+			name:   "json",
+			format: FormatJSON,
+			v:      list,
+			want:   "[\n  {\n    \"name\": \"go\",\n    \"version\": \"1.25.0\",\n    \"enabled\": true\n  },\n  {\n    \"name\": \"true\",\n    \"enabled\": false\n  }\n]\n",
+		},
+		{
+			name:   "yaml keeps the json keys and order",
+			format: FormatYAML,
+			v:      list,
+			want:   "- name: go\n  version: 1.25.0\n  enabled: true\n- name: \"true\"\n  enabled: false\n",
+		},
+		{
+			name:   "table",
+			format: FormatTable,
+			v:      list,
+			want:   "NAME  VERSION\ngo    1.25.0\ntrue  \n",
+		},
+		{
+			name:   "text falls back to the table",
+			format: FormatText,
+			v:      list,
+			want:   "NAME  VERSION\ngo    1.25.0\ntrue  \n",
+		},
+		{
+			name:   "text",
+			format: FormatText,
+			v:      greeting("world"),
+			want:   "hello world\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Render(&buf, tt.format, tt.v); err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("Render() =\n%q\nwant\n%q", buf.String(), tt.want)
+			}
+		})
+	}
+
+	for format, want := range map[Format]string{
+		FormatText:  "api\nworker\n",
+		FormatTable: "NAME\napi\nworker\n",
+		FormatJSON:  "[\n  \"api\",\n  \"worker\"\n]\n",
+		FormatYAML:  "- api\n- worker\n",
+	} {
+		var buf bytes.Buffer
+		if err := Render(&buf, format, List{Header: "NAME", Values: []string{"api", "worker"}}); err != nil || buf.String() != want {
+			t.Errorf("Render(%s, List) = %q, %v, want %q", format, buf.String(), err, want)
+		}
+	}
+
+	var empty bytes.Buffer
+	if err := Render(&empty, FormatJSON, List{}); err != nil || empty.String() != "[]\n" {
+		t.Errorf("Render(json, empty List) = %q, %v", empty.String(), err)
+	}
+
+	if err := Render(io.Discard, FormatTable, greeting("world")); !errors.Is(err, ErrUnsupported) || !strings.Contains(err.Error(), "table output is not supported") {
+		t.Errorf("Render() error = %v, want table output unsupported", err)
+	}
+	for _, tt := range []struct {
+		format Format
+		v      any
+	}{
+		{FormatText, greeting("world")},
+		{FormatText, list},
+		{FormatTable, list},
+		{FormatJSON, list},
+		{FormatYAML, list},
+	} {
+		if err := Render(failingWriter{}, tt.format, tt.v); err == nil || errors.Is(err, ErrUnsupported) {
+			t.Errorf("Render(%s, %T) to a failing writer error = %v, want the write error", tt.format, tt.v, err)
+		}
+	}
+}
+
+// failingWriter fails every write, like a closed pipe.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("broken pipe") }
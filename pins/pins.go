@@ -0,0 +1,200 @@
+// Package pins derives the tool versions required by the cloned repositories from the files
+// that pin them:
+//
+//	.tool-versions   "golang 1.24.1" lines of asdf and mise
+//	.go-version      the Go version of goenv and CI setups
+//	.nvmrc           the Node.js version of nvm
+//	go.mod           the toolchain directive, or else the go directive, as a minimum
+//	Dockerfile       ARG instructions such as "ARG GO_VERSION=1.24"
+//
+// A pin requires the same release series, so "1.24" accepts any 1.24.x, while the go.mod
+// directives are minimums. Tools are named by their catalog id ("golang" and "nodejs" become
+// "go" and "node"); Dockerfile ARGs are only read for tools of the catalog, and versions that are
+// not numbers, such as "lts/iron" or "system", are ignored.
+//
+// Merge combines the requirements of every repository per tool and reports a conflict when no
+// pinned version satisfies all of them, e.g. one repository pins Go 1.24 and another 1.26.
+// Check then compares them with the versions installed on the machine.
+package pins
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/fabianoflorentino/whiterose/catalog"
+	"github.com/fabianoflorentino/whiterose/semver"
+)
+
+// Repository is a cloned repository to scan.
+type Repository struct {
+	Name      string
+	Directory string
+}
+
+// Requirement is a tool version required by a file of a repository.
+type Requirement struct {
+	Tool    string `json:"tool"`
+	Version string `json:"version"`
+	// Constraint is the semver constraint the version stands for, "=1.24" for a pin and
+	// ">=1.24" for a minimum.
+	Constraint string `json:"constraint"`
+	Repository string `json:"repository"`
+	File       string `json:"file"`
+}
+
+// ErrNotCloned is returned by Scan for a repository whose directory does not exist.
+var ErrNotCloned = errors.New("not cloned")
+
+// parser reads the requirements of a file; repository and file are set by Scan.
+type parser func(data []byte) []Requirement
+
+// files are the pinning files, in the order their requirements are listed.
+var files = []struct {
+	name  string
+	parse parser
+}{
+	{".tool-versions", parseToolVersions},
+	{".go-version", pinFile("go")},
+	{".nvmrc", pinFile("node")},
+	{"go.mod", parseGoMod},
+	{"Dockerfile", parseDockerfile},
+}
+
+// Scan returns the requirements of the pinning files at the root of repo.
+func Scan(repo Repository) ([]Requirement, error) {
+	if info, err := os.Stat(repo.Directory); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("%s: %w", repo.Name, ErrNotCloned)
+	}
+
+	var reqs []Requirement
+	for _, f := range files {
+		data, err := os.ReadFile(filepath.Join(repo.Directory, f.name))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", repo.Name, err)
+		}
+
+		for _, r := range f.parse(data) {
+			r.Repository, r.File = repo.Name, f.name
+			reqs = append(reqs, r)
+		}
+	}
+	return reqs, nil
+}
+
+// pin returns the requirement of the same release series as version, or false when version is
+// not a number.
+func pin(tool, version string) (Requirement, bool) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if _, err := semver.Parse(version); err != nil {
+		return Requirement{}, false
+	}
+	return Requirement{Tool: toolID(tool), Version: version, Constraint: "=" + version}, true
+}
+
+// minimum returns the requirement of version or later, or false when version is not a number.
+func minimum(tool, version string) (Requirement, bool) {
+	r, ok := pin(tool, version)
+	r.Constraint = ">=" + r.Version
+	return r, ok
+}
+
+// aliases are the names tools are known by in pinning files that are not catalog ids.
+var aliases = map[string]string{
+	"golang":  "go",
+	"nodejs":  "node",
+	"python3": "python",
+}
+
+// toolID returns the catalog id of a tool named in a pinning file, or the lowercased name when
+// the catalog does not know it.
+func toolID(name string) string {
+	name = strings.ToLower(name)
+	if alias, ok := aliases[name]; ok {
+		return alias
+	}
+	if app, ok := catalog.Lookup(name); ok {
+		return app.Catalog
+	}
+	return name
+}
+
+// known reports whether the catalog has the tool named name.
+func known(name string) bool {
+	_, ok := catalog.Lookup(toolID(name))
+	return ok
+}
+
+// parseToolVersions reads "tool version [fallback...]" lines, using the first version.
+func parseToolVersions(data []byte) []Requirement {
+	var reqs []Requirement
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		if r, ok := pin(fields[0], fields[1]); ok {
+			reqs = append(reqs, r)
+		}
+	}
+	return reqs
+}
+
+// pinFile returns the parser of a file holding only the version of tool.
+func pinFile(tool string) parser {
+	return func(data []byte) []Requirement {
+		if r, ok := pin(tool, string(data)); ok {
+			return []Requirement{r}
+		}
+		return nil
+	}
+}
+
+// goDirective matches the go and toolchain directives of go.mod.
+var goDirective = regexp.MustCompile(`(?m)^\s*(go|toolchain)\s+(?:go)?(\S+)`)
+
+// parseGoMod reads the toolchain directive of go.mod, or the go directive when there is none.
+func parseGoMod(data []byte) []Requirement {
+	versions := map[string]string{}
+	for _, m := range goDirective.FindAllSubmatch(data, -1) {
+		versions[string(m[1])] = string(m[2])
+	}
+
+	version, ok := versions["toolchain"]
+	if !ok {
+		version = versions["go"]
+	}
+	if r, ok := minimum("go", version); ok {
+		return []Requirement{r}
+	}
+	return nil
+}
+
+// dockerArg matches "ARG NAME_VERSION=value" instructions.
+var dockerArg = regexp.MustCompile(`(?mi)^\s*ARG\s+([A-Za-z0-9_]+?)_VERSION\s*=\s*["']?([^\s"']+)`)
+
+// parseDockerfile reads the ARG instructions setting the version of a catalog tool, whose
+// underscores stand for dashes as in GOLANGCI_LINT_VERSION.
+func parseDockerfile(data []byte) []Requirement {
+	var reqs []Requirement
+	for _, m := range dockerArg.FindAllSubmatch(data, -1) {
+		tool := strings.ReplaceAll(string(m[1]), "_", "-")
+		if !known(tool) {
+			continue
+		}
+		if r, ok := pin(tool, string(m[2])); ok {
+			reqs = append(reqs, r)
+		}
+	}
+	return reqs
+}
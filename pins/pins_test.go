@@ -0,0 +1,106 @@
+package pins
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// requirements formats reqs as "tool constraint" for comparison.
+func requirements(reqs []Requirement) string {
+	var s []string
+	for _, r := range reqs {
+		s = append(s, r.Tool+" "+r.Constraint)
+	}
+	return strings.Join(s, ", ")
+}
+
+func TestParsers(t *testing.T) {
+	tests := []struct {
+		name  string
+		parse parser
+		data  string
+		want  string
+	}{
+		{
+			name:  "tool-versions",
+			parse: parseToolVersions,
+			data:  "# tools\ngolang 1.24.1\nnodejs 20.11.0 18.19.0\npython 3.12.4 # comment\nterraform latest\nruby\n",
+			want:  "go =1.24.1, node =20.11.0, python =3.12.4",
+		},
+		{
+			name:  "go-version",
+			parse: pinFile("go"),
+			data:  "1.26\n",
+			want:  "go =1.26",
+		},
+		{
+			name:  "nvmrc with v prefix",
+			parse: pinFile("node"),
+			data:  "v20\n",
+			want:  "node =20",
+		},
+		{
+			name:  "nvmrc alias",
+			parse: pinFile("node"),
+			data:  "lts/iron\n",
+			want:  "",
+		},
+		{
+			name:  "go.mod go directive",
+			parse: parseGoMod,
+			data:  "module example.com/a\n\ngo 1.24\n\nrequire example.com/b v1.0.0\n",
+			want:  "go >=1.24",
+		},
+		{
+			name:  "go.mod toolchain directive",
+			parse: parseGoMod,
+			data:  "module example.com/a\n\ngo 1.24.0\n\ntoolchain go1.25.3\n",
+			want:  "go >=1.25.3",
+		},
+		{
+			name:  "Dockerfile",
+			parse: parseDockerfile,
+			data:  "ARG GO_VERSION=1.24\nARG GOLANGCI_LINT_VERSION=\"v1.60.1\"\nARG APP_VERSION=2.0.0\nFROM golang:${GO_VERSION}\n",
+			want:  "go =1.24, golangci-lint =1.60.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := requirements(tt.parse([]byte(tt.data))); got != tt.want {
+				t.Errorf("parse() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScan(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("go.mod", "module a\n\ngo 1.24\n")
+	write(".go-version", "1.24.2\n")
+	write(".nvmrc", "20.11.0\n")
+
+	reqs, err := Scan(Repository{Name: "a", Directory: dir})
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if got, want := requirements(reqs), "go =1.24.2, node =20.11.0, go >=1.24"; got != want {
+		t.Errorf("Scan() = %q, want %q", got, want)
+	}
+	if reqs[0].Repository != "a" || reqs[0].File != ".go-version" {
+		t.Errorf("Scan()[0] = %+v", reqs[0])
+	}
+
+	_, err = Scan(Repository{Name: "b", Directory: filepath.Join(dir, "missing")})
+	if !errors.Is(err, ErrNotCloned) {
+		t.Errorf("Scan(missing) error = %v, want ErrNotCloned", err)
+	}
+}
@@ -0,0 +1,247 @@
+package pins
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/fabianoflorentino/whiterose/catalog"
+	"github.com/fabianoflorentino/whiterose/exitcode"
+	"github.com/fabianoflorentino/whiterose/output"
+	"github.com/fabianoflorentino/whiterose/prereq"
+	"github.com/fabianoflorentino/whiterose/semver"
+	"github.com/fabianoflorentino/whiterose/utils"
+)
+
+// Tool holds the requirements of every repository for one tool.
+type Tool struct {
+	ID string `json:"tool"`
+	// Constraint is the comma-separated list of the distinct constraints of Requirements.
+	Constraint   string        `json:"constraint"`
+	Requirements []Requirement `json:"requirements"`
+	// Conflict is true when no required version satisfies every requirement.
+	Conflict bool `json:"conflict"`
+}
+
+// Merge groups requirements by tool, in the order the tools are first required.
+func Merge(reqs []Requirement) []Tool {
+	var tools []Tool
+	for _, r := range reqs {
+		i := slices.IndexFunc(tools, func(t Tool) bool { return t.ID == r.Tool })
+		if i < 0 {
+			tools = append(tools, Tool{ID: r.Tool})
+			i = len(tools) - 1
+		}
+		tools[i].Requirements = append(tools[i].Requirements, r)
+	}
+
+	for i := range tools {
+		var constraints []string
+		for _, r := range tools[i].Requirements {
+			if !slices.Contains(constraints, r.Constraint) {
+				constraints = append(constraints, r.Constraint)
+			}
+		}
+		tools[i].Constraint = strings.Join(constraints, ", ")
+		tools[i].Conflict = !satisfiable(tools[i])
+	}
+	return tools
+}
+
+// satisfiable reports whether one of the versions required for t satisfies all its requirements.
+func satisfiable(t Tool) bool {
+	constraint, err := semver.ParseConstraint(t.Constraint)
+	if err != nil {
+		return false
+	}
+	for _, r := range t.Requirements {
+		if v, err := semver.Parse(r.Version); err == nil && constraint.Check(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// Status is the state of a tool on the machine compared with the requirements.
+type Status struct {
+	Tool
+	Installed      bool   `json:"installed"`
+	TimedOut       bool   `json:"timedOut,omitempty"`
+	CurrentVersion string `json:"currentVersion,omitempty"`
+	// Satisfied is true when the installed version satisfies every requirement.
+	Satisfied bool `json:"satisfied"`
+}
+
+// Report is the outcome of checking the requirements of the repositories.
+type Report struct {
+	Tools []Status `json:"tools"`
+	// NotCloned are the repositories that could not be scanned because they are not cloned.
+	NotCloned []string `json:"notCloned,omitempty"`
+}
+
+// Check scans repos, merges their requirements and validates the installed version of every
+// required tool with av. A tool is checked as the application of av based on its catalog entry
+// or running its command, falling back to its catalog entry and then to "tool --version".
+func Check(repos []Repository, av *prereq.AppValidator) (Report, error) {
+	report := Report{Tools: []Status{}}
+
+	var reqs []Requirement
+	for _, repo := range repos {
+		found, err := Scan(repo)
+		if errors.Is(err, ErrNotCloned) {
+			report.NotCloned = append(report.NotCloned, repo.Name)
+			continue
+		}
+		if err != nil {
+			return report, err
+		}
+		reqs = append(reqs, found...)
+	}
+
+	tools := Merge(reqs)
+	toCheck := make([]utils.AppInfo, 0, len(tools))
+	for _, t := range tools {
+		app := application(t.ID, av.Apps())
+		app.RecommendedVersion = t.Constraint
+		// Only the version matters here, not whether the tool is ready for others.
+		app.DependsOn, app.HealthCheck = nil, nil
+		toCheck = append(toCheck, app)
+	}
+
+	results := av.CheckApps(toCheck)
+	for i, t := range tools {
+		status := Status{Tool: t}
+		if i < len(results) {
+			r := results[i]
+			status.Installed = r.IsInstalled
+			status.TimedOut = r.TimedOut
+			status.CurrentVersion = r.CurrentVersion
+			status.Satisfied = r.IsInstalled && r.IsUpToDate && r.VersionError == ""
+		}
+		report.Tools = append(report.Tools, status)
+	}
+	return report, nil
+}
+
+// application returns the application checking tool.
+func application(tool string, apps []utils.AppInfo) utils.AppInfo {
+	entry, inCatalog := catalog.Lookup(tool)
+	for _, app := range apps {
+		if strings.EqualFold(app.Catalog, tool) || strings.EqualFold(app.Command, tool) ||
+			(inCatalog && strings.EqualFold(app.Command, entry.Command)) {
+			return app
+		}
+	}
+	if inCatalog {
+		return entry
+	}
+	return utils.AppInfo{Name: tool, Command: tool, VersionFlag: "--version"}
+}
+
+// state returns the status of s as shown in tables.
+func (s Status) state() string {
+	switch {
+	case s.Conflict:
+		return "conflict"
+	case s.TimedOut:
+		return "timed out"
+	case !s.Installed:
+		return "not installed"
+	case s.Satisfied:
+		return "ok"
+	default:
+		return "outdated"
+	}
+}
+
+// Err returns an error with the exitcode.Prerequisite status naming the tools whose requirements
+// conflict, that are not installed or, when strict, whose installed version does not satisfy
+// the requirements.
+func (r Report) Err(strict bool) error {
+	var conflicts, missing, outdated []string
+	for _, s := range r.Tools {
+		switch s.state() {
+		case "conflict":
+			conflicts = append(conflicts, fmt.Sprintf("%s (%s)", s.ID, s.Constraint))
+		case "not installed", "timed out":
+			missing = append(missing, s.ID)
+		case "outdated":
+			if strict {
+				outdated = append(outdated, fmt.Sprintf("%s %s (requires %s)", s.ID, s.CurrentVersion, s.Constraint))
+			}
+		}
+	}
+
+	var problems []string
+	if len(conflicts) > 0 {
+		problems = append(problems, "conflicting requirements: "+strings.Join(conflicts, ", "))
+	}
+	if len(missing) > 0 {
+		problems = append(problems, "missing prerequisites: "+strings.Join(missing, ", "))
+	}
+	if len(outdated) > 0 {
+		problems = append(problems, "outdated prerequisites: "+strings.Join(outdated, ", "))
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return exitcode.Errorf(exitcode.Prerequisite, "%s", strings.Join(problems, "; "))
+}
+
+// Text writes every tool with the files requiring it and its installed version.
+func (r Report) Text(w io.Writer) {
+	for _, name := range r.NotCloned {
+		fmt.Fprintf(w, "⚠️  %s is not cloned, run 'whiterose setup --repos' to scan it\n", name)
+	}
+	if len(r.Tools) == 0 {
+		fmt.Fprintln(w, "No version requirements found in the repositories.")
+		return
+	}
+
+	for _, s := range r.Tools {
+		fmt.Fprintf(w, "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+		fmt.Fprintf(w, "📌 %s %s\n", s.ID, s.Constraint)
+		for _, req := range s.Requirements {
+			fmt.Fprintf(w, "   %s: %s (%s)\n", req.Repository, req.File, req.Constraint)
+		}
+
+		switch s.state() {
+		case "conflict":
+			fmt.Fprintf(w, "❌ Conflict: no version satisfies every repository\n")
+		case "timed out":
+			fmt.Fprintf(w, "⏱️  Version check timed out\n")
+		case "not installed":
+			fmt.Fprintf(w, "❌ Not installed\n")
+		case "outdated":
+			fmt.Fprintf(w, "⚠️  Installed: %s, does not satisfy %s\n", s.CurrentVersion, s.Constraint)
+		default:
+			fmt.Fprintf(w, "✅ Installed: %s\n", s.CurrentVersion)
+		}
+		if s.Conflict && s.Installed {
+			fmt.Fprintf(w, "📦 Installed: %s\n", s.CurrentVersion)
+		}
+		fmt.Fprintf(w, "\n")
+	}
+}
+
+// Table lays the report out one tool per row.
+func (r Report) Table() output.Table {
+	t := output.Table{Header: []string{"TOOL", "REQUIRED", "INSTALLED", "STATUS", "SOURCES"}}
+	for _, s := range r.Tools {
+		var sources []string
+		for _, req := range s.Requirements {
+			sources = append(sources, req.Repository+"/"+req.File)
+		}
+		installed := "-"
+		if s.CurrentVersion != "" {
+			installed = s.CurrentVersion
+		}
+		t.Rows = append(t.Rows, []string{s.ID, s.Constraint, installed, s.state(), strings.Join(sources, ", ")})
+	}
+	for _, name := range r.NotCloned {
+		t.Rows = append(t.Rows, []string{"-", "-", "-", "not cloned", name})
+	}
+	return t
+}
@@ -0,0 +1,119 @@
+package pins
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fabianoflorentino/whiterose/exitcode"
+	"github.com/fabianoflorentino/whiterose/prereq"
+	"github.com/fabianoflorentino/whiterose/utils"
+)
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name           string
+		reqs           []Requirement
+		wantConstraint string
+		wantConflict   bool
+	}{
+		{
+			name:           "same pin",
+			reqs:           []Requirement{{Tool: "go", Version: "1.24", Constraint: "=1.24"}, {Tool: "go", Version: "1.24", Constraint: "=1.24"}},
+			wantConstraint: "=1.24",
+		},
+		{
+			name:           "pin within series",
+			reqs:           []Requirement{{Tool: "go", Version: "1.24", Constraint: "=1.24"}, {Tool: "go", Version: "1.24.3", Constraint: "=1.24.3"}},
+			wantConstraint: "=1.24, =1.24.3",
+		},
+		{
+			name:           "different pins",
+			reqs:           []Requirement{{Tool: "go", Version: "1.24", Constraint: "=1.24"}, {Tool: "go", Version: "1.26", Constraint: "=1.26"}},
+			wantConstraint: "=1.24, =1.26",
+			wantConflict:   true,
+		},
+		{
+			name:           "minimum and pin",
+			reqs:           []Requirement{{Tool: "go", Version: "1.24", Constraint: ">=1.24"}, {Tool: "go", Version: "1.26", Constraint: "=1.26"}},
+			wantConstraint: ">=1.24, =1.26",
+		},
+		{
+			name:           "pin below minimum",
+			reqs:           []Requirement{{Tool: "go", Version: "1.26", Constraint: ">=1.26"}, {Tool: "go", Version: "1.24", Constraint: "=1.24"}},
+			wantConstraint: ">=1.26, =1.24",
+			wantConflict:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tools := Merge(tt.reqs)
+			if len(tools) != 1 {
+				t.Fatalf("Merge() = %+v, want one tool", tools)
+			}
+			if tools[0].Constraint != tt.wantConstraint || tools[0].Conflict != tt.wantConflict {
+				t.Errorf("Merge() = %q conflict %v, want %q conflict %v", tools[0].Constraint, tools[0].Conflict, tt.wantConstraint, tt.wantConflict)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	root := t.TempDir()
+	for dir, files := range map[string]map[string]string{
+		"a": {".tool-versions": "golang 1.24.1\nnodejs 20.11.0\n"},
+		"b": {".go-version": "1.26\n", ".nvmrc": "20\n"},
+	} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		for name, data := range files {
+			if err := os.WriteFile(filepath.Join(root, dir, name), []byte(data), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	av := &prereq.AppValidator{}
+	av.AddApp(utils.AppInfo{Catalog: "go", Name: "Go", Command: "echo", VersionFlag: "go version go1.24.1 linux/amd64"})
+	av.AddApp(utils.AppInfo{Catalog: "node", Name: "Node.js", Command: "echo", VersionFlag: "v18.19.0"})
+
+	report, err := Check([]Repository{
+		{Name: "a", Directory: filepath.Join(root, "a")},
+		{Name: "b", Directory: filepath.Join(root, "b")},
+		{Name: "c", Directory: filepath.Join(root, "c")},
+	}, av)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	if len(report.NotCloned) != 1 || report.NotCloned[0] != "c" {
+		t.Errorf("NotCloned = %v, want [c]", report.NotCloned)
+	}
+
+	var got []string
+	for _, row := range report.Table().Rows {
+		got = append(got, strings.Join(row[:4], " "))
+	}
+	want := []string{
+		"go =1.24.1, =1.26 1.24.1 conflict",
+		"node =20.11.0, =20 18.19.0 outdated",
+		"- - - not cloned",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Table() rows =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	err = report.Err(false)
+	if exitcode.Of(err) != exitcode.Prerequisite || !strings.Contains(err.Error(), "conflicting requirements: go") {
+		t.Errorf("Err(false) = %v", err)
+	}
+	if strings.Contains(err.Error(), "node") {
+		t.Errorf("Err(false) reports the outdated node: %v", err)
+	}
+	if err := report.Err(true); err == nil || !strings.Contains(err.Error(), "outdated prerequisites: node 18.19.0") {
+		t.Errorf("Err(true) = %v", err)
+	}
+}
@@ -0,0 +1,143 @@
+package prereq
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/fabianoflorentino/whiterose/internal/interfaces"
+	"github.com/fabianoflorentino/whiterose/utils"
+)
+
+// Health states of the applications with a health check.
+const (
+	Healthy   = "healthy"
+	Unhealthy = "unhealthy"
+)
+
+// ready reports whether an application can be relied on by the applications depending on it:
+// it is installed, answered in time, was not blocked and passed its health check.
+func ready(r interfaces.AppValidation) bool {
+	return r.IsInstalled && !r.TimedOut && len(r.BlockedBy) == 0 && r.Health != Unhealthy
+}
+
+// find returns the registered application whose name or command is name.
+func (av *AppValidator) find(name string) (utils.AppInfo, bool) {
+	for _, app := range av.apps {
+		if strings.EqualFold(app.Name, name) || strings.EqualFold(app.Command, name) {
+			return app, true
+		}
+	}
+	return utils.AppInfo{}, false
+}
+
+// withDependencies returns apps followed by the registered applications they depend on,
+// directly or not, that are not already in apps.
+func (av *AppValidator) withDependencies(apps []utils.AppInfo) []utils.AppInfo {
+	all := append([]utils.AppInfo{}, apps...)
+	seen := map[string]bool{}
+	for _, app := range apps {
+		seen[app.Name] = true
+	}
+
+	for i := 0; i < len(all); i++ {
+		for _, name := range all[i].DependsOn {
+			dep, ok := av.find(name)
+			if ok && !seen[dep.Name] {
+				seen[dep.Name] = true
+				all = append(all, dep)
+			}
+		}
+	}
+	return all
+}
+
+// validateAll validates apps in dependency order and returns the results in that order.
+//
+// The applications are checked in rounds: each round concurrently checks the applications
+// whose dependencies were all checked in earlier rounds, in the order of apps. An application
+// whose dependency is not ready, is unknown or is part of a dependency cycle is not checked
+// and is reported blocked by it.
+func (av *AppValidator) validateAll(apps []utils.AppInfo) Results {
+	results := make(Results, 0, len(apps))
+	checked := map[string]interfaces.AppValidation{}
+	pending := apps
+
+	for len(pending) > 0 {
+		var round, next []utils.AppInfo
+		for _, app := range pending {
+			if av.waiting(app, pending) {
+				next = append(next, app)
+			} else {
+				round = append(round, app)
+			}
+		}
+		// Every pending application waits for another one: they depend on each other.
+		if len(round) == 0 {
+			round, next = next, nil
+		}
+
+		for _, r := range av.validateRound(round, checked) {
+			checked[r.Name] = r
+			results = append(results, r)
+		}
+		pending = next
+	}
+
+	return results
+}
+
+// waiting reports whether app depends on an application of pending other than itself.
+func (av *AppValidator) waiting(app utils.AppInfo, pending []utils.AppInfo) bool {
+	for _, name := range app.DependsOn {
+		dep, ok := av.find(name)
+		if !ok || dep.Name == app.Name {
+			continue
+		}
+		for _, p := range pending {
+			if p.Name == dep.Name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// validateRound concurrently validates apps whose dependencies are ready in checked, and
+// reports the others blocked.
+func (av *AppValidator) validateRound(apps []utils.AppInfo, checked map[string]interfaces.AppValidation) Results {
+	results := make(Results, len(apps))
+
+	var wg sync.WaitGroup
+	for i, app := range apps {
+		if blockers := av.blockers(app, checked); len(blockers) > 0 {
+			results[i] = av.result(app)
+			results[i].BlockedBy = blockers
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = av.validate(app)
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+// blockers returns the dependencies of app that are unknown or not ready in checked.
+func (av *AppValidator) blockers(app utils.AppInfo, checked map[string]interfaces.AppValidation) []string {
+	var blockers []string
+	for _, name := range app.DependsOn {
+		dep, ok := av.find(name)
+		if !ok {
+			blockers = append(blockers, name)
+			continue
+		}
+		if r, ok := checked[dep.Name]; !ok || !ready(r) {
+			blockers = append(blockers, dep.Name)
+		}
+	}
+	return blockers
+}
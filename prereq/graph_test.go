@@ -0,0 +1,116 @@
+package prereq
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fabianoflorentino/whiterose/utils"
+)
+
+// shell returns a health check running script with sh.
+func shell(script, expect string) *utils.HealthCheck {
+	return &utils.HealthCheck{Command: "sh", Args: []string{"-c", script}, Expect: expect}
+}
+
+func TestAppValidator_Check_Dependencies(t *testing.T) {
+	tests := []struct {
+		name string
+		apps []utils.AppInfo
+		// want lists the applications in the order they are reported, with their table status.
+		want []string
+	}{
+		{
+			name: "dependencies are checked first",
+			apps: []utils.AppInfo{
+				{Name: "Compose", Command: "echo", VersionFlag: "2.29.1", DependsOn: []string{"docker"}},
+				{Name: "Docker", Command: "echo", VersionFlag: "27.1.0", DependsOn: []string{"Shell"}},
+				{Name: "Shell", Command: "echo", VersionFlag: "1.0"},
+				{Name: "Go", Command: "echo", VersionFlag: "1.25.3"},
+			},
+			want: []string{"Shell: installed", "Go: installed", "Docker: installed", "Compose: installed"},
+		},
+		{
+			name: "missing dependency blocks",
+			apps: []utils.AppInfo{
+				{Name: "Lint", Command: "echo", VersionFlag: "1.60.0", DependsOn: []string{"Go"}},
+				{Name: "Go", Command: "nonexistent-cmd"},
+			},
+			want: []string{"Go: not installed", "Lint: blocked"},
+		},
+		{
+			name: "unhealthy dependency blocks",
+			apps: []utils.AppInfo{
+				{Name: "Docker", Command: "echo", VersionFlag: "27.1.0", HealthCheck: shell("echo 'Cannot connect to the Docker daemon' >&2; exit 1", "")},
+				{Name: "Compose", Command: "echo", VersionFlag: "2.29.1", DependsOn: []string{"Docker"}},
+				{Name: "Go", Command: "echo", VersionFlag: "1.25.3", HealthCheck: shell("echo ok", "^ok")},
+			},
+			want: []string{"Docker: unhealthy", "Go: healthy", "Compose: blocked"},
+		},
+		{
+			name: "unknown dependency and cycle",
+			apps: []utils.AppInfo{
+				{Name: "A", Command: "echo", VersionFlag: "1.0", DependsOn: []string{"B"}},
+				{Name: "B", Command: "echo", VersionFlag: "1.0", DependsOn: []string{"A"}},
+				{Name: "C", Command: "echo", VersionFlag: "1.0", DependsOn: []string{"unknown"}},
+			},
+			want: []string{"C: blocked", "A: blocked", "B: blocked"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := (&AppValidator{apps: tt.apps, os: "linux"}).Check()
+
+			var got []string
+			for _, row := range results.Table().Rows {
+				got = append(got, row[0]+": "+row[2])
+			}
+			if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+				t.Errorf("Check() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppValidator_CheckSpecific_Dependencies(t *testing.T) {
+	av := &AppValidator{apps: []utils.AppInfo{
+		{Name: "Docker", Command: "echo", VersionFlag: "27.1.0"},
+		{Name: "Compose", Command: "docker-compose", DependsOn: []string{"Docker"}},
+		{Name: "Go", Command: "echo", VersionFlag: "1.25.3"},
+	}}
+
+	results := av.CheckSpecific([]string{"docker-compose"})
+	if len(results) != 2 || results[0].Name != "Docker" || results[1].Name != "Compose" {
+		t.Errorf("CheckSpecific() = %+v, want Docker then Compose", results)
+	}
+}
+
+func TestAppValidator_checkHealth(t *testing.T) {
+	tests := []struct {
+		name    string
+		check   *utils.HealthCheck
+		timeout string
+		wantErr string
+	}{
+		{"passes", shell("echo 'Logged in to github.com' >&2", "Logged in"), "", ""},
+		{"no pattern", shell("true", ""), "", ""},
+		{"fails", shell("echo starting; echo 'Cannot connect to the Docker daemon' >&2; exit 1", ""), "", "sh -c echo starting; echo 'Cannot connect to the Docker daemon' >&2; exit 1 failed: exit status 1: Cannot connect to the Docker daemon"},
+		{"output does not match", shell("echo 'not logged in'", "^Logged in"), "", `does not match "^Logged in"`},
+		{"timeout", shell("sleep 5", ""), "100ms", "did not answer within 100ms"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&AppValidator{}).checkHealth(utils.AppInfo{Name: "Tool", Timeout: tt.timeout, HealthCheck: tt.check})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("checkHealth() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("checkHealth() = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,71 +1,110 @@
-// Package prereq provides utilities for validating the presence and versions of essential
-// command-line applications required for development environments. It supports checking
-// installation status, displaying recommended versions, and providing OS-specific installation
-// instructions for applications such as Go, Git, Docker, jq, and yq.
+// Package prereq validates the presence, versions and health of the command-line applications
+// a development environment needs. The applications are the utils.AppInfo entries of the
+// configuration, or the defaults of the catalog when it lists none, and a check reports whether
+// each one is installed, its version against the recommended one and how to install it on the
+// current OS.
 //
 // Types:
 //
-//   - AppInfo: Represents metadata about an application, including its name, command,
-//     version flag, recommended version, and installation instructions per OS.
-//   - AppValidator: Manages a list of applications to validate, detects the current OS,
-//     and provides methods for validating all or specific applications.
+//   - AppValidator: Holds the applications to validate and the current OS, and checks all or
+//     specific applications.
+//   - Results: The outcome of a check, one interfaces.AppValidation per application.
+//   - AppList: The applications available for validation.
 //
 // Functions:
 //
-//   - NewAppValidator: Constructs a new AppValidator pre-populated with common development tools.
+//   - NewAppValidator: Constructs an AppValidator for the applications of the configuration,
+//     returning the error when the configuration cannot be loaded.
+//   - (*AppValidator) WithTimeout: Sets the timeout of the applications that have none.
 //   - (*AppValidator) AddApp: Adds a custom application to the validator.
-//   - (*AppValidator) ValidateApps: Checks all registered applications for installation and version.
-//   - (*AppValidator) ValidateSpecificApps: Validates only the specified applications by name or command.
-//   - (*AppValidator) ListAvailableApps: Lists all applications available for validation.
-//   - (*AppValidator) getOSName: Returns a human-readable name for the current OS.
-//   - (*AppValidator) checkAppInstalled: Checks if an application is installed and retrieves its version.
+//   - (*AppValidator) Check: Checks all registered applications and returns the Results.
+//   - (*AppValidator) CheckApps: Checks the given applications, which need not be registered.
+//   - (*AppValidator) CheckSpecific: Checks only the specified applications by name or command.
+//   - (*AppValidator) Apps: Returns the applications available for validation as an AppList.
+//   - (*AppValidator) Missing: Returns the applications that a check found not installed.
 //
 // Usage:
 //
-//	validator := prereq.NewAppValidator()
-//	validator.ValidateApps()
-//	validator.ValidateSpecificApps([]string{"Go", "Git"})
-//	validator.ListAvailableApps()
+//	validator, err := prereq.NewAppValidator()
+//	if err != nil {
+//		return err
+//	}
+//	results := validator.CheckSpecific([]string{"Go", "Git"})
+//	return results.Err(false)
+//
+// The installed version is extracted from the output of the version command with
+// semver.Extract, using the VersionRegex of the application when it has one, and checked
+// against RecommendedVersion as a semver constraint such as ">=1.25" or "~2.40".
+//
+// The applications are checked concurrently and each version or health check command is killed
+// after the Timeout of its application, or DefaultTimeout. An application whose version command
+// does not answer in time, such as docker waiting for an unreachable daemon, is reported as
+// timed out rather than not installed.
+//
+// Applications are checked after the applications of their DependsOn list, in dependency order;
+// one whose dependency is missing, timed out, blocked or unhealthy is not checked and is
+// reported blocked. An installed application with a HealthCheck is then reported healthy or
+// unhealthy, as its health check command passes or not.
+//
+// Results.Err reports the missing, timed out, blocked and unhealthy applications, and in strict
+// mode the outdated ones, with the exitcode.Prerequisite status.
+// Results and AppList implement output.Tabler and output.Texter, so they can be rendered as
+// text, tables, JSON or YAML.
 package prereq
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os/exec"
+	"regexp"
 	"runtime"
 	"strings"
+	"time"
 
+	"github.com/fabianoflorentino/whiterose/catalog"
+	"github.com/fabianoflorentino/whiterose/config"
+	"github.com/fabianoflorentino/whiterose/exitcode"
+	"github.com/fabianoflorentino/whiterose/internal/interfaces"
+	"github.com/fabianoflorentino/whiterose/output"
+	"github.com/fabianoflorentino/whiterose/semver"
 	"github.com/fabianoflorentino/whiterose/utils"
 )
 
-// AppInfo holds information about a command-line application.
-type AppInfo struct {
-	Name                string
-	Command             string
-	VersionFlag         string
-	RecommendedVersion  string
-	InstallInstructions map[string]string
-}
+// DefaultTimeout is how long the version command of an application may run when neither the
+// application nor WithTimeout sets a timeout.
+const DefaultTimeout = 10 * time.Second
 
 // AppValidator manages a list of applications to validate.
 type AppValidator struct {
 	apps []utils.AppInfo
 	os   string
+	// timeout applies to the applications without a timeout; DefaultTimeout when zero.
+	timeout time.Duration
 }
 
-// NewAppValidator constructs a new AppValidator pre-populated with common development tools.
-func NewAppValidator() *AppValidator {
-	config, err := utils.LoadDotConfig()
+// NewAppValidator constructs an AppValidator for the applications of the configuration, or the
+// default applications of the catalog when it has none. When the configuration cannot be loaded
+// it returns the error, with a validator that has no applications.
+func NewAppValidator() (*AppValidator, error) {
+	cfg, err := config.Load()
 	if err != nil {
-		fmt.Printf("Error loading config: %v\n", err)
+		return &AppValidator{os: runtime.GOOS}, err
 	}
 
-	apps, err := utils.FetchAppsInfo(config)
-	if err != nil {
-		fmt.Printf("Error fetching applications: %v\n", err)
-		return &AppValidator{os: runtime.GOOS, apps: apps}
+	apps := cfg.Applications
+	if len(apps) == 0 {
+		apps = catalog.Defaults()
 	}
+	return &AppValidator{os: runtime.GOOS, apps: apps}, nil
+}
 
-	return &AppValidator{os: runtime.GOOS, apps: apps}
+// WithTimeout sets how long the version command of the applications that have no timeout of
+// their own may run.
+func (av *AppValidator) WithTimeout(timeout time.Duration) *AppValidator {
+	av.timeout = timeout
+	return av
 }
 
 // AddApp adds a custom application to the validator.
@@ -73,77 +112,268 @@ func (av *AppValidator) AddApp(app utils.AppInfo) {
 	av.apps = append(av.apps, app)
 }
 
-// ValidateApps checks all registered applications for installation and version.
-func (av *AppValidator) ValidateApps() {
-	installedCount := 0
+// Results are the validations of a set of applications, in the order they were checked.
+type Results []interfaces.AppValidation
 
-	for _, app := range av.apps {
-		fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+// AppList is the list of applications available for validation.
+type AppList []utils.AppInfo
 
-		installed, version, err := av.checkAppInstalled(app)
+// Check validates every registered application.
+func (av *AppValidator) Check() Results {
+	return av.validateAll(av.apps)
+}
 
-		if installed && err == nil {
-			fmt.Printf("💾 %s\n", app.Name)
-			fmt.Printf("✅ Status: INSTALLED\n")
-			fmt.Printf("📦 Version: %s\n", version)
-			fmt.Printf("🎯 Recommended: %s\n", app.RecommendedVersion)
-			installedCount++
-		} else {
-			fmt.Printf("❌ Status: NOT INSTALLED\n")
-			fmt.Printf("🎯 Recommended Version: %s\n", app.RecommendedVersion)
-			fmt.Printf("📥 Installation Instructions:\n")
+// CheckApps validates apps instead of the registered applications, with the timeout of the
+// validator.
+func (av *AppValidator) CheckApps(apps []utils.AppInfo) Results {
+	return av.validateAll(apps)
+}
 
-			if instruction, exists := app.InstallInstructions[av.os]; exists {
-				fmt.Printf("   %s\n", instruction)
-			} else {
-				fmt.Printf("   Instructions not available for %s\n", av.getOSName())
-			}
+// CheckSpecific validates only the applications whose name or command is in appNames, and the
+// applications they depend on. Names that match no registered application are ignored.
+func (av *AppValidator) CheckSpecific(appNames []string) Results {
+	var apps []utils.AppInfo
+	for _, name := range appNames {
+		if app, ok := av.find(name); ok {
+			apps = append(apps, app)
 		}
-
-		fmt.Printf("\n")
 	}
+	return av.validateAll(av.withDependencies(apps))
 }
 
-// ValidateSpecificApps validates only the specified applications by name or command.
-// It accepts a slice of application names or commands to validate.
-// If an application is not found in the predefined list, it is skipped with a message.
-func (av *AppValidator) ValidateSpecificApps(appNames []string) {
-	var appsToValidate []utils.AppInfo
+// Apps returns the applications available for validation.
+func (av *AppValidator) Apps() AppList {
+	return append(AppList{}, av.apps...)
+}
 
-	for _, name := range appNames {
+// Missing returns the registered applications that results report as not installed. Timed out
+// applications are not missing: their command exists but did not answer; neither are blocked
+// ones, which were not checked.
+func (av *AppValidator) Missing(results Results) []utils.AppInfo {
+	var missing []utils.AppInfo
+	for _, r := range results {
+		if r.IsInstalled || r.TimedOut || len(r.BlockedBy) > 0 {
+			continue
+		}
 		for _, app := range av.apps {
-			if strings.EqualFold(app.Name, name) || strings.EqualFold(app.Command, name) {
-				appsToValidate = append(appsToValidate, app)
+			if app.Name == r.Name {
+				missing = append(missing, app)
 				break
 			}
 		}
 	}
+	return missing
+}
+
+// result returns the validation of app before it is checked.
+func (av *AppValidator) result(app utils.AppInfo) interfaces.AppValidation {
+	return interfaces.AppValidation{
+		Name:               app.Name,
+		Command:            app.Command,
+		RecommendedVersion: app.RecommendedVersion,
+		OS:                 av.os,
+	}
+}
+
+// validate checks a single application and describes the result.
+func (av *AppValidator) validate(app utils.AppInfo) interfaces.AppValidation {
+	result := av.result(app)
+
+	installed, out, err := av.checkAppInstalled(app)
+	if errors.Is(err, context.DeadlineExceeded) {
+		result.TimedOut = true
+		result.VersionError = err.Error()
+		return result
+	}
+	if !installed || err != nil {
+		result.InstallInstruction = app.InstallInstructions[av.os]
+		return result
+	}
+
+	result.IsInstalled = true
+	checkVersion(&result, app, out)
+
+	if app.HealthCheck != nil {
+		result.Health = Healthy
+		if err := av.checkHealth(app); err != nil {
+			result.Health, result.HealthError = Unhealthy, err.Error()
+		}
+	}
+
+	return result
+}
+
+// checkVersion sets the version of result from out, the output of the version command, and
+// checks it against the recommended version of app.
+func checkVersion(result *interfaces.AppValidation, app utils.AppInfo, out string) {
+	result.CurrentVersion = out
+
+	version, err := semver.Extract(out, app.VersionRegex)
+	if err == nil {
+		result.CurrentVersion = version.String()
+	}
+	if app.RecommendedVersion == "" {
+		result.IsUpToDate = true
+		return
+	}
+	if err != nil {
+		result.VersionError = err.Error()
+		return
+	}
+
+	constraint, err := semver.ParseConstraint(app.RecommendedVersion)
+	if err != nil {
+		result.VersionError = err.Error()
+		return
+	}
+	result.IsUpToDate = constraint.Check(version)
+}
+
+// Err returns an error with the exitcode.Prerequisite status naming the applications that are not
+// installed, timed out, blocked by a dependency or unhealthy and, when strict, those that do not satisfy their recommended version.
+// It returns nil when there are none.
+func (r Results) Err(strict bool) error {
+	var missing, timedOut, blocked, unhealthy, outdated []string
+	for _, app := range r {
+		switch {
+		case app.TimedOut:
+			timedOut = append(timedOut, app.Name)
+		case len(app.BlockedBy) > 0:
+			blocked = append(blocked, fmt.Sprintf("%s (requires %s)", app.Name, strings.Join(app.BlockedBy, ", ")))
+		case !app.IsInstalled:
+			missing = append(missing, app.Name)
+		case app.Health == Unhealthy:
+			unhealthy = append(unhealthy, app.Name)
+		case strict && !app.IsUpToDate:
+			outdated = append(outdated, fmt.Sprintf("%s %s (requires %s)", app.Name, app.CurrentVersion, app.RecommendedVersion))
+		}
+	}
+
+	var problems []string
+	if len(missing) > 0 {
+		problems = append(problems, "missing prerequisites: "+strings.Join(missing, ", "))
+	}
+	if len(timedOut) > 0 {
+		problems = append(problems, "timed out prerequisites: "+strings.Join(timedOut, ", "))
+	}
+	if len(blocked) > 0 {
+		problems = append(problems, "blocked prerequisites: "+strings.Join(blocked, ", "))
+	}
+	if len(unhealthy) > 0 {
+		problems = append(problems, "unhealthy prerequisites: "+strings.Join(unhealthy, ", "))
+	}
+	if len(outdated) > 0 {
+		problems = append(problems, "outdated prerequisites: "+strings.Join(outdated, ", "))
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return exitcode.Errorf(exitcode.Prerequisite, "%s", strings.Join(problems, "; "))
+}
 
-	if len(appsToValidate) == 0 {
-		fmt.Println("❌ No applications found in the list to validate.")
+// Text writes the validations in the emoji format of the pre-req command.
+func (r Results) Text(w io.Writer) {
+	if len(r) == 0 {
+		fmt.Fprintln(w, "❌ No applications found in the list to validate.")
 		return
 	}
 
-	// Temporarily replace the app list
-	originalApps := av.apps
-	av.apps = appsToValidate
-	av.ValidateApps()
-	av.apps = originalApps
+	for _, app := range r {
+		fmt.Fprintf(w, "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+		fmt.Fprintf(w, "💾 %s\n", app.Name)
+
+		switch {
+		case app.TimedOut:
+			fmt.Fprintf(w, "⏱️  Status: TIMED OUT\n")
+			fmt.Fprintf(w, "⚠️  %s\n", app.VersionError)
+		case len(app.BlockedBy) > 0:
+			fmt.Fprintf(w, "⛔ Status: BLOCKED\n")
+			fmt.Fprintf(w, "⚠️  Requires %s, which is not ready\n", strings.Join(app.BlockedBy, ", "))
+		case app.IsInstalled:
+			switch app.Health {
+			case Healthy:
+				fmt.Fprintf(w, "✅ Status: HEALTHY\n")
+			case Unhealthy:
+				fmt.Fprintf(w, "🩺 Status: UNHEALTHY\n")
+			default:
+				fmt.Fprintf(w, "✅ Status: INSTALLED\n")
+			}
+			fmt.Fprintf(w, "📦 Version: %s\n", app.CurrentVersion)
+			fmt.Fprintf(w, "🎯 Recommended: %s\n", app.RecommendedVersion)
+			switch {
+			case app.VersionError != "":
+				fmt.Fprintf(w, "⚠️  Version check failed: %s\n", app.VersionError)
+			case !app.IsUpToDate:
+				fmt.Fprintf(w, "⚠️  Outdated: %s does not satisfy %s\n", app.CurrentVersion, app.RecommendedVersion)
+			}
+			if app.Health == Unhealthy {
+				fmt.Fprintf(w, "⚠️  Health check failed: %s\n", app.HealthError)
+			}
+		default:
+			fmt.Fprintf(w, "❌ Status: NOT INSTALLED\n")
+			fmt.Fprintf(w, "🎯 Recommended Version: %s\n", app.RecommendedVersion)
+			fmt.Fprintf(w, "📥 Installation Instructions:\n")
+
+			if app.InstallInstruction != "" {
+				fmt.Fprintf(w, "   %s\n", app.InstallInstruction)
+			} else {
+				fmt.Fprintf(w, "   Instructions not available for %s\n", osName(app.OS))
+			}
+		}
+
+		fmt.Fprintf(w, "\n")
+	}
+}
+
+// Table lays the validations out one application per row.
+func (r Results) Table() output.Table {
+	t := output.Table{Header: []string{"APP", "COMMAND", "STATUS", "VERSION", "RECOMMENDED"}}
+	for _, app := range r {
+		status, version := "not installed", "-"
+		switch {
+		case app.TimedOut:
+			status = "timed out"
+		case len(app.BlockedBy) > 0:
+			status = "blocked"
+		case app.IsInstalled && app.Health == Unhealthy:
+			status, version = "unhealthy", app.CurrentVersion
+		case app.IsInstalled && app.IsUpToDate && app.Health == Healthy:
+			status, version = "healthy", app.CurrentVersion
+		case app.IsInstalled && app.IsUpToDate:
+			status, version = "installed", app.CurrentVersion
+		case app.IsInstalled && app.VersionError != "":
+			status, version = "unknown version", app.CurrentVersion
+		case app.IsInstalled:
+			status, version = "outdated", app.CurrentVersion
+		}
+		t.Rows = append(t.Rows, []string{app.Name, app.Command, status, version, app.RecommendedVersion})
+	}
+	return t
 }
 
-// ListAvailableApps lists all applications available for validation.
-func (av *AppValidator) ListAvailableApps() {
-	fmt.Printf("📋 Available applications for validation:\n")
-	fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+// Text writes the numbered list of applications.
+func (l AppList) Text(w io.Writer) {
+	fmt.Fprintf(w, "📋 Available applications for validation:\n")
+	fmt.Fprintf(w, "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+
+	for i, app := range l {
+		fmt.Fprintf(w, "%d. %s (command: %s)\n", i+1, app.Name, app.Command)
+	}
+	fmt.Fprintf(w, "\n")
+}
 
-	for i, app := range av.apps {
-		fmt.Printf("%d. %s (command: %s)\n", i+1, app.Name, app.Command)
+// Table lays the applications out one per row.
+func (l AppList) Table() output.Table {
+	t := output.Table{Header: []string{"APP", "COMMAND", "RECOMMENDED"}}
+	for _, app := range l {
+		t.Rows = append(t.Rows, []string{app.Name, app.Command, app.RecommendedVersion})
 	}
-	fmt.Printf("\n")
+	return t
 }
 
-func (av *AppValidator) getOSName() string {
-	switch av.os {
+// osName returns a human-readable name for a GOOS value.
+func osName(goos string) string {
+	switch goos {
 	case "darwin":
 		return "macOS"
 	case "linux":
@@ -151,14 +381,24 @@ func (av *AppValidator) getOSName() string {
 	case "windows":
 		return "Windows"
 	default:
-		return av.os
+		return goos
 	}
 }
 
 // checkAppInstalled checks if a command-line application is installed and retrieves its version.
+// When the version command does not finish within the timeout of the application, it is killed
+// and the error wraps context.DeadlineExceeded.
 func (av *AppValidator) checkAppInstalled(app utils.AppInfo) (bool, string, error) {
-	cmd := exec.Command(app.Command, app.VersionFlag)
-	output, err := cmd.Output()
+	timeout := av.timeoutOf(app)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// The version flag may hold several arguments, such as "version --client".
+	args := strings.Fields(app.VersionFlag)
+	output, err := commandContext(ctx, app.Command, args...).Output()
+	if ctx.Err() != nil {
+		return false, "", fmt.Errorf("%s did not answer within %s: %w", commandLine(app.Command, args...), timeout, ctx.Err())
+	}
 	if err != nil {
 		return false, "", err
 	}
@@ -167,3 +407,67 @@ func (av *AppValidator) checkAppInstalled(app utils.AppInfo) (bool, string, erro
 
 	return true, version, nil
 }
+
+// checkHealth runs the health check of app, with the timeout of app.
+func (av *AppValidator) checkHealth(app utils.AppInfo) error {
+	check := app.HealthCheck
+	line := commandLine(check.Command, check.Args...)
+
+	timeout := av.timeoutOf(app)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	output, err := commandContext(ctx, check.Command, check.Args...).CombinedOutput()
+	if ctx.Err() != nil {
+		return fmt.Errorf("%s did not answer within %s", line, timeout)
+	}
+	if err != nil {
+		if last := lastLine(string(output)); last != "" {
+			return fmt.Errorf("%s failed: %w: %s", line, err, last)
+		}
+		return fmt.Errorf("%s failed: %w", line, err)
+	}
+
+	if check.Expect == "" {
+		return nil
+	}
+	re, err := regexp.Compile(check.Expect)
+	if err != nil {
+		return fmt.Errorf("invalid expect pattern %q: %w", check.Expect, err)
+	}
+	if !re.Match(output) {
+		return fmt.Errorf("output of %s does not match %q", line, check.Expect)
+	}
+	return nil
+}
+
+// commandContext returns the command running name with args until ctx is done.
+func commandContext(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	// Do not wait for children of the command that keep its output open once it is killed.
+	cmd.WaitDelay = time.Second
+	return cmd
+}
+
+// commandLine returns name and args as they would be typed, for messages.
+func commandLine(name string, args ...string) string {
+	return strings.TrimSpace(name + " " + strings.Join(args, " "))
+}
+
+// lastLine returns the last non-empty line of output, where commands usually print their error.
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// timeoutOf returns the timeout of app, falling back to the timeout of the validator and then
+// DefaultTimeout when it has none or it is not a valid duration.
+func (av *AppValidator) timeoutOf(app utils.AppInfo) time.Duration {
+	if d, err := time.ParseDuration(app.Timeout); err == nil && d > 0 {
+		return d
+	}
+	if av.timeout > 0 {
+		return av.timeout
+	}
+	return DefaultTimeout
+}
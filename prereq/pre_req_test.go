@@ -1,14 +1,40 @@
 package prereq
 
 import (
+	"os"
+	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/fabianoflorentino/whiterose/exitcode"
+	"github.com/fabianoflorentino/whiterose/internal/interfaces"
 	"github.com/fabianoflorentino/whiterose/utils"
 )
 
 func TestAppValidator_New(t *testing.T) {
-	av := NewAppValidator()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("WHITEROSE_CONFIG", filepath.Join(t.TempDir(), "missing.yaml"))
+
+	av, err := NewAppValidator()
+	if err != nil {
+		t.Fatalf("NewAppValidator() error = %v", err)
+	}
+	if len(av.Apps()) == 0 {
+		t.Error("NewAppValidator() has no applications, want the catalog defaults")
+	}
+
+	broken := filepath.Join(t.TempDir(), "whiterose.yaml")
+	if err := os.WriteFile(broken, []byte("workers: [\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("WHITEROSE_CONFIG", broken)
+	av, err = NewAppValidator()
+	if exitcode.Of(err) != exitcode.Config {
+		t.Errorf("NewAppValidator() with a broken configuration error = %v, want exit code %d", err, exitcode.Config)
+	}
 	if av == nil {
 		t.Error("NewAppValidator() returned nil")
 	}
@@ -35,7 +61,7 @@ func TestAppValidator_AddApp(t *testing.T) {
 	}
 }
 
-func TestAppValidator_getOSName(t *testing.T) {
+func TestOSName(t *testing.T) {
 	tests := []struct {
 		name string
 		os   string
@@ -49,77 +75,241 @@ func TestAppValidator_getOSName(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			av := &AppValidator{os: tt.os}
-			if got := av.getOSName(); got != tt.want {
-				t.Errorf("getOSName() = %v, want %v", got, tt.want)
+			if got := osName(tt.os); got != tt.want {
+				t.Errorf("osName(%s) = %v, want %v", tt.os, got, tt.want)
 			}
 		})
 	}
 }
 
-func TestAppValidator_ValidateApps_Empty(t *testing.T) {
+func TestCheckAppInstalled_NotFound(t *testing.T) {
 	av := &AppValidator{
 		apps: []utils.AppInfo{},
 		os:   runtime.GOOS,
 	}
 
-	av.ValidateApps()
+	_, _, err := av.checkAppInstalled(utils.AppInfo{Command: "nonexistent-cmd"})
+	if err == nil {
+		t.Error("expected error for nonexistent command")
+	}
 }
 
-func TestAppValidator_ValidateSpecificApps_NoApps(t *testing.T) {
+func TestCheckAppInstalled_Found(t *testing.T) {
+	if testing.Short() {
+		t.Skip("requires system commands")
+	}
+
 	av := &AppValidator{
 		apps: []utils.AppInfo{},
 		os:   runtime.GOOS,
 	}
 
-	av.ValidateSpecificApps([]string{"nonexistent"})
+	_, _, err := av.checkAppInstalled(utils.AppInfo{Command: "go", VersionFlag: "version"})
+	if err != nil {
+		t.Logf("go may not be installed: %v", err)
+	}
 }
 
-func TestAppValidator_ListAvailableApps_Empty(t *testing.T) {
+func TestAppValidator_Check(t *testing.T) {
 	av := &AppValidator{
-		apps: []utils.AppInfo{},
-		os:   runtime.GOOS,
+		apps: []utils.AppInfo{
+			{Name: "Echo", Command: "echo", VersionFlag: "v1.2.3", RecommendedVersion: "1.2.3"},
+			{Name: "Missing", Command: "nonexistent-cmd", RecommendedVersion: "2.0", InstallInstructions: map[string]string{"linux": "apt install missing"}},
+		},
+		os: "linux",
+	}
+
+	results := av.Check()
+	if len(results) != 2 {
+		t.Fatalf("len(Check()) = %d, want 2", len(results))
+	}
+	if got := results[0]; !got.IsInstalled || got.CurrentVersion != "1.2.3" || !got.IsUpToDate || got.OS != "linux" {
+		t.Errorf("Check()[0] = %+v", got)
+	}
+	if got := results[1]; got.IsInstalled || got.InstallInstruction != "apt install missing" {
+		t.Errorf("Check()[1] = %+v", got)
+	}
+
+	if got := av.CheckSpecific([]string{"ECHO", "unknown"}); len(got) != 1 || got[0].Name != "Echo" {
+		t.Errorf("CheckSpecific() = %+v", got)
+	}
+	if got := av.CheckSpecific([]string{"unknown"}); got == nil || len(got) != 0 {
+		t.Errorf("CheckSpecific() = %#v, want an empty non-nil result", got)
+	}
+
+	var out strings.Builder
+	results.Text(&out)
+	for _, want := range []string{"💾 Echo", "✅ Status: INSTALLED", "💾 Missing", "❌ Status: NOT INSTALLED", "   apt install missing"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Text() missing %q:\n%s", want, out.String())
+		}
 	}
 
-	av.ListAvailableApps()
+	rows := results.Table().Rows
+	if len(rows) != 2 || rows[0][2] != "installed" || rows[1][2] != "not installed" || rows[1][3] != "-" {
+		t.Errorf("Table().Rows = %v", rows)
+	}
 }
 
-func TestAppValidator_ListAvailableApps_WithApps(t *testing.T) {
-	av := &AppValidator{
+func TestCheckAppInstalled_VersionFlagArguments(t *testing.T) {
+	installed, out, err := (&AppValidator{}).checkAppInstalled(utils.AppInfo{Command: "echo", VersionFlag: "version  --client"})
+	if !installed || err != nil || out != "version --client" {
+		t.Errorf("checkAppInstalled() = %v, %q, %v, want the flag passed as two arguments", installed, out, err)
+	}
+}
+
+func TestAppValidator_Check_Versions(t *testing.T) {
+	tests := []struct {
+		name         string
+		app          utils.AppInfo
+		wantVersion  string
+		wantUpToDate bool
+		wantError    bool
+	}{
+		{"no recommendation", utils.AppInfo{VersionFlag: "tool 0.1"}, "0.1.0", true, false},
+		{"satisfied", utils.AppInfo{VersionFlag: "go version go1.25.3 linux/amd64", RecommendedVersion: ">=1.25"}, "1.25.3", true, false},
+		{"outdated", utils.AppInfo{VersionFlag: "git version 2.39.5", RecommendedVersion: "~2.40"}, "2.39.5", false, false},
+		{"version regex", utils.AppInfo{VersionFlag: "build 20 version 3.4.1", VersionRegex: `version (\S+)`, RecommendedVersion: "^3"}, "3.4.1", true, false},
+		{"no version in output", utils.AppInfo{VersionFlag: "unknown", RecommendedVersion: "1.0"}, "unknown", false, true},
+		{"invalid constraint", utils.AppInfo{VersionFlag: "1.0.0", RecommendedVersion: "latest"}, "1.0.0", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.app.Name, tt.app.Command = "Echo", "echo"
+			got := (&AppValidator{os: "linux"}).validate(tt.app)
+			if !got.IsInstalled || got.CurrentVersion != tt.wantVersion || got.IsUpToDate != tt.wantUpToDate || (got.VersionError != "") != tt.wantError {
+				t.Errorf("validate() = %+v", got)
+			}
+		})
+	}
+}
+
+func TestAppValidator_Check_Timeout(t *testing.T) {
+	av := (&AppValidator{
 		apps: []utils.AppInfo{
-			{Name: "Go", Command: "go"},
-			{Name: "Git", Command: "git"},
+			{Name: "Slow", Command: "sleep", VersionFlag: "5", Timeout: "100ms"},
+			{Name: "Hung", Command: "sleep", VersionFlag: "5"},
+			{Name: "Hung too", Command: "sleep", VersionFlag: "5"},
+			{Name: "Echo", Command: "echo", VersionFlag: "1.0.0"},
+			{Name: "Missing", Command: "nonexistent-cmd"},
 		},
-		os: runtime.GOOS,
+		os: "linux",
+	}).WithTimeout(300 * time.Millisecond)
+
+	start := time.Now()
+	results := av.Check()
+	// The two hung applications time out together, not one after the other.
+	if elapsed := time.Since(start); elapsed > 550*time.Millisecond {
+		t.Errorf("Check() took %v, want the applications checked concurrently", elapsed)
+	}
+
+	want := []struct {
+		name      string
+		timedOut  bool
+		installed bool
+	}{
+		{"Slow", true, false},
+		{"Hung", true, false},
+		{"Hung too", true, false},
+		{"Echo", false, true},
+		{"Missing", false, false},
+	}
+	for i, w := range want {
+		if got := results[i]; got.Name != w.name || got.TimedOut != w.timedOut || got.IsInstalled != w.installed {
+			t.Errorf("Check()[%d] = %+v, want %s timed out %v, installed %v", i, got, w.name, w.timedOut, w.installed)
+		}
+	}
+	if results[0].VersionError != "sleep 5 did not answer within 100ms: context deadline exceeded" {
+		t.Errorf("VersionError = %q", results[0].VersionError)
+	}
+
+	if missing := av.Missing(results); len(missing) != 1 || missing[0].Name != "Missing" {
+		t.Errorf("Missing() = %+v, want only Missing", missing)
 	}
 
-	av.ListAvailableApps()
+	var out strings.Builder
+	results.Text(&out)
+	if !strings.Contains(out.String(), "💾 Hung\n⏱️  Status: TIMED OUT\n⚠️  sleep 5 did not answer within 300ms") {
+		t.Errorf("Text() =\n%s", out.String())
+	}
+	if rows := results.Table().Rows; rows[0][2] != "timed out" || rows[4][2] != "not installed" {
+		t.Errorf("Table().Rows = %v", rows)
+	}
 }
 
-func TestCheckAppInstalled_NotFound(t *testing.T) {
-	av := &AppValidator{
-		apps: []utils.AppInfo{},
-		os:   runtime.GOOS,
+func TestAppValidator_timeoutOf(t *testing.T) {
+	tests := []struct {
+		name      string
+		validator time.Duration
+		app       string
+		want      time.Duration
+	}{
+		{"default", 0, "", DefaultTimeout},
+		{"validator", 3 * time.Second, "", 3 * time.Second},
+		{"application", 3 * time.Second, "500ms", 500 * time.Millisecond},
+		{"invalid application timeout", 3 * time.Second, "soon", 3 * time.Second},
 	}
 
-	_, _, err := av.checkAppInstalled(utils.AppInfo{Command: "nonexistent-cmd"})
-	if err == nil {
-		t.Error("expected error for nonexistent command")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			av := (&AppValidator{}).WithTimeout(tt.validator)
+			if got := av.timeoutOf(utils.AppInfo{Timeout: tt.app}); got != tt.want {
+				t.Errorf("timeoutOf() = %v, want %v", got, tt.want)
+			}
+		})
 	}
 }
 
-func TestCheckAppInstalled_Found(t *testing.T) {
-	if testing.Short() {
-		t.Skip("requires system commands")
+func TestAppValidator_Missing(t *testing.T) {
+	av := &AppValidator{apps: []utils.AppInfo{{Name: "Go", Command: "go"}, {Name: "Git", Command: "git"}, {Name: "jq", Command: "jq"}, {Name: "Lint", Command: "golangci-lint"}}}
+
+	missing := av.Missing(Results{{Name: "Go"}, {Name: "Git", IsInstalled: true}, {Name: "jq"}, {Name: "Lint", BlockedBy: []string{"Go"}}})
+	if len(missing) != 2 || missing[0].Command != "go" || missing[1].Command != "jq" {
+		t.Errorf("Missing() = %+v, want Go and jq", missing)
 	}
+}
 
-	av := &AppValidator{
-		apps: []utils.AppInfo{},
-		os:   runtime.GOOS,
+func TestAppList_Text(t *testing.T) {
+	av := &AppValidator{apps: []utils.AppInfo{{Name: "Go", Command: "go"}, {Name: "Git", Command: "git"}}}
+
+	var out strings.Builder
+	av.Apps().Text(&out)
+	if !strings.Contains(out.String(), "1. Go (command: go)\n2. Git (command: git)\n") {
+		t.Errorf("Text() =\n%s", out.String())
 	}
+}
 
-	_, _, err := av.checkAppInstalled(utils.AppInfo{Command: "go", VersionFlag: "version"})
-	if err != nil {
-		t.Logf("go may not be installed: %v", err)
+func TestResults_Err(t *testing.T) {
+	outdated := interfaces.AppValidation{Name: "Git", IsInstalled: true, CurrentVersion: "2.39.5", RecommendedVersion: "~2.40"}
+
+	tests := []struct {
+		name    string
+		results Results
+		strict  bool
+		want    string
+	}{
+		{"all installed", Results{{Name: "Git", IsInstalled: true, IsUpToDate: true}}, true, ""},
+		{"missing", Results{{Name: "Git", IsInstalled: true}, {Name: "Go"}, {Name: "Docker"}}, false, "missing prerequisites: Go, Docker"},
+		{"outdated is not an error", Results{outdated}, false, ""},
+		{"outdated in strict mode", Results{outdated}, true, "outdated prerequisites: Git 2.39.5 (requires ~2.40)"},
+		{"missing and outdated", Results{outdated, {Name: "Go"}}, true, "missing prerequisites: Go; outdated prerequisites: Git 2.39.5 (requires ~2.40)"},
+		{"timed out", Results{{Name: "Docker", TimedOut: true}, {Name: "Go"}}, false, "missing prerequisites: Go; timed out prerequisites: Docker"},
+		{"blocked and unhealthy", Results{{Name: "Docker", IsInstalled: true, IsUpToDate: true, Health: Unhealthy}, {Name: "Compose", BlockedBy: []string{"Docker"}}}, false, "blocked prerequisites: Compose (requires Docker); unhealthy prerequisites: Docker"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.results.Err(tt.strict)
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("Err() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.want || exitcode.Of(err) != exitcode.Prerequisite {
+				t.Errorf("Err() = %v (exit %d), want %q with the prerequisite status", err, exitcode.Of(err), tt.want)
+			}
+		})
 	}
 }
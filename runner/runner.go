@@ -0,0 +1,249 @@
+// Package runner runs an arbitrary shell command in every configured repository,
+// optionally in parallel, prefixing each output line with the repository it came from.
+package runner
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fabianoflorentino/whiterose/internal/interfaces"
+	"github.com/fabianoflorentino/whiterose/internal/services"
+	"github.com/fabianoflorentino/whiterose/output"
+)
+
+// Target is a directory the command runs in.
+type Target struct {
+	Name      string
+	Directory string
+}
+
+// Result holds the outcome of running the command in one target.
+type Result struct {
+	Target   Target
+	ExitCode int
+	Output   string
+	Skipped  bool
+	Err      error
+	Duration time.Duration
+}
+
+// Runner fans a shell command out to a set of targets.
+type Runner struct {
+	executor interfaces.Executor
+	shell    string
+	parallel int
+	out      io.Writer
+}
+
+// New creates a Runner executing commands sequentially through "sh" and printing to stdout.
+func New() *Runner {
+	return &Runner{
+		executor: services.NewExecutorService(),
+		shell:    "sh",
+		parallel: 1,
+		out:      os.Stdout,
+	}
+}
+
+// WithExecutor replaces the executor used to run commands.
+func (r *Runner) WithExecutor(exec interfaces.Executor) *Runner {
+	r.executor = exec
+	return r
+}
+
+// WithParallel sets how many targets run at the same time; values below 1 mean sequential.
+func (r *Runner) WithParallel(n int) *Runner {
+	if n < 1 {
+		n = 1
+	}
+	r.parallel = n
+	return r
+}
+
+// WithOutput sets where the prefixed output is written.
+func (r *Runner) WithOutput(w io.Writer) *Runner {
+	r.out = w
+	return r
+}
+
+// Run executes command in every target directory and returns one result per target, in order.
+// Targets whose directory does not exist are skipped. The output of each target is written
+// as a block once it finishes, with every line prefixed by the target name.
+func (r *Runner) Run(targets []Target, command string) []Result {
+	results := make([]Result, len(targets))
+	jobs := make(chan int)
+	mu := &sync.Mutex{}
+
+	var wg sync.WaitGroup
+	for w := 0; w < min(r.parallel, max(len(targets), 1)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				res := r.runOne(targets[i], command)
+				results[i] = res
+
+				mu.Lock()
+				r.writeResult(res)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := range targets {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// runOne runs command inside target's directory. The directory and the command are passed as
+// positional parameters so neither needs shell quoting.
+func (r *Runner) runOne(target Target, command string) Result {
+	res := Result{Target: target}
+
+	if fi, err := os.Stat(target.Directory); err != nil || !fi.IsDir() {
+		res.Skipped = true
+		res.ExitCode = -1
+		res.Err = fmt.Errorf("directory %s does not exist", target.Directory)
+		return res
+	}
+
+	start := time.Now()
+	out, err := r.executor.Run(r.shell, "-c", `cd "$0" && eval "$1"`, target.Directory, command)
+	res.Duration = time.Since(start).Round(time.Millisecond)
+	res.Output = out
+	res.ExitCode = exitCode(err)
+
+	// Report the exit status of the command rather than the shell wrapping it.
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		err = exitErr
+	}
+	res.Err = err
+
+	return res
+}
+
+// writeResult prints the output of a finished target, prefixing each line with its name.
+func (r *Runner) writeResult(res Result) {
+	prefix := fmt.Sprintf("[%s] ", res.Target.Name)
+
+	if res.Skipped {
+		fmt.Fprintf(r.out, "%sskipped: %v\n", prefix, res.Err)
+		return
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(res.Output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		fmt.Fprintf(r.out, "%s%s\n", prefix, scanner.Text())
+	}
+}
+
+// Summary is the outcome of a run, one result per target in order.
+type Summary []Result
+
+// Text writes the summary table followed by the count of each outcome.
+func (s Summary) Text(w io.Writer) {
+	_ = output.WriteTable(w, s.Table())
+
+	ok, failed, skipped := Counts(s)
+	fmt.Fprintf(w, "\n%d succeeded, %d failed, %d skipped, %d total\n", ok, failed, skipped, len(s))
+}
+
+// Table lays the results out one target per row.
+func (s Summary) Table() output.Table {
+	t := output.Table{Header: []string{"REPOSITORY", "DIRECTORY", "EXIT", "DURATION"}}
+	for _, res := range s {
+		code := fmt.Sprint(res.ExitCode)
+		if res.Skipped {
+			code = "skipped"
+		}
+		t.Rows = append(t.Rows, []string{res.Target.Name, res.Target.Directory, code, res.Duration.String()})
+	}
+	return t
+}
+
+// MarshalJSON encodes the result with its error as a message and its duration as a string.
+func (res Result) MarshalJSON() ([]byte, error) {
+	var errMsg string
+	if res.Err != nil {
+		errMsg = res.Err.Error()
+	}
+	return json.Marshal(struct {
+		Name      string `json:"name"`
+		Directory string `json:"directory"`
+		ExitCode  int    `json:"exitCode"`
+		Skipped   bool   `json:"skipped"`
+		Duration  string `json:"duration"`
+		Output    string `json:"output"`
+		Error     string `json:"error,omitempty"`
+	}{res.Target.Name, res.Target.Directory, res.ExitCode, res.Skipped, res.Duration.String(), res.Output, errMsg})
+}
+
+// Counts returns the number of succeeded, failed and skipped results.
+func Counts(results []Result) (ok, failed, skipped int) {
+	for _, res := range results {
+		switch {
+		case res.Skipped:
+			skipped++
+		case res.ExitCode == 0:
+			ok++
+		default:
+			failed++
+		}
+	}
+	return ok, failed, skipped
+}
+
+// CommandLine returns the shell command line of the arguments given after "--". A single
+// argument is a command line of its own, such as "make test | tee test.log"; several arguments
+// are quoted so that each reaches the command unchanged, as in sh -c 'echo a b'.
+func CommandLine(args []string) string {
+	if len(args) == 1 {
+		return args[0]
+	}
+
+	quoted := make([]string, 0, len(args))
+	for _, arg := range args {
+		quoted = append(quoted, shellQuote(arg))
+	}
+	return strings.Join(quoted, " ")
+}
+
+// safeWord matches the arguments the shell reads literally without quotes.
+var safeWord = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+// shellQuote quotes arg for sh, leaving plain words as they are.
+func shellQuote(arg string) string {
+	if safeWord.MatchString(arg) {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// exitCode extracts the process exit status from err; errors that are not exit errors map to 1.
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+
+	return 1
+}
@@ -0,0 +1,158 @@
+package runner
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fabianoflorentino/whiterose/mocks"
+)
+
+func TestRunner_Run(t *testing.T) {
+	dirA := t.TempDir()
+	dirB := t.TempDir()
+
+	var calls [][]string
+	mock := &mocks.MockExecutor{
+		RunFunc: func(cmd string, args ...string) (string, error) {
+			calls = append(calls, append([]string{cmd}, args...))
+			if args[2] == dirB {
+				return "boom\n", errors.New("exit status 3")
+			}
+			return "line1\nline2\n", nil
+		},
+	}
+
+	targets := []Target{
+		{Name: "a", Directory: dirA},
+		{Name: "b", Directory: dirB},
+		{Name: "c", Directory: filepath.Join(dirA, "missing")},
+	}
+
+	var out bytes.Buffer
+	results := New().WithExecutor(mock).WithOutput(&out).Run(targets, "make test")
+
+	if len(calls) != 2 {
+		t.Fatalf("executor called %d times, want 2", len(calls))
+	}
+	if calls[0][0] != "sh" || calls[0][1] != "-c" || calls[0][3] != dirA || calls[0][4] != "make test" {
+		t.Errorf("unexpected call %v", calls[0])
+	}
+
+	if results[0].ExitCode != 0 || results[1].ExitCode != 1 || !results[2].Skipped {
+		t.Errorf("unexpected results: %+v", results)
+	}
+
+	for _, want := range []string{"[a] line1\n", "[a] line2\n", "[b] boom\n", "[c] skipped"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	ok, failed, skipped := Counts(results)
+	if ok != 1 || failed != 1 || skipped != 1 {
+		t.Errorf("Counts() = %d, %d, %d, want 1, 1, 1", ok, failed, skipped)
+	}
+}
+
+func TestRunner_Parallel(t *testing.T) {
+	var targets []Target
+	for i := 0; i < 5; i++ {
+		targets = append(targets, Target{Name: "r", Directory: t.TempDir()})
+	}
+
+	results := New().WithExecutor(&mocks.MockExecutor{}).WithParallel(3).WithOutput(&bytes.Buffer{}).Run(targets, "true")
+	if len(results) != 5 {
+		t.Fatalf("len(results) = %d, want 5", len(results))
+	}
+	for i, res := range results {
+		if res.Target != targets[i] {
+			t.Errorf("results[%d] out of order", i)
+		}
+	}
+}
+
+func TestRunner_RealExitCode(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	dir := t.TempDir()
+	var out bytes.Buffer
+	results := New().WithOutput(&out).Run([]Target{{Name: "repo", Directory: dir}}, "pwd; exit 3")
+
+	if results[0].ExitCode != 3 {
+		t.Errorf("ExitCode = %d, want 3", results[0].ExitCode)
+	}
+	if !strings.Contains(out.String(), "[repo] "+dir) {
+		t.Errorf("output = %q, want pwd of %s", out.String(), dir)
+	}
+}
+
+func TestSummary_Text(t *testing.T) {
+	results := Summary{
+		{Target: Target{Name: "a", Directory: "a"}, ExitCode: 0},
+		{Target: Target{Name: "b", Directory: "b"}, ExitCode: 2},
+		{Target: Target{Name: "c", Directory: "c"}, Skipped: true},
+	}
+
+	var out bytes.Buffer
+	results.Text(&out)
+
+	if !strings.Contains(out.String(), "1 succeeded, 1 failed, 1 skipped, 3 total") {
+		t.Errorf("unexpected summary:\n%s", out.String())
+	}
+}
+
+func TestResult_MarshalJSON(t *testing.T) {
+	data, err := json.Marshal(Summary{
+		{Target: Target{Name: "api", Directory: "src/api"}, Output: "ok\n", Duration: 250 * time.Millisecond},
+		{Target: Target{Name: "web", Directory: "src/web"}, Skipped: true, ExitCode: -1, Err: errors.New("directory src/web does not exist")},
+	})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	want := `[{"name":"api","directory":"src/api","exitCode":0,"skipped":false,"duration":"250ms","output":"ok\n"},` +
+		`{"name":"web","directory":"src/web","exitCode":-1,"skipped":true,"duration":"0s","output":"","error":"directory src/web does not exist"}]`
+	if string(data) != want {
+		t.Errorf("Marshal() =\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestCommandLine(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"make", "test"}, "make test"},
+		{[]string{"git log -1 | cat"}, "git log -1 | cat"},
+		{[]string{"sh", "-c", "echo a b"}, `sh -c 'echo a b'`},
+		{[]string{"echo", "it's", ""}, `echo 'it'\''s' ''`},
+		{[]string{"go", "test", "./..."}, "go test ./..."},
+	}
+
+	for _, tt := range tests {
+		if got := CommandLine(tt.args); got != tt.want {
+			t.Errorf("CommandLine(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestRunner_KeepsArguments(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	var out bytes.Buffer
+	results := New().WithOutput(&out).Run([]Target{{Name: "repo", Directory: t.TempDir()}}, CommandLine([]string{"sh", "-c", "echo a  b"}))
+
+	if results[0].ExitCode != 0 || out.String() != "[repo] a b\n" {
+		t.Errorf("Run() = %+v, output %q, want [repo] a b", results[0], out.String())
+	}
+}
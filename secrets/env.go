@@ -0,0 +1,31 @@
+package secrets
+
+import (
+	"fmt"
+	"os"
+)
+
+// EnvPrefix is prepended to the normalised secret name to form the environment variable.
+const EnvPrefix = "WHITEROSE_SECRET_"
+
+// EnvProvider reads secrets from environment variables, e.g. "secret://gitlab-token"
+// from WHITEROSE_SECRET_GITLAB_TOKEN.
+type EnvProvider struct{}
+
+// NewEnvProvider creates an EnvProvider.
+func NewEnvProvider() *EnvProvider {
+	return &EnvProvider{}
+}
+
+func (p *EnvProvider) Name() string {
+	return "env"
+}
+
+func (p *EnvProvider) Get(name string) (string, error) {
+	key := EnvPrefix + normalizeName(name)
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value, nil
+	}
+
+	return "", fmt.Errorf("%s is not set: %w", key, ErrNotFound)
+}
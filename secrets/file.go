@@ -0,0 +1,261 @@
+package secrets
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/term"
+)
+
+// PassphraseEnv holds the passphrase of the secrets file for non-interactive runs.
+const PassphraseEnv = "WHITEROSE_SECRETS_PASSPHRASE"
+
+// scrypt parameters used to derive the file key from the passphrase.
+const (
+	scryptN     = 1 << 15
+	scryptR     = 8
+	scryptP     = 1
+	fileVersion = 1
+	saltSize    = 16
+)
+
+// PromptPassphrase asks for the passphrase of the secrets file. It reads WHITEROSE_SECRETS_PASSPHRASE
+// when set and otherwise prompts on the terminal. It is a variable so tests can replace it.
+var PromptPassphrase = func(path string) (string, error) {
+	if p := os.Getenv(PassphraseEnv); p != "" {
+		return p, nil
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("secrets file %s is encrypted, set %s", path, PassphraseEnv)
+	}
+
+	fmt.Fprintf(os.Stderr, "Enter passphrase for %s: ", path)
+	passphrase, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase: %w", err)
+	}
+
+	return string(passphrase), nil
+}
+
+// DefaultFilePath returns WHITEROSE_SECRETS_FILE, falling back to ~/.config/whiterose/secrets.enc.
+func DefaultFilePath() string {
+	if path := os.Getenv("WHITEROSE_SECRETS_FILE"); path != "" {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "secrets.enc"
+	}
+
+	return filepath.Join(home, ".config", "whiterose", "secrets.enc")
+}
+
+// encryptedFile is the on-disk format: the secrets map encoded as JSON and sealed with
+// XChaCha20-Poly1305 under a key derived from the passphrase with scrypt.
+type encryptedFile struct {
+	Version int    `json:"version"`
+	Salt    []byte `json:"salt"`
+	Nonce   []byte `json:"nonce"`
+	Data    []byte `json:"data"`
+}
+
+// FileProvider reads and writes secrets in a passphrase-encrypted file. The passphrase is asked
+// once and the decrypted secrets are kept in memory for the lifetime of the provider.
+type FileProvider struct {
+	path       string
+	mu         sync.Mutex
+	passphrase string
+	secrets    map[string]string
+}
+
+// NewFileProvider creates a FileProvider for the file at path.
+func NewFileProvider(path string) *FileProvider {
+	return &FileProvider{path: path}
+}
+
+func (p *FileProvider) Name() string {
+	return "file"
+}
+
+func (p *FileProvider) Get(name string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, err := os.Stat(p.path); errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("%s does not exist: %w", p.path, ErrNotFound)
+	}
+
+	if err := p.load(); err != nil {
+		return "", err
+	}
+
+	value, ok := p.secrets[name]
+	if !ok {
+		return "", fmt.Errorf("%q is not in %s: %w", name, p.path, ErrNotFound)
+	}
+
+	return value, nil
+}
+
+// Set stores a secret, creating the file when it does not exist yet.
+func (p *FileProvider) Set(name, value string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, err := os.Stat(p.path); err == nil {
+		if err := p.load(); err != nil {
+			return err
+		}
+	} else if p.secrets == nil {
+		p.secrets = make(map[string]string)
+	}
+
+	p.secrets[name] = value
+
+	return p.save()
+}
+
+// Names returns the names of the stored secrets, sorted.
+func (p *FileProvider) Names() ([]string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, err := os.Stat(p.path); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err := p.load(); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(p.secrets))
+	for name := range p.secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// load decrypts the file into memory unless it was already loaded.
+func (p *FileProvider) load() error {
+	if p.secrets != nil {
+		return nil
+	}
+
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", p.path, err)
+	}
+
+	var ef encryptedFile
+	if err := json.Unmarshal(data, &ef); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", p.path, err)
+	}
+	if ef.Version != fileVersion {
+		return fmt.Errorf("unsupported secrets file version %d in %s", ef.Version, p.path)
+	}
+
+	if err := p.ensurePassphrase(); err != nil {
+		return err
+	}
+
+	aead, err := newAEAD(p.passphrase, ef.Salt)
+	if err != nil {
+		return err
+	}
+
+	plain, err := aead.Open(nil, ef.Nonce, ef.Data, nil)
+	if err != nil {
+		p.passphrase = ""
+		return fmt.Errorf("failed to decrypt %s: wrong passphrase or corrupted file", p.path)
+	}
+
+	secrets := make(map[string]string)
+	if err := json.Unmarshal(plain, &secrets); err != nil {
+		return fmt.Errorf("failed to decode secrets in %s: %w", p.path, err)
+	}
+	p.secrets = secrets
+
+	return nil
+}
+
+// save encrypts the in-memory secrets with a fresh salt and nonce and writes them to the file.
+func (p *FileProvider) save() error {
+	if err := p.ensurePassphrase(); err != nil {
+		return err
+	}
+
+	plain, err := json.Marshal(p.secrets)
+	if err != nil {
+		return fmt.Errorf("failed to encode secrets: %w", err)
+	}
+
+	ef := encryptedFile{Version: fileVersion, Salt: make([]byte, saltSize), Nonce: make([]byte, chacha20poly1305.NonceSizeX)}
+	if _, err := rand.Read(ef.Salt); err != nil {
+		return fmt.Errorf("failed to generate salt: %w", err)
+	}
+	if _, err := rand.Read(ef.Nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	aead, err := newAEAD(p.passphrase, ef.Salt)
+	if err != nil {
+		return err
+	}
+	ef.Data = aead.Seal(nil, ef.Nonce, plain, nil)
+
+	data, err := json.MarshalIndent(ef, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", p.path, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(p.path), err)
+	}
+	if err := os.WriteFile(p.path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", p.path, err)
+	}
+
+	return nil
+}
+
+func (p *FileProvider) ensurePassphrase() error {
+	if p.passphrase != "" {
+		return nil
+	}
+
+	passphrase, err := PromptPassphrase(p.path)
+	if err != nil {
+		return err
+	}
+	if passphrase == "" {
+		return fmt.Errorf("empty passphrase for %s", p.path)
+	}
+	p.passphrase = passphrase
+
+	return nil
+}
+
+// newAEAD derives the file key from passphrase and salt.
+func newAEAD(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, chacha20poly1305.KeySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+
+	return chacha20poly1305.NewX(key)
+}
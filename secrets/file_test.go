@@ -0,0 +1,84 @@
+package secrets
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// stubPassphrase replaces the passphrase prompt for the duration of the test.
+func stubPassphrase(t *testing.T, passphrase string) *int {
+	t.Helper()
+	prompts := 0
+	original := PromptPassphrase
+	PromptPassphrase = func(string) (string, error) {
+		prompts++
+		return passphrase, nil
+	}
+	t.Cleanup(func() { PromptPassphrase = original })
+	return &prompts
+}
+
+func TestFileProvider_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "whiterose", "secrets.enc")
+	prompts := stubPassphrase(t, "correct horse")
+
+	if _, err := NewFileProvider(path).Get("gitlab-token"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() on a missing file error = %v, want ErrNotFound", err)
+	}
+
+	writer := NewFileProvider(path)
+	if err := writer.Set("gitlab-token", "glpat"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := writer.Set("github-token", "ghp"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read secrets file: %v", err)
+	}
+	if strings.Contains(string(data), "glpat") {
+		t.Error("secrets file contains the secret in plain text")
+	}
+	if fi, _ := os.Stat(path); fi.Mode().Perm() != 0o600 {
+		t.Errorf("secrets file mode = %v, want 0600", fi.Mode().Perm())
+	}
+
+	reader := NewFileProvider(path)
+	got, err := reader.Get("gitlab-token")
+	if err != nil || got != "glpat" {
+		t.Errorf("Get() = %q, %v, want glpat", got, err)
+	}
+	if _, err := reader.Get("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(missing) error = %v, want ErrNotFound", err)
+	}
+
+	names, err := reader.Names()
+	if err != nil || !reflect.DeepEqual(names, []string{"github-token", "gitlab-token"}) {
+		t.Errorf("Names() = %v, %v", names, err)
+	}
+
+	if *prompts != 2 {
+		t.Errorf("prompted %d times, want once per provider", *prompts)
+	}
+}
+
+func TestFileProvider_WrongPassphrase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.enc")
+
+	stubPassphrase(t, "right")
+	if err := NewFileProvider(path).Set("token", "value"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	stubPassphrase(t, "wrong")
+	_, err := NewFileProvider(path).Get("token")
+	if err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() with a wrong passphrase error = %v, want a decryption error", err)
+	}
+}
@@ -0,0 +1,128 @@
+package secrets
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// KeyringService is the service name whiterose secrets are stored under in the OS keyring.
+const KeyringService = "whiterose"
+
+// Keyring stores secrets in the operating system keyring. Get returns an error wrapping
+// ErrNotFound when the account does not exist.
+type Keyring interface {
+	Get(service, account string) (string, error)
+	Set(service, account, value string) error
+}
+
+// KeyringProvider reads secrets from a Keyring under KeyringService.
+type KeyringProvider struct {
+	keyring Keyring
+}
+
+// NewKeyringProvider creates a KeyringProvider backed by keyring.
+func NewKeyringProvider(keyring Keyring) *KeyringProvider {
+	return &KeyringProvider{keyring: keyring}
+}
+
+func (p *KeyringProvider) Name() string {
+	return "keyring"
+}
+
+func (p *KeyringProvider) Get(name string) (string, error) {
+	return p.keyring.Get(KeyringService, name)
+}
+
+// Set stores a secret in the keyring.
+func (p *KeyringProvider) Set(name, value string) error {
+	return p.keyring.Set(KeyringService, name, value)
+}
+
+// SystemKeyring talks to the OS keyring through its command-line tool:
+// "security" on macOS and "secret-tool" (libsecret) on Linux.
+type SystemKeyring struct {
+	goos string
+}
+
+// NewSystemKeyring creates a SystemKeyring for the current operating system.
+func NewSystemKeyring() *SystemKeyring {
+	return &SystemKeyring{goos: runtime.GOOS}
+}
+
+func (k *SystemKeyring) Get(service, account string) (string, error) {
+	name, args, stdin, err := keyringCommand(k.goos, service, account, nil)
+	if err != nil {
+		return "", fmt.Errorf("%v: %w", err, ErrNotFound)
+	}
+
+	out, err := runKeyringCommand(stdin, name, args...)
+	if err != nil {
+		// A missing tool or entry both mean the keyring cannot provide the secret.
+		return "", fmt.Errorf("%s %s: %v: %w", name, account, err, ErrNotFound)
+	}
+
+	return strings.TrimRight(out, "\r\n"), nil
+}
+
+func (k *SystemKeyring) Set(service, account, value string) error {
+	name, args, stdin, err := keyringCommand(k.goos, service, account, &value)
+	if err != nil {
+		return err
+	}
+
+	if _, err := runKeyringCommand(stdin, name, args...); err != nil {
+		return fmt.Errorf("failed to store %s in the keyring: %w", account, err)
+	}
+
+	return nil
+}
+
+// keyringCommand returns the command and stdin reading a keyring entry on goos, or writing
+// value when it is not nil. Values are always passed on stdin so they never appear in the
+// process list.
+func keyringCommand(goos, service, account string, value *string) (string, []string, string, error) {
+	switch goos {
+	case "darwin":
+		if value == nil {
+			return "security", []string{"find-generic-password", "-s", service, "-a", account, "-w"}, "", nil
+		}
+		// "security -i" reads commands from stdin, keeping the value out of its arguments.
+		stdin := fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n", quote(service), quote(account), quote(*value))
+		return "security", []string{"-i"}, stdin, nil
+	case "linux", "freebsd", "openbsd", "netbsd":
+		if value == nil {
+			return "secret-tool", []string{"lookup", "service", service, "account", account}, "", nil
+		}
+		return "secret-tool", []string{"store", "--label", service + " " + account, "service", service, "account", account}, *value, nil
+	default:
+		return "", nil, "", fmt.Errorf("keyring is not supported on %s", goos)
+	}
+}
+
+// quote wraps s in single quotes for the "security -i" command line.
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// runKeyringCommand runs name with stdin and returns its standard output.
+func runKeyringCommand(stdin, name string, args ...string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && stderr.Len() > 0 {
+			return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		return "", err
+	}
+
+	return string(out), nil
+}
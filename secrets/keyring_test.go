@@ -0,0 +1,87 @@
+package secrets
+
+import (
+	"reflect"
+	"testing"
+)
+
+// memoryKeyring is an in-memory Keyring.
+type memoryKeyring map[string]string
+
+func (k memoryKeyring) Get(service, account string) (string, error) {
+	if v, ok := k[service+"/"+account]; ok {
+		return v, nil
+	}
+	return "", ErrNotFound
+}
+
+func (k memoryKeyring) Set(service, account, value string) error {
+	k[service+"/"+account] = value
+	return nil
+}
+
+func TestKeyringProvider(t *testing.T) {
+	kr := memoryKeyring{}
+	p := NewKeyringProvider(kr)
+
+	if err := p.Set("github-token", "ghp"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if kr["whiterose/github-token"] != "ghp" {
+		t.Errorf("keyring = %v, want whiterose/github-token", kr)
+	}
+
+	got, err := p.Get("github-token")
+	if err != nil || got != "ghp" {
+		t.Errorf("Get() = %q, %v, want ghp", got, err)
+	}
+}
+
+func TestKeyringCommand(t *testing.T) {
+	value := "it's secret"
+
+	tests := []struct {
+		name      string
+		goos      string
+		value     *string
+		wantCmd   string
+		wantArgs  []string
+		wantStdin string
+		wantErr   bool
+	}{
+		{
+			name: "darwin get", goos: "darwin",
+			wantCmd: "security", wantArgs: []string{"find-generic-password", "-s", "whiterose", "-a", "tok", "-w"},
+		},
+		{
+			name: "darwin set", goos: "darwin", value: &value,
+			wantCmd: "security", wantArgs: []string{"-i"},
+			wantStdin: "add-generic-password -U -s 'whiterose' -a 'tok' -w 'it'\\''s secret'\n",
+		},
+		{
+			name: "linux get", goos: "linux",
+			wantCmd: "secret-tool", wantArgs: []string{"lookup", "service", "whiterose", "account", "tok"},
+		},
+		{
+			name: "linux set", goos: "linux", value: &value,
+			wantCmd: "secret-tool", wantArgs: []string{"store", "--label", "whiterose tok", "service", "whiterose", "account", "tok"},
+			wantStdin: value,
+		},
+		{name: "windows", goos: "windows", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, args, stdin, err := keyringCommand(tt.goos, "whiterose", "tok", tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("keyringCommand() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if cmd != tt.wantCmd || !reflect.DeepEqual(args, tt.wantArgs) || stdin != tt.wantStdin {
+				t.Errorf("keyringCommand() = %s %v %q, want %s %v %q", cmd, args, stdin, tt.wantCmd, tt.wantArgs, tt.wantStdin)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+// Package secrets resolves tokens and passphrases from pluggable backends so that configuration
+// values can reference a secret as "secret://name" instead of embedding it.
+//
+// Backends:
+//   - EnvProvider: reads WHITEROSE_SECRET_<NAME> environment variables.
+//   - KeyringProvider: reads the OS keyring through the Keyring interface.
+//   - FileProvider: reads a passphrase-encrypted local file.
+//
+// Resolve looks a reference up in the default chain (env, keyring, then encrypted file) and
+// returns any other value unchanged.
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ReferencePrefix marks a configuration value as a reference to a secret.
+const ReferencePrefix = "secret://"
+
+// ErrNotFound is returned by a Provider that does not hold the requested secret.
+var ErrNotFound = errors.New("secret not found")
+
+// Provider returns the value of a named secret.
+type Provider interface {
+	// Name identifies the backend in error messages, e.g. "env" or "keyring".
+	Name() string
+	// Get returns the secret value, or an error wrapping ErrNotFound when the backend does not hold it.
+	Get(name string) (string, error)
+}
+
+// Chain tries each provider in order and returns the first secret found.
+type Chain []Provider
+
+func (c Chain) Name() string {
+	names := make([]string, 0, len(c))
+	for _, p := range c {
+		names = append(names, p.Name())
+	}
+	return strings.Join(names, ", ")
+}
+
+// Get returns the secret from the first provider holding it. Errors other than ErrNotFound
+// stop the lookup, so a wrong passphrase is reported rather than skipped.
+func (c Chain) Get(name string) (string, error) {
+	for _, p := range c {
+		value, err := p.Get(name)
+		if err == nil {
+			return value, nil
+		}
+		if !errors.Is(err, ErrNotFound) {
+			return "", fmt.Errorf("%s: %w", p.Name(), err)
+		}
+	}
+
+	return "", fmt.Errorf("secret %q not found in %s: %w", name, c.Name(), ErrNotFound)
+}
+
+// IsReference reports whether value is a "secret://name" reference.
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, ReferencePrefix)
+}
+
+// Resolver resolves secret references through a provider and caches the values it returned,
+// so a secret shared by many repositories is only looked up once.
+type Resolver struct {
+	provider Provider
+	mu       sync.Mutex
+	cache    map[string]string
+}
+
+// NewResolver creates a Resolver backed by provider.
+func NewResolver(provider Provider) *Resolver {
+	return &Resolver{provider: provider, cache: make(map[string]string)}
+}
+
+// Resolve returns the secret referenced by value, or value itself when it is not a reference.
+func (r *Resolver) Resolve(value string) (string, error) {
+	if !IsReference(value) {
+		return value, nil
+	}
+
+	name := strings.TrimPrefix(value, ReferencePrefix)
+	if name == "" {
+		return "", fmt.Errorf("empty secret reference %q", value)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if v, ok := r.cache[name]; ok {
+		return v, nil
+	}
+
+	v, err := r.provider.Get(name)
+	if err != nil {
+		return "", err
+	}
+	r.cache[name] = v
+
+	return v, nil
+}
+
+var (
+	defaultResolver     *Resolver
+	defaultResolverOnce sync.Once
+)
+
+// DefaultChain returns the providers used by Resolve: environment variables, the OS keyring
+// and the encrypted secrets file, in that order.
+func DefaultChain() Chain {
+	return Chain{
+		NewEnvProvider(),
+		NewKeyringProvider(NewSystemKeyring()),
+		NewFileProvider(DefaultFilePath()),
+	}
+}
+
+// Resolve resolves value through the default chain; see Resolver.Resolve.
+func Resolve(value string) (string, error) {
+	defaultResolverOnce.Do(func() {
+		defaultResolver = NewResolver(DefaultChain())
+	})
+	return defaultResolver.Resolve(value)
+}
+
+// normalizeName turns a secret name into the suffix of an environment variable,
+// e.g. "gitlab-token" becomes "GITLAB_TOKEN".
+func normalizeName(name string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "_", ".", "_", " ", "_", "/", "_").Replace(name))
+}
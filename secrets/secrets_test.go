@@ -0,0 +1,110 @@
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// mapProvider is an in-memory Provider counting its lookups.
+type mapProvider struct {
+	name    string
+	secrets map[string]string
+	err     error
+	calls   int
+}
+
+func (p *mapProvider) Name() string { return p.name }
+
+func (p *mapProvider) Get(name string) (string, error) {
+	p.calls++
+	if p.err != nil {
+		return "", p.err
+	}
+	if v, ok := p.secrets[name]; ok {
+		return v, nil
+	}
+	return "", fmt.Errorf("%s: %w", name, ErrNotFound)
+}
+
+func TestChain_Get(t *testing.T) {
+	first := &mapProvider{name: "first", secrets: map[string]string{"a": "1"}}
+	second := &mapProvider{name: "second", secrets: map[string]string{"a": "2", "b": "3"}}
+	chain := Chain{first, second}
+
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{name: "a", want: "1"},
+		{name: "b", want: "3"},
+		{name: "c", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := chain.Get(tt.name)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("Get(%s) = %q, %v, want %q (error %v)", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+
+	if _, err := chain.Get("c"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(c) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestChain_StopsOnError(t *testing.T) {
+	broken := &mapProvider{name: "broken", err: errors.New("wrong passphrase")}
+	fallback := &mapProvider{name: "fallback", secrets: map[string]string{"a": "1"}}
+
+	if _, err := (Chain{broken, fallback}).Get("a"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() error = %v, want the provider error", err)
+	}
+	if fallback.calls != 0 {
+		t.Error("Get() consulted the next provider after an error")
+	}
+}
+
+func TestResolver_Resolve(t *testing.T) {
+	p := &mapProvider{name: "test", secrets: map[string]string{"gitlab-token": "glpat"}}
+	r := NewResolver(p)
+
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{value: "plain-token", want: "plain-token"},
+		{value: "", want: ""},
+		{value: "secret://gitlab-token", want: "glpat"},
+		{value: "secret://missing", wantErr: true},
+		{value: "secret://", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := r.Resolve(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("Resolve(%q) = %q, %v, want %q (error %v)", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+
+	p.calls = 0
+	_, _ = r.Resolve("secret://gitlab-token")
+	if p.calls != 0 {
+		t.Errorf("Resolve() looked up a cached secret %d times", p.calls)
+	}
+}
+
+func TestEnvProvider_Get(t *testing.T) {
+	t.Setenv("WHITEROSE_SECRET_GITLAB_TOKEN", "glpat")
+
+	got, err := NewEnvProvider().Get("gitlab-token")
+	if err != nil || got != "glpat" {
+		t.Errorf("Get(gitlab-token) = %q, %v, want glpat", got, err)
+	}
+
+	if _, err := NewEnvProvider().Get("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(missing) error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,212 @@
+// Package semver extracts semantic versions from the output of command-line tools and checks
+// them against the version constraints of the configuration, such as ">=1.25" or "~2.40".
+//
+// Versions have up to three numeric parts; pre-release and build suffixes are ignored, so
+// "go1.25rc1" is read as 1.25. A constraint is one or more comma-separated clauses, all of
+// which must hold:
+//
+//	1.25     at least 1.25 (a bare version is the recommended minimum)
+//	>=1.25   at least 1.25
+//	>1.25    1.26 or later
+//	<=1.25   1.25.x or earlier
+//	<2       earlier than 2.0.0
+//	=1.25    any 1.25.x
+//	~2.40    at least 2.40.0 and earlier than 2.41.0
+//	^1.2     at least 1.2.0 and earlier than 2.0.0
+//
+// Clauses compare only as many parts as they name: "<=1.25" accepts 1.25.9 and "=1" any 1.x.
+package semver
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Version is a semantic version without pre-release or build metadata.
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Compare returns -1, 0 or +1 depending on whether v is lower than, equal to or greater than o.
+func (v Version) Compare(o Version) int {
+	for _, d := range []int{v.Major - o.Major, v.Minor - o.Minor, v.Patch - o.Patch} {
+		switch {
+		case d < 0:
+			return -1
+		case d > 0:
+			return 1
+		}
+	}
+	return 0
+}
+
+// versionPattern matches the first version in a text, such as 1.25.0 in "go version go1.25.0 linux/amd64".
+var versionPattern = regexp.MustCompile(`\d+(?:\.\d+){0,2}`)
+
+// Parse reads a version such as "1.25", "v2.40.1" or "28.1.1-rc.2".
+func Parse(s string) (Version, error) {
+	v, _, err := parse(s)
+	return v, err
+}
+
+// parse reads a version and returns how many of its parts were given.
+func parse(s string) (Version, int, error) {
+	trimmed := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexAny(trimmed, "-+"); i >= 0 {
+		trimmed = trimmed[:i]
+	}
+
+	fields := strings.Split(trimmed, ".")
+	if len(fields) > 3 {
+		return Version{}, 0, fmt.Errorf("invalid version %q: more than three parts", s)
+	}
+
+	var parts [3]int
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 {
+			return Version{}, 0, fmt.Errorf("invalid version %q", s)
+		}
+		parts[i] = n
+	}
+
+	return Version{Major: parts[0], Minor: parts[1], Patch: parts[2]}, len(fields), nil
+}
+
+// Extract finds the version in the output of a version command. With an empty pattern the first
+// dotted number is used; otherwise pattern is a regular expression whose first capture group, or
+// whole match when it has none, is the version.
+func Extract(text, pattern string) (Version, error) {
+	re := versionPattern
+	if pattern != "" {
+		var err error
+		if re, err = regexp.Compile(pattern); err != nil {
+			return Version{}, fmt.Errorf("invalid version pattern %q: %w", pattern, err)
+		}
+	}
+
+	m := re.FindStringSubmatch(text)
+	if m == nil {
+		return Version{}, fmt.Errorf("no version found in %q", strings.TrimSpace(text))
+	}
+
+	match := m[0]
+	if len(m) > 1 && m[1] != "" {
+		match = m[1]
+	}
+	if pattern != "" {
+		// A custom pattern may capture text around the number, such as "go1.25.0".
+		if found := versionPattern.FindString(match); found != "" {
+			match = found
+		}
+	}
+
+	return Parse(match)
+}
+
+// Constraint is a set of version requirements that must all hold.
+type Constraint struct {
+	raw     string
+	clauses []clause
+}
+
+type clause struct {
+	op      string
+	version Version
+	parts   int
+}
+
+// operators are the supported comparison operators, longest first so that ">=" is not read as ">".
+var operators = []string{">=", "<=", "==", ">", "<", "=", "~", "^"}
+
+// ParseConstraint reads a constraint such as ">=1.25", "~2.40" or ">=1.22, <2".
+func ParseConstraint(s string) (Constraint, error) {
+	c := Constraint{raw: strings.TrimSpace(s)}
+	if c.raw == "" {
+		return Constraint{}, fmt.Errorf("empty version constraint")
+	}
+
+	for _, part := range strings.Split(c.raw, ",") {
+		part = strings.TrimSpace(part)
+
+		op := ">="
+		for _, candidate := range operators {
+			if strings.HasPrefix(part, candidate) {
+				op, part = candidate, strings.TrimSpace(part[len(candidate):])
+				break
+			}
+		}
+		if op == "==" {
+			op = "="
+		}
+
+		v, n, err := parse(part)
+		if err != nil {
+			return Constraint{}, fmt.Errorf("invalid version constraint %q: %w", s, err)
+		}
+		c.clauses = append(c.clauses, clause{op: op, version: v, parts: n})
+	}
+
+	return c, nil
+}
+
+func (c Constraint) String() string {
+	return c.raw
+}
+
+// Check reports whether v satisfies every clause of the constraint.
+func (c Constraint) Check(v Version) bool {
+	for _, cl := range c.clauses {
+		if !cl.check(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func (cl clause) check(v Version) bool {
+	cmp := truncate(v, cl.parts).Compare(cl.version)
+
+	switch cl.op {
+	case ">=":
+		return cmp >= 0
+	case ">":
+		return cmp > 0
+	case "<=":
+		return cmp <= 0
+	case "<":
+		return cmp < 0
+	case "=":
+		return cmp == 0
+	case "~":
+		if v.Compare(cl.version) < 0 {
+			return false
+		}
+		if cl.parts == 1 {
+			return v.Major == cl.version.Major
+		}
+		return v.Major == cl.version.Major && v.Minor == cl.version.Minor
+	case "^":
+		return v.Compare(cl.version) >= 0 && v.Major == cl.version.Major
+	}
+	return false
+}
+
+// truncate zeroes the parts of v beyond the first n, so that it compares to a partial version.
+func truncate(v Version, n int) Version {
+	switch n {
+	case 1:
+		return Version{Major: v.Major}
+	case 2:
+		return Version{Major: v.Major, Minor: v.Minor}
+	}
+	return v
+}
@@ -0,0 +1,121 @@
+package semver
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Version
+		wantErr bool
+	}{
+		{"1.25.0", Version{1, 25, 0}, false},
+		{"v2.40", Version{2, 40, 0}, false},
+		{"28", Version{28, 0, 0}, false},
+		{"1.7.1-rc.2+build", Version{1, 7, 1}, false},
+		{"", Version{}, true},
+		{"1.2.3.4", Version{}, true},
+		{"one.two", Version{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Parse(tt.in)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("Parse(%q) = %v, %v, want %v (error %v)", tt.in, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		pattern string
+		want    Version
+		wantErr bool
+	}{
+		{"go", "go version go1.25.0 linux/amd64", "", Version{1, 25, 0}, false},
+		{"go release candidate", "go version go1.26rc1 darwin/arm64", "", Version{1, 26, 0}, false},
+		{"git", "git version 2.43.0\n", "", Version{2, 43, 0}, false},
+		{"docker", "Docker version 28.1.1, build 4eba377", "", Version{28, 1, 1}, false},
+		{"jq", "jq-1.7.1", "", Version{1, 7, 1}, false},
+		{"capture group", "terraform v1.9.8 on linux_amd64\nprovider 5.0.1", `terraform v(\S+)`, Version{1, 9, 8}, false},
+		{"whole match", "kubectl: Client Version: v1.31.2", `v\d+\.\d+\.\d+`, Version{1, 31, 2}, false},
+		{"no version", "command not configured", "", Version{}, true},
+		{"pattern without match", "go version go1.25.0", `node v(\d+)`, Version{}, true},
+		{"invalid pattern", "go1.25.0", `(`, Version{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Extract(tt.text, tt.pattern)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("Extract() = %v, %v, want %v (error %v)", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConstraint_Check(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		want       bool
+	}{
+		{"1.25", "1.25.0", true},
+		{"1.25", "1.26.1", true},
+		{"1.25", "1.24.9", false},
+		{">=1.25", "1.25.3", true},
+		{">=1.25.2", "1.25.1", false},
+		{">1.25", "1.25.9", false},
+		{">1.25", "1.26.0", true},
+		{"<=1.25", "1.25.9", true},
+		{"<=1.25", "1.26.0", false},
+		{"<2", "1.99.0", true},
+		{"<2", "2.0.1", false},
+		{"=1.25", "1.25.4", true},
+		{"==1.25.4", "1.25.5", false},
+		{"~2.40", "2.40.3", true},
+		{"~2.40", "2.41.0", false},
+		{"~2.40.2", "2.40.1", false},
+		{"~1", "1.9.0", true},
+		{"^1.2", "1.9.0", true},
+		{"^1.2", "2.0.0", false},
+		{"^1.2", "1.1.9", false},
+		{">=1.22, <2", "1.25.0", true},
+		{">=1.22, <2", "2.1.0", false},
+		{" >= 1.22 ", "1.22.0", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint+" "+tt.version, func(t *testing.T) {
+			c, err := ParseConstraint(tt.constraint)
+			if err != nil {
+				t.Fatalf("ParseConstraint(%q) error = %v", tt.constraint, err)
+			}
+			v, err := Parse(tt.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := c.Check(v); got != tt.want {
+				t.Errorf("%q.Check(%s) = %v, want %v", tt.constraint, v, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseConstraint_Invalid(t *testing.T) {
+	for _, s := range []string{"", "latest", ">=", "~x.y", ">=1.2,"} {
+		if _, err := ParseConstraint(s); err == nil {
+			t.Errorf("ParseConstraint(%q) succeeded, want an error", s)
+		}
+	}
+}
+
+func TestVersion_Compare(t *testing.T) {
+	a, b := Version{1, 25, 0}, Version{1, 25, 1}
+	if a.Compare(b) != -1 || b.Compare(a) != 1 || a.Compare(a) != 0 {
+		t.Errorf("Compare(%s, %s) is not ordered", a, b)
+	}
+}
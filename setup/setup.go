@@ -1,24 +1,75 @@
-// GitCloneRepository loads repository configurations from a JSON file,
-// sets authentication credentials and SSH key information from environment variables,
-// and fetches/clones the repositories. If any error occurs during loading or fetching,
-// the function logs the error and terminates the application.
+// Package setup prepares a development environment: it validates the required applications,
+// installs the missing ones and clones the configured repositories.
 package setup
 
 import (
+	"fmt"
+	"io"
+	"strings"
+
 	"github.com/fabianoflorentino/whiterose/git"
+	"github.com/fabianoflorentino/whiterose/install"
 	"github.com/fabianoflorentino/whiterose/prereq"
 )
 
-func PreReq() {
-	p := prereq.NewAppValidator()
-	p.ValidateApps()
+// InstallOptions control how InstallPreReq installs the missing applications.
+type InstallOptions struct {
+	// DryRun prints the installation plan without installing anything.
+	DryRun bool
+	// Confirm is asked before the plan runs; the plan runs without asking when it is nil.
+	Confirm func(plan install.Plan) (bool, error)
+	// Out receives the plan and the installation progress.
+	Out io.Writer
+}
+
+// InstallPreReq validates the applications listed in the configuration and installs the missing
+// ones with the install engine, after printing the plan. It returns the validation made after
+// the installation, so that applications that failed to install are still reported missing.
+func InstallPreReq(opts InstallOptions) (prereq.Results, error) {
+	av, err := prereq.NewAppValidator()
+	if err != nil {
+		return nil, err
+	}
+
+	results := av.Check()
+	missing := av.Missing(results)
+	if len(missing) == 0 {
+		return results, nil
+	}
+
+	names := make([]string, len(missing))
+	for i, app := range missing {
+		names[i] = app.Name
+	}
+	fmt.Fprintf(opts.Out, "Missing prerequisites: %s\n", strings.Join(names, ", "))
+
+	engine := install.NewEngine()
+	plan := engine.Plan(missing)
+	plan.Text(opts.Out)
+	if len(plan.Steps) == 0 || opts.DryRun {
+		return results, nil
+	}
+
+	if opts.Confirm != nil {
+		ok, err := opts.Confirm(plan)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return results, nil
+		}
+	}
+
+	engine.Execute(plan, opts.Out).Text(opts.Out)
+	fmt.Fprintln(opts.Out)
+
+	return av.Check(), nil
 }
 
 // GitCloneRepository loads repository configurations from a JSON file,
 // sets authentication credentials and SSH key information from environment variables,
-// and fetches/clones the repositories. If any error occurs during loading or fetching,
-// the function logs the error and terminates the application.
-func GitCloneRepository() {
-	g := git.NewGitRepository()
-	g.Setup()
+// and fetches/clones the repositories concurrently. It returns an error when the configuration
+// cannot be loaded or any repository fails.
+func GitCloneRepository(opts git.SetupOptions) error {
+	return git.NewGitRepository().Setup(opts)
 }
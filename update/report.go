@@ -0,0 +1,209 @@
+package update
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/fabianoflorentino/whiterose/internal/domain/entities"
+	"github.com/fabianoflorentino/whiterose/output"
+)
+
+// ProjectPackages are the library updates of one project.
+type ProjectPackages struct {
+	Project string         `json:"project"`
+	Updates PackageUpdates `json:"updates"`
+	Error   string         `json:"error,omitempty"`
+}
+
+// ProjectImage are the tags available for the base Docker image of one project.
+type ProjectImage struct {
+	Project string `json:"project"`
+	ImageTags
+	Error string `json:"error,omitempty"`
+}
+
+// UpdateList is the result of update --list: the library updates and Docker tags of the
+// configured projects and the published Go releases, each present only when requested.
+type UpdateList struct {
+	Libraries    []ProjectPackages `json:"libraries,omitempty"`
+	GoVersions   *GoVersions       `json:"goVersions,omitempty"`
+	DockerImages []ProjectImage    `json:"dockerImages,omitempty"`
+}
+
+// Text writes each section with the headers printed by update --list.
+func (l UpdateList) Text(w io.Writer) {
+	for _, p := range l.Libraries {
+		fmt.Fprintf(w, "\n=== %s: Library updates ===\n", p.Project)
+		if p.Error == "" {
+			p.Updates.Text(w)
+		}
+	}
+
+	if l.GoVersions != nil {
+		l.GoVersions.Text(w)
+	}
+
+	for _, p := range l.DockerImages {
+		fmt.Fprintf(w, "\n=== %s: Docker image updates ===\n", p.Project)
+		if p.Error == "" {
+			p.ImageTags.Text(w)
+		}
+	}
+}
+
+// Table lays every available version out on its own row.
+func (l UpdateList) Table() output.Table {
+	t := output.Table{Header: []string{"TYPE", "PROJECT", "NAME", "CURRENT", "AVAILABLE"}}
+	for _, p := range l.Libraries {
+		if p.Error != "" {
+			t.Rows = append(t.Rows, []string{"package", p.Project, "-", "-", "error: " + p.Error})
+		}
+		for _, u := range p.Updates {
+			t.Rows = append(t.Rows, []string{"package", p.Project, u.Name, u.CurrentVersion, u.LatestVersion})
+		}
+	}
+	if l.GoVersions != nil {
+		for _, row := range l.GoVersions.Table().Rows {
+			t.Rows = append(t.Rows, []string{"go", "-", "go", "-", row[0] + " (" + row[1] + ")"})
+		}
+	}
+	for _, p := range l.DockerImages {
+		if p.Error != "" {
+			t.Rows = append(t.Rows, []string{"docker", p.Project, "-", "-", "error: " + p.Error})
+		}
+		for _, tag := range p.Tags {
+			t.Rows = append(t.Rows, []string{"docker", p.Project, p.Image, p.Current, tag})
+		}
+	}
+	return t
+}
+
+// ProjectReport describes the dependencies of one project in the updates report.
+type ProjectReport struct {
+	Name        string         `json:"name"`
+	Path        string         `json:"path"`
+	Packages    PackageUpdates `json:"packages,omitempty"`
+	Error       string         `json:"error,omitempty"`
+	GoVersion   string         `json:"goVersion,omitempty"`
+	DockerImage string         `json:"dockerImage,omitempty"`
+}
+
+// Report is the dependency updates report published by update --report.
+type Report struct {
+	Generated string          `json:"generated"`
+	Projects  []ProjectReport `json:"projects"`
+}
+
+// listModuleUpdates runs go list -m -u all in the module at dir.
+var listModuleUpdates = func(dir string) (string, error) {
+	cmd := exec.Command("go", "list", "-m", "-u", "all")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+// BuildReport gathers the library updates, Go version and Docker base image of every project.
+func (vc *VersionChecker) BuildReport(projects []entities.UpdateProject) Report {
+	report := Report{Generated: entities.GetTimestampedBranchName(), Projects: []ProjectReport{}}
+
+	for _, project := range projects {
+		p := ProjectReport{Name: project.Name, Path: project.Path}
+
+		if project.GoMod != nil {
+			out, err := listModuleUpdates(project.Path)
+			if err != nil {
+				p.Error = "Error checking updates"
+			} else {
+				p.Packages = ParsePackageUpdates(out)
+			}
+		}
+		if project.GoVersion != nil {
+			p.GoVersion = vc.GetCurrentGoVersion(project.Path)
+		}
+		if project.DockerImage != nil {
+			p.DockerImage = project.DockerImage.Base
+		}
+
+		report.Projects = append(report.Projects, p)
+	}
+
+	return report
+}
+
+// Markdown returns the report as the body of a pull request.
+func (r Report) Markdown() string {
+	var b strings.Builder
+
+	b.WriteString("# 📦 Dependency Updates Report\n\n")
+	fmt.Fprintf(&b, "Generated: %s\n\n", r.Generated)
+	b.WriteString("---\n\n")
+
+	for _, p := range r.Projects {
+		fmt.Fprintf(&b, "## %s\n\n", p.Name)
+		fmt.Fprintf(&b, "Path: `%s`\n\n", p.Path)
+
+		if p.Packages != nil || p.Error != "" {
+			b.WriteString("### Go Packages\n\n")
+			switch {
+			case p.Error != "":
+				fmt.Fprintf(&b, "```\n%s\n```\n\n", p.Error)
+			case len(p.Packages) == 0:
+				b.WriteString("All packages up to date.\n\n")
+			default:
+				for _, u := range p.Packages {
+					fmt.Fprintf(&b, "- `%s %s -> %s`\n", u.Name, u.CurrentVersion, u.LatestVersion)
+				}
+				b.WriteString("\n")
+			}
+		}
+
+		if p.GoVersion != "" {
+			b.WriteString("### Go Version\n\n")
+			fmt.Fprintf(&b, "Current: %s\n\n", p.GoVersion)
+		}
+
+		if p.DockerImage != "" {
+			b.WriteString("### Docker Image\n\n")
+			fmt.Fprintf(&b, "Current: `%s`\n\n", p.DockerImage)
+		}
+
+		b.WriteString("---\n\n")
+	}
+
+	b.WriteString("## 🤖 Generated by Whiterose\n\n")
+	b.WriteString("Use `whiterose update --packages --config update-config.yaml` to apply updates.\n")
+
+	return b.String()
+}
+
+// Text writes the report as Markdown.
+func (r Report) Text(w io.Writer) {
+	fmt.Fprintln(w, r.Markdown())
+}
+
+// Table lays the report out one project per row.
+func (r Report) Table() output.Table {
+	t := output.Table{Header: []string{"PROJECT", "PATH", "PACKAGE UPDATES", "GO VERSION", "DOCKER IMAGE"}}
+	for _, p := range r.Projects {
+		updates := "-"
+		switch {
+		case p.Error != "":
+			updates = "error"
+		case p.Packages != nil:
+			updates = strconv.Itoa(len(p.Packages))
+		}
+		t.Rows = append(t.Rows, []string{p.Name, p.Path, updates, dash(p.GoVersion), dash(p.DockerImage)})
+	}
+	return t
+}
+
+// dash returns s, or "-" when it is empty.
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,103 @@
+package update
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fabianoflorentino/whiterose/internal/domain/entities"
+)
+
+func TestVersionChecker_BuildReport(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module api\ngo 1.25.0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	original := listModuleUpdates
+	t.Cleanup(func() { listModuleUpdates = original })
+	listModuleUpdates = func(path string) (string, error) {
+		if path != dir {
+			return "", errors.New("go.mod not found")
+		}
+		return "api\ngithub.com/bar v2.0.0 [v2.1.0]\n", nil
+	}
+
+	report := NewVersionChecker().BuildReport([]entities.UpdateProject{
+		{
+			Name:        "api",
+			Path:        dir,
+			GoMod:       &entities.GoModConfig{},
+			GoVersion:   &entities.GoVersionConfig{},
+			DockerImage: &entities.DockerImageConfig{Base: "golang:1.25"},
+		},
+		{Name: "worker", Path: "/nonexistent", GoMod: &entities.GoModConfig{}},
+		{Name: "docs", Path: "/docs"},
+	})
+
+	if len(report.Projects) != 3 || report.Generated == "" {
+		t.Fatalf("BuildReport() = %+v", report)
+	}
+	api := report.Projects[0]
+	if len(api.Packages) != 1 || api.GoVersion != "1.25.0" || api.DockerImage != "golang:1.25" {
+		t.Errorf("api = %+v", api)
+	}
+	if report.Projects[1].Error == "" {
+		t.Errorf("worker = %+v, want an error", report.Projects[1])
+	}
+
+	md := report.Markdown()
+	for _, want := range []string{
+		"## api\n",
+		"- `github.com/bar v2.0.0 -> v2.1.0`\n",
+		"Current: 1.25.0\n",
+		"Current: `golang:1.25`\n",
+		"```\nError checking updates\n```\n",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown() missing %q:\n%s", want, md)
+		}
+	}
+
+	rows := report.Table().Rows
+	if len(rows) != 3 || rows[0][2] != "1" || rows[1][2] != "error" || rows[2][2] != "-" {
+		t.Errorf("Table().Rows = %v", rows)
+	}
+}
+
+func TestUpdateList_Table(t *testing.T) {
+	list := UpdateList{
+		Libraries: []ProjectPackages{
+			{Project: "api", Updates: PackageUpdates{{Name: "github.com/bar", CurrentVersion: "v2.0.0", LatestVersion: "v2.1.0"}}},
+			{Project: "worker", Error: "go not installed"},
+		},
+		GoVersions:   &GoVersions{Stable: []string{"go1.25.0"}},
+		DockerImages: []ProjectImage{{Project: "api", ImageTags: ImageTags{Image: "golang", Current: "1.25", Tags: []string{"1.25.1"}}}},
+	}
+
+	want := [][]string{
+		{"package", "api", "github.com/bar", "v2.0.0", "v2.1.0"},
+		{"package", "worker", "-", "-", "error: go not installed"},
+		{"go", "-", "go", "-", "go1.25.0 (stable)"},
+		{"docker", "api", "golang", "1.25", "1.25.1"},
+	}
+	rows := list.Table().Rows
+	if len(rows) != len(want) {
+		t.Fatalf("Table().Rows = %v, want %v", rows, want)
+	}
+	for i := range want {
+		if strings.Join(rows[i], "|") != strings.Join(want[i], "|") {
+			t.Errorf("row %d = %v, want %v", i, rows[i], want[i])
+		}
+	}
+
+	var out strings.Builder
+	list.Text(&out)
+	for _, want := range []string{"=== api: Library updates ===", "=== worker: Library updates ===", "go1.25.0 (stable)", "=== api: Docker image updates ==="} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Text() missing %q:\n%s", want, out.String())
+		}
+	}
+}
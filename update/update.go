@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/fabianoflorentino/whiterose/config"
+	"github.com/fabianoflorentino/whiterose/exitcode"
 	"github.com/fabianoflorentino/whiterose/internal/domain/entities"
 )
 
@@ -160,11 +162,33 @@ func (s *UpdateService) CreatePRWithBase(project entities.UpdateProject, branchN
 	return nil
 }
 
+// LoadUpdateConfig reads the projects of a standalone update config file. When configPath is empty
+// the projects come from the "projects" key of the whiterose configuration. Errors carry the
+// exitcode.Config exit status.
 func (s *UpdateService) LoadUpdateConfig(configPath string) ([]entities.UpdateProject, error) {
+	projects, err := s.loadUpdateConfig(configPath)
+	if err != nil {
+		return nil, exitcode.Wrap(exitcode.Config, err)
+	}
+	return projects, nil
+}
+
+func (s *UpdateService) loadUpdateConfig(configPath string) ([]entities.UpdateProject, error) {
+	if configPath == "" {
+		cfg, err := config.Load()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config: %w", err)
+		}
+		return cfg.Projects, nil
+	}
+
 	data, err := os.ReadFile(configPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config: %w", err)
 	}
+	if data, err = config.InterpolateYAML(data); err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
 
 	return entities.ParseUpdateConfig(data)
 }
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -311,6 +312,34 @@ func TestUpdateService_LoadUpdateConfig(t *testing.T) {
 	}
 }
 
+func TestUpdateService_LoadUpdateConfig_ExpandsPaths(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("PROJECTS_DIR", "/srv")
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := `projects:
+  - name: home-project
+    path: ~/projects/another-project
+  - name: env-project
+    path: ${PROJECTS_DIR}/api
+`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to create config: %v", err)
+	}
+
+	projects, err := New().LoadUpdateConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadUpdateConfig() error = %v", err)
+	}
+	if want := filepath.Join(home, "projects/another-project"); projects[0].Path != want {
+		t.Errorf("Path = %q, want %q", projects[0].Path, want)
+	}
+	if projects[1].Path != "/srv/api" {
+		t.Errorf("Path = %q, want /srv/api", projects[1].Path)
+	}
+}
+
 func TestVersionChecker_UpdatePackages_NoUpdates(t *testing.T) {
 	vc := NewVersionChecker().WithExecutor(&mocks.MockCommandExecutor{
 		RunFunc: func(cmd string, args ...string) (string, error) {
@@ -731,3 +760,76 @@ func TestVersionChecker_ListGoVersions_InvalidJSON(t *testing.T) {
 		t.Error("expected error for invalid JSON")
 	}
 }
+
+func TestParsePackageUpdates(t *testing.T) {
+	out := `github.com/fabianoflorentino/whiterose
+github.com/foo v1.0.0
+github.com/bar v2.0.0 [v2.1.0]
+golang.org/x/net v0.21.0 [v0.22.0] (retracted)
+go: downloading example.com/x v1.0.0 [v1.1.0]`
+
+	want := PackageUpdates{
+		{Name: "github.com/bar", CurrentVersion: "v2.0.0", LatestVersion: "v2.1.0"},
+		{Name: "golang.org/x/net", CurrentVersion: "v0.21.0", LatestVersion: "v0.22.0"},
+	}
+	if got := ParsePackageUpdates(out); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParsePackageUpdates() = %+v, want %+v", got, want)
+	}
+	if got := ParsePackageUpdates(""); got == nil || len(got) != 0 {
+		t.Errorf("ParsePackageUpdates(\"\") = %#v, want an empty non-nil list", got)
+	}
+}
+
+func TestVersionChecker_FetchGoVersions(t *testing.T) {
+	vc := NewVersionChecker().WithHTTP(&mocks.MockHTTPClient{
+		GetFunc: func(url string) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: 200,
+				Body: io.NopCloser(strings.NewReader(`[
+					{"version": "go1.24.6", "stable": true},
+					{"version": "go1.25.0", "stable": true},
+					{"version": "go1.26rc1", "stable": false},
+					{"version": "go1.23.0", "stable": false}
+				]`)),
+			}, nil
+		},
+	})
+
+	got, err := vc.FetchGoVersions()
+	if err != nil {
+		t.Fatalf("FetchGoVersions() error = %v", err)
+	}
+	want := GoVersions{Stable: []string{"go1.25.0", "go1.24.6"}, Unstable: []string{"go1.26rc1"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FetchGoVersions() = %+v, want %+v", got, want)
+	}
+	if rows := got.Table().Rows; len(rows) != 3 || rows[2][1] != "unstable" {
+		t.Errorf("Table().Rows = %v", rows)
+	}
+}
+
+func TestVersionChecker_FetchDockerTags(t *testing.T) {
+	vc := NewVersionChecker().WithHTTP(&mocks.MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(strings.NewReader(`{"results": [{"name": "1.25"}, {"name": "1.25.1"}, {"name": "2.0.0"}, {"name": "1.24.0"}]}`)),
+			}, nil
+		},
+	})
+
+	got, err := vc.FetchDockerTags("golang:1.25")
+	if err != nil {
+		t.Fatalf("FetchDockerTags() error = %v", err)
+	}
+	want := ImageTags{Image: "golang", Current: "1.25", Tags: []string{"1.25.1", "1.25", "1.24.0"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FetchDockerTags() = %+v, want %+v", got, want)
+	}
+
+	var out strings.Builder
+	got.Text(&out)
+	if !strings.Contains(out.String(), "  1.25 (current)\n") {
+		t.Errorf("Text() =\n%s", out.String())
+	}
+}
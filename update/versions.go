@@ -9,7 +9,12 @@ import (
 	"path/filepath"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+
+	"github.com/fabianoflorentino/whiterose/exitcode"
+	"github.com/fabianoflorentino/whiterose/internal/interfaces"
+	"github.com/fabianoflorentino/whiterose/output"
 )
 
 type HTTPClient interface {
@@ -53,18 +58,24 @@ func (vc *VersionChecker) WithExecutor(exec CommandExecutor) *VersionChecker {
 	}
 }
 
-func (vc *VersionChecker) ListGoVersions() error {
-	fmt.Println("Fetching Go versions...")
+// GoVersions are the Go releases published on go.dev, newest first: at most ten stable releases
+// and five betas or release candidates.
+type GoVersions struct {
+	Stable   []string `json:"stable"`
+	Unstable []string `json:"unstable"`
+}
 
+// FetchGoVersions returns the Go releases published on go.dev.
+func (vc *VersionChecker) FetchGoVersions() (GoVersions, error) {
 	resp, err := vc.httpClient.Get("https://go.dev/dl/?mode=json")
 	if err != nil {
-		return fmt.Errorf("failed to fetch Go versions: %w", err)
+		return GoVersions{}, exitcode.Errorf(exitcode.External, "failed to fetch Go versions: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
+		return GoVersions{}, exitcode.Errorf(exitcode.External, "failed to read response: %w", err)
 	}
 
 	var versions []struct {
@@ -73,12 +84,11 @@ func (vc *VersionChecker) ListGoVersions() error {
 	}
 
 	if err := json.Unmarshal(body, &versions); err != nil {
-		return fmt.Errorf("failed to parse JSON: %w", err)
+		return GoVersions{}, exitcode.Errorf(exitcode.External, "failed to parse JSON: %w", err)
 	}
 
-	fmt.Println("\nAvailable Go versions:")
-	var stable []string
-	var unstable []string
+	stable := []string{}
+	unstable := []string{}
 
 	for _, v := range versions {
 		if v.Stable {
@@ -89,19 +99,48 @@ func (vc *VersionChecker) ListGoVersions() error {
 	}
 
 	sort.Sort(sort.Reverse(sort.StringSlice(stable)))
-	for _, v := range stable[:min(10, len(stable))] {
-		fmt.Printf("  %s (stable)\n", v)
+	sort.Sort(sort.Reverse(sort.StringSlice(unstable)))
+
+	return GoVersions{Stable: stable[:min(10, len(stable))], Unstable: unstable[:min(5, len(unstable))]}, nil
+}
+
+func (vc *VersionChecker) ListGoVersions() error {
+	fmt.Println("Fetching Go versions...")
+
+	versions, err := vc.FetchGoVersions()
+	if err != nil {
+		return err
+	}
+
+	versions.Text(os.Stdout)
+	return nil
+}
+
+// Text writes the stable releases followed by the unstable ones.
+func (g GoVersions) Text(w io.Writer) {
+	fmt.Fprintln(w, "\nAvailable Go versions:")
+	for _, v := range g.Stable {
+		fmt.Fprintf(w, "  %s (stable)\n", v)
 	}
 
-	if len(unstable) > 0 {
-		fmt.Println("\nUnstable versions:")
-		sort.Sort(sort.Reverse(sort.StringSlice(unstable)))
-		for _, v := range unstable[:min(5, len(unstable))] {
-			fmt.Printf("  %s\n", v)
+	if len(g.Unstable) > 0 {
+		fmt.Fprintln(w, "\nUnstable versions:")
+		for _, v := range g.Unstable {
+			fmt.Fprintf(w, "  %s\n", v)
 		}
 	}
+}
 
-	return nil
+// Table lays the releases out one per row.
+func (g GoVersions) Table() output.Table {
+	t := output.Table{Header: []string{"VERSION", "CHANNEL"}}
+	for _, v := range g.Stable {
+		t.Rows = append(t.Rows, []string{v, "stable"})
+	}
+	for _, v := range g.Unstable {
+		t.Rows = append(t.Rows, []string{v, "unstable"})
+	}
+	return t
 }
 
 func (vc *VersionChecker) GetCurrentGoVersion(goModPath string) string {
@@ -115,32 +154,72 @@ func (vc *VersionChecker) GetCurrentGoVersion(goModPath string) string {
 	return ""
 }
 
+// PackageUpdates are the dependencies of a module that have a newer version available.
+type PackageUpdates []interfaces.PackageUpdate
+
+// FetchGoLibUpdates returns the dependencies with a newer version, as reported by go list -m -u all.
+func (vc *VersionChecker) FetchGoLibUpdates(goModPath string) (PackageUpdates, error) {
+	out, err := vc.executor.Run("go", "list", "-m", "-u", "all")
+	if err != nil {
+		return nil, exitcode.Errorf(exitcode.External, "failed to list updates: %w", err)
+	}
+
+	return ParsePackageUpdates(out), nil
+}
+
 func (vc *VersionChecker) ListGoLibUpdates(goModPath string) error {
 	fmt.Println("Checking for library updates...")
 
-	out, err := vc.executor.Run("go", "list", "-m", "-u", "all")
+	updates, err := vc.FetchGoLibUpdates(goModPath)
 	if err != nil {
-		return fmt.Errorf("failed to list updates: %w", err)
+		return err
 	}
 
-	fmt.Println("\nAvailable updates:")
-	lines := strings.Split(string(out), "\n")
-	hasUpdates := false
+	updates.Text(os.Stdout)
+	return nil
+}
 
-	for _, line := range lines {
-		if strings.HasSuffix(line, "(latest)") || strings.Contains(line, "->") {
-			if !strings.HasPrefix(line, "go:") {
-				fmt.Printf("  %s\n", line)
-				hasUpdates = true
-			}
+// ParsePackageUpdates extracts the modules with a newer version from the output of
+// go list -m -u all, whose lines read "path current [latest]".
+func ParsePackageUpdates(out string) PackageUpdates {
+	updates := PackageUpdates{}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || strings.HasPrefix(line, "go:") {
+			continue
 		}
+		latest := fields[2]
+		if !strings.HasPrefix(latest, "[") || !strings.HasSuffix(latest, "]") {
+			continue
+		}
+		updates = append(updates, interfaces.PackageUpdate{
+			Name:           fields[0],
+			CurrentVersion: fields[1],
+			LatestVersion:  strings.Trim(latest, "[]"),
+		})
 	}
+	return updates
+}
 
-	if !hasUpdates {
-		fmt.Println("  All dependencies are up to date")
+// Text writes one "name current -> latest" line per update.
+func (p PackageUpdates) Text(w io.Writer) {
+	fmt.Fprintln(w, "\nAvailable updates:")
+	if len(p) == 0 {
+		fmt.Fprintln(w, "  All dependencies are up to date")
+		return
+	}
+	for _, u := range p {
+		fmt.Fprintf(w, "  %s %s -> %s\n", u.Name, u.CurrentVersion, u.LatestVersion)
 	}
+}
 
-	return nil
+// Table lays the updates out one package per row.
+func (p PackageUpdates) Table() output.Table {
+	t := output.Table{Header: []string{"PACKAGE", "CURRENT", "LATEST"}}
+	for _, u := range p {
+		t.Rows = append(t.Rows, []string{u.Name, u.CurrentVersion, u.LatestVersion})
+	}
+	return t
 }
 
 func (vc *VersionChecker) UpdatePackages(goModPath string, strategy string, dryRun bool) error {
@@ -155,9 +234,9 @@ func (vc *VersionChecker) UpdatePackages(goModPath string, strategy string, dryR
 	}
 	if err != nil {
 		if dryRun {
-			return fmt.Errorf("dry-run failed: %w\n%s", err, out)
+			return exitcode.Errorf(exitcode.External, "dry-run failed: %w\n%s", err, out)
 		}
-		return fmt.Errorf("failed to update packages: %w\n%s", err, out)
+		return exitcode.Errorf(exitcode.External, "failed to update packages: %w\n%s", err, out)
 	}
 
 	if dryRun {
@@ -183,7 +262,7 @@ func (vc *VersionChecker) UpdatePackages(goModPath string, strategy string, dryR
 		fmt.Println("\nRunning go mod tidy...")
 		tidyOut, tidyErr := vc.executor.Run("go", "mod", "tidy")
 		if tidyErr != nil {
-			return fmt.Errorf("go mod tidy failed: %w\n%s", tidyErr, tidyOut)
+			return exitcode.Errorf(exitcode.External, "go mod tidy failed: %w\n%s", tidyErr, tidyOut)
 		}
 		fmt.Println("  Done.")
 	}
@@ -191,12 +270,19 @@ func (vc *VersionChecker) UpdatePackages(goModPath string, strategy string, dryR
 	return nil
 }
 
-func (vc *VersionChecker) ListDockerUpdates(imageName string) error {
-	fmt.Printf("Fetching Docker image versions for %s...\n", imageName)
+// ImageTags are the most recent tags of a Docker image, restricted to the major version of
+// its current tag when the registry publishes any.
+type ImageTags struct {
+	Image   string   `json:"image"`
+	Current string   `json:"current"`
+	Tags    []string `json:"tags"`
+}
 
+// FetchDockerTags returns the tags available for imageName, given as name:tag.
+func (vc *VersionChecker) FetchDockerTags(imageName string) (ImageTags, error) {
 	parts := strings.Split(imageName, ":")
 	if len(parts) != 2 {
-		return fmt.Errorf("invalid image format, use name:tag (e.g., golang:1.25)")
+		return ImageTags{}, exitcode.Errorf(exitcode.Config, "invalid image format, use name:tag (e.g., golang:1.25)")
 	}
 
 	image := parts[0]
@@ -204,13 +290,12 @@ func (vc *VersionChecker) ListDockerUpdates(imageName string) error {
 
 	tags, err := vc.fetchDockerHubTags(image)
 	if err != nil {
-		return fmt.Errorf("failed to fetch tags: %w", err)
+		return ImageTags{}, exitcode.Errorf(exitcode.External, "failed to fetch tags: %w", err)
 	}
 
-	fmt.Printf("\nAvailable versions for %s:\n", image)
 	currentMajor := vc.extractMajorVersion(tag)
 
-	var majorTags []string
+	majorTags := []string{}
 	for _, t := range tags {
 		if strings.HasPrefix(t, currentMajor+".") {
 			majorTags = append(majorTags, t)
@@ -218,19 +303,45 @@ func (vc *VersionChecker) ListDockerUpdates(imageName string) error {
 	}
 
 	if len(majorTags) == 0 {
-		majorTags = tags[:min(10, len(tags))]
+		majorTags = append(majorTags, tags[:min(10, len(tags))]...)
 	}
 
 	sort.Sort(sort.Reverse(sort.StringSlice(majorTags)))
-	for _, t := range majorTags[:min(10, len(majorTags))] {
+
+	return ImageTags{Image: image, Current: tag, Tags: majorTags[:min(10, len(majorTags))]}, nil
+}
+
+func (vc *VersionChecker) ListDockerUpdates(imageName string) error {
+	fmt.Printf("Fetching Docker image versions for %s...\n", imageName)
+
+	tags, err := vc.FetchDockerTags(imageName)
+	if err != nil {
+		return err
+	}
+
+	tags.Text(os.Stdout)
+	return nil
+}
+
+// Text writes the tags, marking the current one.
+func (i ImageTags) Text(w io.Writer) {
+	fmt.Fprintf(w, "\nAvailable versions for %s:\n", i.Image)
+	for _, t := range i.Tags {
 		marker := ""
-		if t == tag {
+		if t == i.Current {
 			marker = " (current)"
 		}
-		fmt.Printf("  %s%s\n", t, marker)
+		fmt.Fprintf(w, "  %s%s\n", t, marker)
 	}
+}
 
-	return nil
+// Table lays the tags out one per row.
+func (i ImageTags) Table() output.Table {
+	t := output.Table{Header: []string{"IMAGE", "TAG", "CURRENT"}}
+	for _, tag := range i.Tags {
+		t.Rows = append(t.Rows, []string{i.Image, tag, strconv.FormatBool(tag == i.Current)})
+	}
+	return t
 }
 
 func (vc *VersionChecker) fetchDockerHubTags(image string) ([]string, error) {
@@ -6,8 +6,11 @@
 //   - repoFile: Contains a reference URL for usage instructions.
 //
 // Types:
-//   - RepoInfo: Represents a repository with its URL and local directory.
-//   - ConfigFile: Represents the configuration file structure containing a list of repositories.
+//   - RepoInfo: Represents a repository with its URL, local directory and optional clone settings.
+//   - RepoAuth: Represents the per-repository authentication settings.
+//   - HostCredential: Represents the HTTPS username and token used for a Git host.
+//   - ConfigFile: Represents the configuration file structure containing a list of repositories,
+//     applications, the number of concurrent clone workers and per-host credentials.
 //
 // Functions:
 //   - FetchReposFromJSON(file string) ([]RepoInfo, error):
@@ -26,26 +29,100 @@ import (
 
 const (
 	repoFile string = `
+run "whiterose config init" to create it, or see
 https://github.com/fabianoflorentino/whiterose/blob/main/README.md#usage
 `
 )
 
+// RepoInfo describes a repository entry of the configuration file. Only url and directory are
+// required; the remaining fields override the default clone behaviour for that repository.
 type RepoInfo struct {
-	URL       string `json:"url" yaml:"url"`
-	Directory string `json:"directory" yaml:"directory"`
+	URL                  string    `json:"url" yaml:"url" jsonschema:"required,format=git-url"`
+	Directory            string    `json:"directory" yaml:"directory" jsonschema:"required"`
+	Branch               string    `json:"branch,omitempty" yaml:"branch,omitempty"`
+	FallbackBranch       string    `json:"fallbackBranch,omitempty" yaml:"fallbackBranch,omitempty"`
+	CreateBranchTemplate string    `json:"createBranchTemplate,omitempty" yaml:"createBranchTemplate,omitempty"`
+	Depth                int       `json:"depth,omitempty" yaml:"depth,omitempty"`
+	SingleBranch         bool      `json:"singleBranch,omitempty" yaml:"singleBranch,omitempty"`
+	Submodules           bool      `json:"submodules,omitempty" yaml:"submodules,omitempty"`
+	Tags                 []string  `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Groups               []string  `json:"groups,omitempty" yaml:"groups,omitempty"`
+	Auth                 *RepoAuth `json:"auth,omitempty" yaml:"auth,omitempty"`
 }
 
+// RepoAuth selects how a repository is authenticated. Method is "ssh" or "https"; when empty it
+// is derived from the URL. CredentialRef names the credentials to use for HTTPS: the
+// <REF>_USER and <REF>_TOKEN environment variables replace GIT_USER and GIT_TOKEN.
+type RepoAuth struct {
+	Method        string `json:"method,omitempty" yaml:"method,omitempty" jsonschema:"enum=ssh|https"`
+	CredentialRef string `json:"credentialRef,omitempty" yaml:"credentialRef,omitempty"`
+	SSHKeyPath    string `json:"sshKeyPath,omitempty" yaml:"sshKeyPath,omitempty"`
+}
+
+// AppInfo is an application checked by pre-req. RecommendedVersion is a version constraint such
+// as ">=1.25" or "~2.40", and VersionRegex a regular expression that finds the version in the
+// output of the version command when the first dotted number is not it. Timeout bounds the
+// version command, as a duration such as "5s". DependsOn names the applications, by name or
+// command, that must be ready before this one is checked. Catalog is the id of the built-in
+// catalog entry the application is based on; the fields it sets override those of the entry.
 type AppInfo struct {
-	Name                string            `json:"name" yaml:"name"`
-	Command             string            `json:"command" yaml:"command"`
-	VersionFlag         string            `json:"versionFlag" yaml:"versionFlag"`
-	RecommendedVersion  string            `json:"recommendedVersion" yaml:"recommendedVersion"`
-	InstallInstructions map[string]string `json:"installInstructions" yaml:"installInstructions"`
+	Catalog             string            `json:"catalog,omitempty" yaml:"catalog,omitempty" jsonschema:"format=catalog-id"`
+	Name                string            `json:"name,omitempty" yaml:"name,omitempty"`
+	Command             string            `json:"command,omitempty" yaml:"command,omitempty"`
+	VersionFlag         string            `json:"versionFlag,omitempty" yaml:"versionFlag,omitempty"`
+	RecommendedVersion  string            `json:"recommendedVersion,omitempty" yaml:"recommendedVersion,omitempty" jsonschema:"format=version-constraint"`
+	VersionRegex        string            `json:"versionRegex,omitempty" yaml:"versionRegex,omitempty" jsonschema:"format=regex"`
+	Timeout             string            `json:"timeout,omitempty" yaml:"timeout,omitempty" jsonschema:"format=duration"`
+	DependsOn           []string          `json:"dependsOn,omitempty" yaml:"dependsOn,omitempty"`
+	HealthCheck         *HealthCheck      `json:"healthCheck,omitempty" yaml:"healthCheck,omitempty"`
+	InstallInstructions map[string]string `json:"installInstructions,omitempty" yaml:"installInstructions,omitempty"`
+	Install             *AppInstall       `json:"install,omitempty" yaml:"install,omitempty"`
+}
+
+// HealthCheck is a command telling whether an installed application works, such as
+// "docker info" or "gh auth status". It passes when the command succeeds and, when Expect is
+// set, its output on stdout and stderr matches the Expect regular expression.
+type HealthCheck struct {
+	Command string   `json:"command" yaml:"command" jsonschema:"required"`
+	Args    []string `json:"args,omitempty" yaml:"args,omitempty"`
+	Expect  string   `json:"expect,omitempty" yaml:"expect,omitempty" jsonschema:"format=regex"`
+}
+
+// AppInstall names the package of an application for each package manager setup can install
+// it with. Version is the version installed by asdf and mise, "latest" when empty.
+type AppInstall struct {
+	Version string `json:"version,omitempty" yaml:"version,omitempty"`
+	Apt     string `json:"apt,omitempty" yaml:"apt,omitempty"`
+	Dnf     string `json:"dnf,omitempty" yaml:"dnf,omitempty"`
+	Pacman  string `json:"pacman,omitempty" yaml:"pacman,omitempty"`
+	Brew    string `json:"brew,omitempty" yaml:"brew,omitempty"`
+	Asdf    string `json:"asdf,omitempty" yaml:"asdf,omitempty"`
+	Mise    string `json:"mise,omitempty" yaml:"mise,omitempty"`
+	// Tarball are the release archives to download, by platform such as "linux/amd64".
+	Tarball map[string]Tarball `json:"tarball,omitempty" yaml:"tarball,omitempty"`
+}
+
+// Tarball is a release archive, a .tar.gz, .tgz or .zip file or a bare executable, verified
+// against its SHA-256 checksum before the executable is installed.
+type Tarball struct {
+	URL    string `json:"url" yaml:"url" jsonschema:"required"`
+	SHA256 string `json:"sha256" yaml:"sha256" jsonschema:"required"`
+	// Binary is the path of the executable in the archive, the command of the application by default.
+	Binary string `json:"binary,omitempty" yaml:"binary,omitempty"`
+}
+
+// HostCredential holds the HTTPS credentials of a Git host. Username may be omitted for
+// forges that accept any user name alongside a token.
+type HostCredential struct {
+	Username string `json:"username,omitempty" yaml:"username,omitempty"`
+	Token    string `json:"token" yaml:"token" jsonschema:"required"`
 }
 
 type ConfigFile struct {
-	Repositories []RepoInfo `json:"repositories" yaml:"repositories"`
-	Applications []AppInfo  `json:"applications" yaml:"applications"`
+	Repositories []RepoInfo                `json:"repositories" yaml:"repositories"`
+	Applications []AppInfo                 `json:"applications" yaml:"applications"`
+	Workers      int                       `json:"workers,omitempty" yaml:"workers,omitempty"`
+	Credentials  map[string]HostCredential `json:"credentials,omitempty" yaml:"credentials,omitempty"`
 }
 
 // FetchRepositories reads a JSON file specified by 'file', decodes its contents into a ConfigFile struct,
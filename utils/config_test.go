@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	"gopkg.in/yaml.v3"
@@ -102,7 +103,7 @@ func TestFetchRepositories_JSONSuccess(t *testing.T) {
 		t.Fatalf("expected %d repos, got %d", len(repos), len(got))
 	}
 	for i, repo := range repos {
-		if got[i] != repo {
+		if !reflect.DeepEqual(got[i], repo) {
 			t.Errorf("expected repo %v, got %v", repo, got[i])
 		}
 	}
@@ -123,7 +124,7 @@ func TestFetchRepositories_YAMLSuccess(t *testing.T) {
 		t.Fatalf("expected %d repos, got %d", len(repos), len(got))
 	}
 	for i, repo := range repos {
-		if got[i] != repo {
+		if !reflect.DeepEqual(got[i], repo) {
 			t.Errorf("expected repo %v, got %v", repo, got[i])
 		}
 	}
@@ -144,7 +145,7 @@ func TestFetchRepositories_YMLSuccess(t *testing.T) {
 		t.Fatalf("expected %d repos, got %d", len(repos), len(got))
 	}
 	for i, repo := range repos {
-		if got[i] != repo {
+		if !reflect.DeepEqual(got[i], repo) {
 			t.Errorf("expected repo %v, got %v", repo, got[i])
 		}
 	}
@@ -1,14 +1,12 @@
 // LoadDotEnv loads environment variables from a .env file located in the user's home directory.
 // It uses the github.com/joho/godotenv package to parse the file. If the .env file cannot be loaded,
-// the function logs a fatal error message including a reference to the environment variables documentation
-// and exits the program with status code 125. Returns an error if loading fails.
+// the returned error includes a reference to the environment variables documentation.
 //
 // See: https://github.com/fabianoflorentino/whiterose/blob/main/README.md#environment-variables
 package utils
 
 import (
 	"fmt"
-	"log"
 	"os"
 	"strings"
 
@@ -21,8 +19,7 @@ https://github.com/fabianoflorentino/whiterose/blob/main/README.md#environment-v
 
 // LoadDotEnv loads environment variables from a .env file located in the user's home directory.
 // It uses the github.com/joho/godotenv package to parse the file. If the .env file cannot be loaded,
-// the function logs a fatal error message including a reference to the environment variables documentation
-// and exits the program with status code 125. Returns an error if loading fails.
+// the returned error includes a reference to the environment variables documentation.
 func LoadDotEnv() error {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
@@ -30,8 +27,7 @@ func LoadDotEnv() error {
 	}
 
 	if err := godotenv.Load(homeDir + "/.env"); err != nil {
-		log.Fatalf("failed to load .env file: %v, %s", err, envVars)
-		os.Exit(125)
+		return fmt.Errorf("failed to load .env file: %w, see %s", err, strings.TrimSpace(envVars))
 	}
 
 	return nil
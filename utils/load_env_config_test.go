@@ -1,6 +1,9 @@
 package utils
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -11,3 +14,26 @@ func TestFileExistsInHomeDir(t *testing.T) {
 func TestGetFilePathInHomeDir(t *testing.T) {
 	t.Skip("TestGetFilePathInHomeDir is not suitable for unit tests")
 }
+
+func TestLoadDotEnv(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	if err := LoadDotEnv(); err == nil || !strings.Contains(err.Error(), "README.md#environment-variables") {
+		t.Errorf("LoadDotEnv() error = %v, want an error pointing to the documentation", err)
+	}
+
+	t.Setenv("WHITEROSE_DOTENV_TEST", "")
+	if err := os.WriteFile(filepath.Join(home, ".env"), []byte("WHITEROSE_DOTENV_TEST=loaded\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Unsetenv("WHITEROSE_DOTENV_TEST"); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadDotEnv(); err != nil {
+		t.Fatalf("LoadDotEnv() error = %v", err)
+	}
+	if got := os.Getenv("WHITEROSE_DOTENV_TEST"); got != "loaded" {
+		t.Errorf("WHITEROSE_DOTENV_TEST = %q, want loaded", got)
+	}
+}